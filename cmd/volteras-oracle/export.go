@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/DIMO-Network/shared/pkg/db"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/DIMO-Network/volteras-oracle/internal/controllers"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/google/subcommands"
+	"github.com/rs/zerolog"
+	"io"
+	"os"
+)
+
+type exportCmd struct {
+	logger   zerolog.Logger
+	settings config.Settings
+	pdb      db.Store
+
+	format string
+	status string
+	phase  string
+	from   string
+	to     string
+	out    string
+}
+
+func (*exportCmd) Name() string { return "export" }
+func (*exportCmd) Synopsis() string {
+	return "export vehicles with their onboarding and telemetry state"
+}
+func (*exportCmd) Usage() string {
+	return `export [-format csv|ndjson] [-status status | -phase phase] [-from date] [-to date] [-out file]:
+	streams every VIN with token IDs, synthetic device, owner, statuses, errors, last status change and telemetry
+	freshness. Dates are RFC3339 and filter on the last status change. Writes to stdout unless -out is set.
+  `
+}
+
+func (p *exportCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.format, "format", controllers.ExportFormatCSV, "csv or ndjson")
+	f.StringVar(&p.status, "status", "", "onboarding status")
+	f.StringVar(&p.phase, "phase", "", "onboarding phase: verification, mint, disconnect or delete")
+	f.StringVar(&p.from, "from", "", "last status change from, RFC3339")
+	f.StringVar(&p.to, "to", "", "last status change before, RFC3339")
+	f.StringVar(&p.out, "out", "", "output file")
+}
+
+func (p *exportCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	// the export may be written to stdout
+	logger := p.logger.Output(os.Stderr)
+
+	filter, err := controllers.ParseVehicleExportFilter(p.status, p.phase, p.from, p.to)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return subcommands.ExitUsageError
+	}
+
+	var out io.Writer = os.Stdout
+	if p.out != "" {
+		file, err := os.Create(p.out)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to create export file")
+			return subcommands.ExitFailure
+		}
+		defer file.Close() // nolint
+		out = file
+	}
+
+	buffered := bufio.NewWriter(out)
+	writer, err := controllers.NewVehicleExportWriter(p.format, buffered)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return subcommands.ExitUsageError
+	}
+
+	walletService := service.NewSDWalletsService(ctx, logger, p.settings)
+	vehicleService := service.NewVehicleService(&p.pdb, &logger)
+	if err := controllers.WriteVehicleExport(ctx, vehicleService, walletService, filter, writer); err != nil {
+		logger.Error().Err(err).Msg("failed to export vehicles")
+		// keeps the summary which tells the export is not complete
+		_ = buffered.Flush()
+		return subcommands.ExitFailure
+	}
+	if err := buffered.Flush(); err != nil {
+		logger.Error().Err(err).Msg("failed to write export")
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/DIMO-Network/go-transactions"
@@ -9,23 +10,29 @@ import (
 	ssetings "github.com/DIMO-Network/shared/pkg/settings"
 	"github.com/DIMO-Network/volteras-oracle/internal/app"
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/DIMO-Network/volteras-oracle/internal/controllers"
 	"github.com/DIMO-Network/volteras-oracle/internal/kafka"
 	"github.com/DIMO-Network/volteras-oracle/internal/models"
 	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
 	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/google/subcommands"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/riverqueue/river"
 	"github.com/riverqueue/river/riverdriver/riverpgxv5"
+	"github.com/riverqueue/river/rivertype"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
+	"time"
 )
 
 func main() {
@@ -61,11 +68,22 @@ func main() {
 	if len(os.Args) > 1 {
 		// CLI only mode
 		subcommands.Register(&migrateDBCmd{logger: logger, settings: settings, pdb: pdb}, "database")
+		subcommands.Register(&exportCmd{logger: logger, settings: settings, pdb: pdb}, "database")
 
 		flag.Parse()
 		os.Exit(int(subcommands.Execute(ctx)))
 	}
 
+	shutdownTracing, err := tracing.Setup(ctx, &settings)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error().Err(err).Msg("failed to flush traces")
+		}
+	}()
+
 	transactionsClient, err := onboarding.NewTransactionsClient(&settings)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create transactions client")
@@ -76,28 +94,68 @@ func main() {
 		logger.Fatal().Err(err).Msg("Failed to create SD Wallets service")
 	}
 
-	monApp := createMonitoringServer()
+	prometheus.MustRegister(onboarding.NewVinStatusCollector(&pdb, logger))
+
+	healthChecker := service.NewHealthChecker(time.Duration(settings.HealthCheckCacheSeconds) * time.Second)
+	monApp := createMonitoringServer(healthChecker)
 	group, gCtx := errgroup.WithContext(ctx)
 	vehicleService := service.NewVehicleService(&pdb, &logger)
 	identityService := service.NewIdentityAPIService(logger, settings)
 	deviceDefinitionsService := service.NewDeviceDefinitionsAPIService(logger, settings)
-	oracleService, err := service.NewOracleService(ctx, logger, settings, vehicleService)
+	telemetryStatusTracker := service.NewTelemetryStatusTracker(&pdb, logger, settings)
+	oracleService, err := service.NewOracleService(ctx, logger, settings, vehicleService, telemetryStatusTracker)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to create Oracle service")
 	}
 
 	enrollmentChannel := make(chan models.OperationMessage, 100)
 	vendorOnboardingService := onboarding.NewExternalOnboardingService(&settings, vehicleService, &logger, enrollmentChannel)
-
-	riverClient, _, dbPool, err := createRiverClientWithWorkersAndPool(gCtx, logger, &settings, identityService, deviceDefinitionsService, oracleService, &pdb, transactionsClient, walletService, vendorOnboardingService)
+	dbPool, err := pgxpool.New(gCtx, settings.DB.BuildConnectionString(true))
 	if err != nil {
-		logger.Fatal().Err(err).Msg("failed to create river client, workers and db pool")
+		logger.Fatal().Err(err).Msg("failed to connect to database")
 	}
 	defer dbPool.Close()
+	logger.Debug().Msg("DB pool for workers created")
+
+	webhooksService := service.NewWebhooksService(&pdb, dbPool, &logger, time.Duration(settings.WebhookTimeoutSeconds)*time.Second)
+	idempotencyKeysService := service.NewIdempotencyKeysService(&pdb, &logger, time.Duration(settings.IdempotencyKeyLeaseSeconds)*time.Second)
+
+	riverClient, _, err := createRiverClientWithWorkers(logger, &settings, dbPool, identityService, deviceDefinitionsService, oracleService, &pdb, transactionsClient, walletService, vendorOnboardingService, webhooksService, idempotencyKeysService, vehicleService)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create river client and workers")
+	}
 
 	runRiver(gCtx, logger, riverClient, group)
 
-	webAPI := app.App(&settings, &logger, vehicleService, riverClient, walletService, transactionsClient)
+	// liveness components only recover with a restart, the others fail readiness until they are reachable again
+	healthChecker.Register("river", true, riverStoppedHealthCheck(riverClient))
+	healthChecker.Register("riverQueue", false, riverQueueHealthCheck(riverClient))
+	healthChecker.Register("database", false, service.DBHealthCheck(&pdb))
+	// identity-api and the Dimo Node only degrade the routes which need them, they don't take the oracle out of service
+	healthChecker.Register("identityApi", false, service.WarningHealthCheck(service.HTTPHealthCheck(&http.Client{}, http.MethodGet, settings.IdentityAPIEndpoint)))
+	healthChecker.Register("dimoNode", false, service.WarningHealthCheck(service.DimoNodeHealthCheck(logger, settings)))
+	healthChecker.Register("certificate", false, service.CertificateHealthCheck(settings.Cert, time.Duration(settings.CertExpiryWarningDays)*24*time.Hour))
+
+	group.Go(func() error {
+		return telemetryStatusTracker.Run(gCtx)
+	})
+
+	// synthetic device addresses of VINs minted before they were stored, searches fill them too if this fails
+	go func() {
+		filled, err := vehicleService.BackfillSyntheticDeviceAddresses(gCtx, walletService)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to backfill synthetic device addresses")
+			return
+		}
+		logger.Info().Int("vins", filled).Msg("Synthetic device addresses backfilled")
+	}()
+
+	vinEventsHub := service.NewVinEventsHub(dbPool, logger)
+	group.Go(func() error {
+		return vinEventsHub.Run(gCtx)
+	})
+
+	webAPI := app.App(&settings, &logger, vehicleService, riverClient, walletService, transactionsClient, vinEventsHub, webhooksService, idempotencyKeysService, dbPool)
 
 	// start the Web Api
 	logger.Info().Str("port", settings.MonitoringPort).Msgf("Starting monitoring server %s", settings.MonitoringPort)
@@ -109,7 +167,7 @@ func main() {
 
 	if settings.IsTelemetryConsumerEnabled {
 		// Setup consumer for UnbufferedTelemetryTopic
-		err = kafka.SetupKafkaConsumer(
+		consumerStatus, err := kafka.SetupKafkaConsumer(
 			ctx,
 			&logger,
 			kafkaBrokers,
@@ -120,21 +178,31 @@ func main() {
 		if err != nil {
 			logger.Fatal().Err(err).Msg("Failed to setup consumer for UnbufferedTelemetryTopic")
 		}
+		healthChecker.Register("kafka:"+settings.UnbufferedTelemetryConsumerGroup, false, consumerStatus.HealthCheck)
+		healthChecker.Register("kafkaStopped:"+settings.UnbufferedTelemetryConsumerGroup, true, consumerStatus.StoppedHealthCheck)
 	}
 
 	if settings.IsOperationsConsumerEnabled {
 		// Setup consumer for OperationsTopic
-		err = kafka.SetupKafkaConsumer(
+		consumerStatus, err := kafka.SetupKafkaConsumer(
 			ctx,
 			&logger,
 			kafkaBrokers,
 			settings.OperationsTopic,
 			settings.OperationsConsumerGroup,
-			kafka.MessageHandlerOperations{Logger: &logger, OracleService: oracleService, EnrollmentChannel: enrollmentChannel},
+			kafka.MessageHandlerOperations{
+				Logger:            &logger,
+				OracleService:     oracleService,
+				EnrollmentChannel: enrollmentChannel,
+				Webhooks:          webhooksService,
+				QueueWebhooks:     onboarding.QueueWebhookDeliveries(riverClient),
+			},
 		)
 		if err != nil {
 			logger.Fatal().Err(err).Msg("Failed to setup consumer for OperationsTopic")
 		}
+		healthChecker.Register("kafka:"+settings.OperationsConsumerGroup, false, consumerStatus.HealthCheck)
+		healthChecker.Register("kafkaStopped:"+settings.OperationsConsumerGroup, true, consumerStatus.StoppedHealthCheck)
 	}
 
 	if err = group.Wait(); err != nil {
@@ -159,73 +227,148 @@ func runFiber(ctx context.Context, fiberApp *fiber.App, addr string, group *errg
 	})
 }
 
-// createMonitoringServer meant for prometheus / openmetrics scraping.
-func createMonitoringServer() *fiber.App {
+// createMonitoringServer meant for prometheus / openmetrics scraping and the Kubernetes probes.
+func createMonitoringServer(healthChecker *service.HealthChecker) *fiber.App {
 	monApp := fiber.New(fiber.Config{DisableStartupMessage: true})
 
-	monApp.Get("/", func(_ *fiber.Ctx) error { return nil })
+	monApp.Get("/", controllers.Livez(healthChecker))
+	monApp.Get("/livez", controllers.Livez(healthChecker))
+	monApp.Get("/readyz", controllers.Readyz(healthChecker))
 	monApp.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
 
 	return monApp
 }
 
-// createRiverClientWithWorkersAndPool we use the river job client to orchestrate onboarding steps for a VIN
-func createRiverClientWithWorkersAndPool(ctx context.Context, logger zerolog.Logger, settings *config.Settings, identityService service.IdentityAPI, dd service.DeviceDefinitionsAPI, os *service.OracleService, dbs *db.Store, tr *transactions.Client, ws service.SDWalletsAPI, onboardingService onboarding.VendorOnboardingAPI) (*river.Client[pgx.Tx], *river.Workers, *pgxpool.Pool, error) {
+// createRiverClientWithWorkers we use the river job client to orchestrate onboarding steps for a VIN
+func createRiverClientWithWorkers(logger zerolog.Logger, settings *config.Settings, dbPool *pgxpool.Pool, identityService service.IdentityAPI, dd service.DeviceDefinitionsAPI, os *service.OracleService, dbs *db.Store, tr *transactions.Client, ws service.SDWalletsAPI, onboardingService onboarding.VendorOnboardingAPI, webhooks *service.Webhooks, idempotencyKeys *service.IdempotencyKeys, vs *service.Vehicle) (*river.Client[pgx.Tx], *river.Workers, error) {
 	workers := river.NewWorkers()
-	verifyWorker := onboarding.NewVerifyWorker(settings, logger, identityService, dd, os, dbs, onboardingService)
-	onboardingWorker := onboarding.NewOnboardingWorker(settings, logger, identityService, dbs, tr, ws, onboardingService)
-	disconnectWorker := onboarding.NewDisconnectWorker(settings, logger, identityService, dbs, tr, ws, onboardingService)
-	deleteWorker := onboarding.NewDeleteWorker(settings, logger, identityService, dbs, tr, ws, onboardingService)
+	verifyWorker := onboarding.NewVerifyWorker(settings, logger, identityService, dd, os, dbs, onboardingService, webhooks)
+	onboardingWorker := onboarding.NewOnboardingWorker(settings, logger, identityService, dbs, tr, ws, onboardingService, webhooks)
+	disconnectWorker := onboarding.NewDisconnectWorker(settings, logger, identityService, dbs, tr, ws, onboardingService, webhooks)
+	deleteWorker := onboarding.NewDeleteWorker(settings, logger, identityService, dbs, tr, ws, onboardingService, webhooks)
+	webhookDeliveryWorker := onboarding.NewWebhookDeliveryWorker(logger, webhooks)
+	idempotencyKeysCleanupWorker := onboarding.NewIdempotencyKeysCleanupWorker(logger, idempotencyKeys)
+	vehicleOwnersSyncWorker := onboarding.NewVehicleOwnersSyncWorker(logger, identityService, vs)
+	sessionEventsWorker := onboarding.NewSessionEventsWorker(os)
 
 	err := river.AddWorkerSafely(workers, verifyWorker)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to add verify worker")
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 	logger.Debug().Msg("Added verify worker")
 
 	err = river.AddWorkerSafely(workers, onboardingWorker)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to add onboarding worker")
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 	logger.Debug().Msg("Added onboarding worker")
 
 	err = river.AddWorkerSafely(workers, disconnectWorker)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to add disconnect worker")
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 	logger.Debug().Msg("Added disconnect worker")
 
 	err = river.AddWorkerSafely(workers, deleteWorker)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to add delete worker")
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 	logger.Debug().Msg("Added delete worker")
 
-	dbURL := settings.DB.BuildConnectionString(true)
-	dbPool, err := pgxpool.New(ctx, dbURL)
+	err = river.AddWorkerSafely(workers, webhookDeliveryWorker)
 	if err != nil {
-		logger.Fatal().Err(err).Msg("failed to connect to database")
-		return nil, nil, nil, err
+		logger.Fatal().Err(err).Msg("failed to add webhook delivery worker")
+		return nil, nil, err
 	}
+	logger.Debug().Msg("Added webhook delivery worker")
 
-	logger.Debug().Msg("DB pool for workers created")
+	err = river.AddWorkerSafely(workers, idempotencyKeysCleanupWorker)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to add idempotency keys cleanup worker")
+		return nil, nil, err
+	}
+	logger.Debug().Msg("Added idempotency keys cleanup worker")
+
+	err = river.AddWorkerSafely(workers, vehicleOwnersSyncWorker)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to add vehicle owners sync worker")
+		return nil, nil, err
+	}
+	logger.Debug().Msg("Added vehicle owners sync worker")
+
+	err = river.AddWorkerSafely(workers, sessionEventsWorker)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to add session events worker")
+		return nil, nil, err
+	}
+	logger.Debug().Msg("Added session events worker")
 
 	riverClient, err := river.NewClient(riverpgxv5.New(dbPool), &river.Config{
 		Queues: map[string]river.QueueConfig{
 			river.QueueDefault: {MaxWorkers: 100},
 		},
 		Workers: workers,
+		// continues the trace of the request that inserted the job and records the job metrics
+		Middleware: []rivertype.Middleware{&tracing.RiverMiddleware{}, &onboarding.JobMetricsMiddleware{}},
+		PeriodicJobs: []*river.PeriodicJob{
+			river.NewPeriodicJob(
+				river.PeriodicInterval(onboarding.IdempotencyKeysCleanupInterval),
+				func() (river.JobArgs, *river.InsertOpts) {
+					return onboarding.IdempotencyKeysCleanupArgs{}, nil
+				},
+				&river.PeriodicJobOpts{RunOnStart: true},
+			),
+			river.NewPeriodicJob(
+				river.PeriodicInterval(onboarding.VehicleOwnersSyncInterval),
+				func() (river.JobArgs, *river.InsertOpts) {
+					return onboarding.VehicleOwnersSyncArgs{}, nil
+				},
+				&river.PeriodicJobOpts{RunOnStart: true},
+			),
+			river.NewPeriodicJob(
+				river.PeriodicInterval(onboarding.SessionEventsInterval),
+				func() (river.JobArgs, *river.InsertOpts) {
+					return onboarding.SessionEventsArgs{}, nil
+				},
+				&river.PeriodicJobOpts{RunOnStart: true},
+			),
+		},
 	})
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create river client")
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
-	return riverClient, workers, dbPool, err
+	return riverClient, workers, err
+}
+
+// riverStoppedHealthCheck fails when the river client stopped, it doesn't touch the database so an outage of it
+// doesn't restart the pod
+func riverStoppedHealthCheck(riverClient *river.Client[pgx.Tx]) service.HealthCheck {
+	return func(_ context.Context) error {
+		select {
+		case <-riverClient.Stopped():
+			return errors.New("river client stopped")
+		default:
+			return nil
+		}
+	}
+}
+
+// riverQueueHealthCheck fails when the river client can't read its queue
+func riverQueueHealthCheck(riverClient *river.Client[pgx.Tx]) service.HealthCheck {
+	return func(ctx context.Context) error {
+		_, err := riverClient.QueueGet(ctx, river.QueueDefault)
+		if err != nil && !errors.Is(err, river.ErrNotFound) {
+			return err
+		}
+
+		return nil
+	}
 }
 
 func runRiver(ctx context.Context, logger zerolog.Logger, riverClient *river.Client[pgx.Tx], group *errgroup.Group) {
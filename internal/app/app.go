@@ -6,31 +6,52 @@ import (
 	"github.com/DIMO-Network/shared/pkg/middleware/metrics"
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
 	"github.com/DIMO-Network/volteras-oracle/internal/controllers"
+	"github.com/DIMO-Network/volteras-oracle/internal/docs"
 	"github.com/DIMO-Network/volteras-oracle/internal/service"
 	jwtware "github.com/gofiber/contrib/jwt"
+	"github.com/gofiber/contrib/otelfiber/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	fiberrecover "github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
 	"github.com/rs/zerolog"
 	"strconv"
+	"strings"
+	"time"
 )
 
-func App(settings *config.Settings, logger *zerolog.Logger, db *service.Vehicle, riverClient *river.Client[pgx.Tx], ws service.SDWalletsAPI, tr *transactions.Client) *fiber.App {
+// App creates the web API with all routes.
+// @title Volteras Oracle API
+// @version 1.0
+// @description Onboards vehicles of the vendor to DIMO: VIN verification, minting, disconnecting and deleting.
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @securityDescription DIMO JWT, sent as "Bearer {token}". Fleet operators send a JWT of their developer license, with the owner wallet in the X-Owner-Address header and the SACD document it signed for the license in the X-Owner-Sacd header.
+// @securityDefinitions.apikey AdminAPIKey
+// @in header
+// @name X-API-Key
+func App(settings *config.Settings, logger *zerolog.Logger, db *service.Vehicle, riverClient *river.Client[pgx.Tx], ws service.SDWalletsAPI, tr *transactions.Client, events *service.VinEventsHub, webhooks *service.Webhooks, idempotencyKeys *service.IdempotencyKeys, dbPool *pgxpool.Pool) *fiber.App {
 	if tr == nil {
 		logger.Fatal().Err(errors.New("tr transactions.Client is nil"))
 	}
 	// all the fiber logic here, routes, authorization
 	app := fiber.New(fiber.Config{
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return ErrorHandler(c, err, logger)
+			return ErrorHandler(c, err, logger, settings.IsProduction())
 		},
 		DisableStartupMessage:    true,
 		ReadBufferSize:           16000,
-		BodyLimit:                5 * 1024 * 1024,
+		BodyLimit:                bodyLimit(settings),
 		EnableSplittingOnParsers: true,
+		ProxyHeader:              settings.ProxyHeader,
 	})
+	// outermost, so the spans cover the other middleware and errors are handled before the span ends
+	app.Use(otelfiber.Middleware(otelfiber.WithNext(func(c *fiber.Ctx) bool {
+		return c.Path() == "/health" || strings.HasPrefix(c.Path(), "/docs")
+	})))
 	app.Use(metrics.HTTPMetricsMiddleware)
 
 	app.Use(fiberrecover.New(fiberrecover.Config{
@@ -39,59 +60,167 @@ func App(settings *config.Settings, logger *zerolog.Logger, db *service.Vehicle,
 		StackTraceHandler: nil,
 	}))
 
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "https://localdev.dimo.org:3008", // localhost development
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
-		AllowCredentials: true,
-	}))
+	app.Use(cors.New(corsConfig(settings)))
 
 	app.Get("/health", healthCheck)
 
+	// OpenAPI document of all routes and its UI
+	app.Get("/docs", docs.UIHandler)
+	app.Get("/docs/openapi.json", docs.SpecHandler)
+
 	identityService := service.NewIdentityAPIService(*logger, *settings)
-	vehiclesCtrl := controllers.NewVehiclesController(settings, logger, identityService, db, riverClient, ws, tr)
+	vehiclesCtrl := controllers.NewVehiclesController(settings, logger, identityService, db, riverClient, ws, tr, events)
+	webhooksCtrl := controllers.NewWebhooksController(settings, logger, webhooks, riverClient)
 
 	// assumes frontend has used Login With DIMO and has a JWT from DIMO.
 	jwtAuth := jwtware.New(jwtware.Config{
 		JWKSetURLs: []string{settings.JwtKeySetURL},
 	})
+	// developer license JWTs of fleet operators act on behalf of the vehicle owner
+	fleetAuth := controllers.FleetAuth(settings, logger, service.NewSacdVerifier(*logger, settings))
+	// privileges the SACD of a fleet operator must grant, routes which aren't about given minted vehicles need them on
+	// all vehicles of the owner
+	sacdRead := controllers.RequireSacd(service.SacdPrivilegeNonLocationHistory, false)
+	sacdReadAll := controllers.RequireSacd(service.SacdPrivilegeNonLocationHistory, true)
+	sacdOnboard := controllers.RequireSacd(service.SacdPrivilegeExecuteCommands, true)
+	sacdCommands := controllers.RequireSacd(service.SacdPrivilegeExecuteCommands, false)
+	// requests per client of every route group, see the RATE_LIMIT_* settings
+	window := time.Duration(settings.RateLimitWindowSeconds) * time.Second
+	ipLimit := controllers.RateLimit(controllers.RateLimitGroupIP, settings.RateLimitPerIP, window)
+	readLimit := controllers.RateLimit(controllers.RateLimitGroupRead, settings.RateLimitRead, window)
+	onboardingLimit := controllers.RateLimit(controllers.RateLimitGroupOnboarding, settings.RateLimitOnboarding, window)
+	webhooksLimit := controllers.RateLimit(controllers.RateLimitGroupWebhooks, settings.RateLimitWebhooks, window)
+	adminLimit := controllers.RateLimit(controllers.RateLimitGroupAdmin, settings.RateLimitAdmin, window)
+	app.Use("/v1", ipLimit)
+	app.Use("/admin", ipLimit)
+
+	// POST requests retried with the same Idempotency-Key header get the response of the first one
+	idempotency := controllers.Idempotency(logger, idempotencyKeys)
 	// get all vehicles in the database for frontend
-	app.Get("/v1/vehicles", jwtAuth, vehiclesCtrl.GetVehicles)
+	app.Get("/v1/vehicles", jwtAuth, fleetAuth, sacdReadAll, readLimit, vehiclesCtrl.GetVehicles)
+	// finds a vehicle of the user by VIN, token IDs, external ID or synthetic device address
+	app.Get("/v1/vehicles/search", jwtAuth, fleetAuth, sacdRead, readLimit, vehiclesCtrl.SearchVehicle)
+
+	// imports a fleet from a CSV file, registering minted vehicles and submitting the others for verification
+	app.Post("/v1/vehicles/import", jwtAuth, fleetAuth, sacdOnboard, onboardingLimit, idempotency, vehiclesCtrl.ImportVehicles)
+	// gets the status of every row of an import
+	app.Get("/v1/vehicles/import/:id", jwtAuth, fleetAuth, sacdReadAll, readLimit, vehiclesCtrl.GetImportBatch)
+	// downloads the failed rows of an import as CSV
+	app.Get("/v1/vehicles/import/:id/errors", jwtAuth, fleetAuth, sacdReadAll, readLimit, vehiclesCtrl.GetImportErrors)
 
 	// gets verification (VIN decoding and vendor support check) statuses
-	app.Get("/v1/vehicle/verify", jwtAuth, vehiclesCtrl.GetVerificationStatusForVins)
+	app.Get("/v1/vehicle/verify", jwtAuth, fleetAuth, sacdRead, readLimit, vehiclesCtrl.GetVerificationStatusForVins)
 	// handles decoding the VIN to be onboarded and checking if the vendor supports this VIN. Optional.
-	app.Post("/v1/vehicle/verify", jwtAuth, vehiclesCtrl.SubmitVerificationForVins)
+	app.Post("/v1/vehicle/verify", jwtAuth, fleetAuth, sacdOnboard, onboardingLimit, idempotency, vehiclesCtrl.SubmitVerificationForVins)
 
 	// gets minting status
-	app.Get("/v1/vehicle/mint/status", jwtAuth, vehiclesCtrl.GetMintStatusForVins)
+	app.Get("/v1/vehicle/mint/status", jwtAuth, fleetAuth, sacdRead, readLimit, vehiclesCtrl.GetMintStatusForVins)
 	// gets the payload to be signed for minting by the frontend (using passkey)
-	app.Get("/v1/vehicle/mint", jwtAuth, vehiclesCtrl.GetMintDataForVins)
+	app.Get("/v1/vehicle/mint", jwtAuth, fleetAuth, sacdOnboard, readLimit, vehiclesCtrl.GetMintDataForVins)
 	// submits the passkey signed minting payload to the backend
-	app.Post("/v1/vehicle/mint", jwtAuth, vehiclesCtrl.SubmitMintDataForVins)
+	app.Post("/v1/vehicle/mint", jwtAuth, fleetAuth, sacdOnboard, onboardingLimit, idempotency, vehiclesCtrl.SubmitMintDataForVins)
 
 	// gets disconnection status
-	app.Get("/v1/vehicle/disconnect/status", jwtAuth, vehiclesCtrl.GetDisconnectStatusForVins)
+	app.Get("/v1/vehicle/disconnect/status", jwtAuth, fleetAuth, sacdRead, readLimit, vehiclesCtrl.GetDisconnectStatusForVins)
 	// gets the payload to be signed for disconnecting by the frontend (using passkey)
-	app.Get("/v1/vehicle/disconnect", jwtAuth, vehiclesCtrl.GetDisconnectDataForVins)
+	app.Get("/v1/vehicle/disconnect", jwtAuth, fleetAuth, sacdCommands, readLimit, vehiclesCtrl.GetDisconnectDataForVins)
 	// submits the passkey signed disconnecting payload to the backend
-	app.Post("/v1/vehicle/disconnect", jwtAuth, vehiclesCtrl.SubmitDisconnectDataForVins)
+	app.Post("/v1/vehicle/disconnect", jwtAuth, fleetAuth, sacdCommands, onboardingLimit, idempotency, vehiclesCtrl.SubmitDisconnectDataForVins)
 
 	// gets vehicle deletion status
-	app.Get("/v1/vehicle/delete/status", jwtAuth, vehiclesCtrl.GetDeleteStatusForVins)
+	app.Get("/v1/vehicle/delete/status", jwtAuth, fleetAuth, sacdRead, readLimit, vehiclesCtrl.GetDeleteStatusForVins)
 	// gets the payload to be signed for deleting a vehicle by the frontend (using passkey)
-	app.Get("/v1/vehicle/delete", jwtAuth, vehiclesCtrl.GetDeleteDataForVins)
+	app.Get("/v1/vehicle/delete", jwtAuth, fleetAuth, sacdCommands, readLimit, vehiclesCtrl.GetDeleteDataForVins)
 	// submits the passkey signed delete vehicle payload to the backend
-	app.Post("/v1/vehicle/delete", jwtAuth, vehiclesCtrl.SubmitDeleteDataForVins)
+	app.Post("/v1/vehicle/delete", jwtAuth, fleetAuth, sacdCommands, onboardingLimit, idempotency, vehiclesCtrl.SubmitDeleteDataForVins)
+
+	// streams onboarding status changes of user's VINs (Server-Sent Events)
+	app.Get("/v1/vehicle/events", jwtAuth, fleetAuth, sacdReadAll, readLimit, vehiclesCtrl.StreamVinEvents)
 
 	// get a specific vehicle by ID (could be VIN or whatever identifier)
-	app.Get("/v1/vehicle/:externalID", jwtAuth, vehiclesCtrl.GetVehicleByExternalID)
+	app.Get("/v1/vehicle/:externalID", jwtAuth, fleetAuth, sacdRead, readLimit, vehiclesCtrl.GetVehicleByExternalID)
 	// submits vehicles to be registered by the backend
-	app.Post("/v1/vehicle/register", jwtAuth, vehiclesCtrl.RegisterVehicle)
+	app.Post("/v1/vehicle/register", jwtAuth, fleetAuth, sacdCommands, onboardingLimit, idempotency, vehiclesCtrl.RegisterVehicle)
+	// device definition picked by the owner for a VIN which failed verification
+	app.Put("/v1/vehicle/definition", jwtAuth, fleetAuth, sacdOnboard, onboardingLimit, vehiclesCtrl.SetVehicleDefinition)
+
+	// webhook subscriptions for onboarding lifecycle events of the VINs of a developer license
+	licenseAuth := controllers.DeveloperLicenseAuth(settings)
+	app.Get("/v1/webhooks", jwtAuth, licenseAuth, webhooksLimit, webhooksCtrl.GetSubscriptions)
+	app.Post("/v1/webhooks", jwtAuth, licenseAuth, webhooksLimit, idempotency, webhooksCtrl.CreateSubscription)
+	app.Delete("/v1/webhooks/:id", jwtAuth, licenseAuth, webhooksLimit, webhooksCtrl.DeleteSubscription)
+	// delivery log of a subscription, failed deliveries can be sent again
+	app.Get("/v1/webhooks/:id/deliveries", jwtAuth, licenseAuth, webhooksLimit, webhooksCtrl.GetDeliveries)
+	app.Post("/v1/webhooks/:id/deliveries/:deliveryId/redeliver", jwtAuth, licenseAuth, webhooksLimit, idempotency, webhooksCtrl.Redeliver)
+
+	if adminAuth, ok := controllers.AdminAuth(settings, logger); ok {
+		adminCtrl := controllers.NewAdminController(settings, logger, db, riverClient, ws, identityService, dbPool)
+
+		// operator endpoints, authenticated with admin API keys instead of DIMO JWT
+		admin := app.Group("/admin/v1", adminAuth, adminLimit, idempotency)
+
+		// search VINs by status, owner and error code
+		admin.Get("/vehicles", adminCtrl.SearchVehicles)
+		// streams all VINs as CSV or NDJSON, registered before the VIN route
+		admin.Get("/vehicles/export", adminCtrl.ExportVehicles)
+		// finds a VIN by token IDs, external ID or synthetic device address
+		admin.Get("/vehicles/lookup", adminCtrl.LookupVehicle)
+		// full VIN record with job history
+		admin.Get("/vehicles/:vin", adminCtrl.GetVehicle)
+		// moves a stuck VIN to a state from which it can be submitted again
+		admin.Post("/vehicles/:vin/reset", adminCtrl.ResetVehicleStatus)
+		// makes the latest verify, mint or disconnect job available again
+		admin.Post("/vehicles/:vin/requeue", adminCtrl.RequeueVehicleJob)
+		// manual device definition override
+		admin.Put("/vehicles/:vin/definition", adminCtrl.SetVehicleDefinition)
+		// admin actions
+		admin.Get("/audit", adminCtrl.GetAuditLog)
+	}
 
 	return app
 }
 
+const (
+	defaultCorsAllowedOrigins = "https://localdev.dimo.org:3008" // localhost development
+	defaultCorsAllowedMethods = "GET,POST,PUT,DELETE,OPTIONS"
+	defaultCorsAllowedHeaders = "Origin, Content-Type, Accept, Authorization, Idempotency-Key, X-Owner-Address, X-Owner-Sacd"
+	defaultBodyLimit          = 5 * 1024 * 1024
+)
+
+// corsConfig allows the frontends of the environment, credentials are not allowed with a wildcard origin
+func corsConfig(settings *config.Settings) cors.Config {
+	cfg := cors.Config{
+		AllowOrigins: settings.CorsAllowedOrigins,
+		AllowMethods: settings.CorsAllowedMethods,
+		AllowHeaders: settings.CorsAllowedHeaders,
+	}
+	if cfg.AllowOrigins == "" {
+		cfg.AllowOrigins = defaultCorsAllowedOrigins
+	}
+	if cfg.AllowMethods == "" {
+		cfg.AllowMethods = defaultCorsAllowedMethods
+	}
+	if cfg.AllowHeaders == "" {
+		cfg.AllowHeaders = defaultCorsAllowedHeaders
+	}
+	cfg.AllowCredentials = strings.TrimSpace(cfg.AllowOrigins) != "*"
+
+	return cfg
+}
+
+func bodyLimit(settings *config.Settings) int {
+	if settings.MaxRequestBodyBytes <= 0 {
+		return defaultBodyLimit
+	}
+
+	return settings.MaxRequestBodyBytes
+}
+
+// healthCheck
+// @Summary Health check
+// @Produce json
+// @Success 200 {object} object
+// @Router /health [get]
 func healthCheck(c *fiber.Ctx) error {
 	res := map[string]interface{}{
 		"data": "Server is up and running",
@@ -106,37 +235,43 @@ func healthCheck(c *fiber.Ctx) error {
 	return nil
 }
 
-// ErrorHandler custom handler to log recovered errors using our logger and return json instead of string
-func ErrorHandler(c *fiber.Ctx, err error, logger *zerolog.Logger) error {
-	code := fiber.StatusInternalServerError // Default 500 statuscode
+// ErrorHandler custom handler to log recovered errors using our logger and return the API error json instead of string
+func ErrorHandler(c *fiber.Ctx, err error, logger *zerolog.Logger, isProduction bool) error {
+	var apiErr *controllers.APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = &controllers.APIError{
+			Status:  fiber.StatusInternalServerError, // Default 500 statuscode
+			Code:    controllers.ErrCodeInternal,
+			Message: err.Error(),
+		}
 
-	var e *fiber.Error
-	isFiberErr := errors.As(err, &e)
-	if isFiberErr {
-		// Override status code if fiber.Error type
-		code = e.Code
+		var e *fiber.Error
+		if errors.As(err, &e) {
+			// Override status code if fiber.Error type
+			apiErr.Status = e.Code
+			apiErr.Code = controllers.ErrCodeForStatus(e.Code)
+		}
 	}
+
 	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
-	codeStr := strconv.Itoa(code)
+	codeStr := strconv.Itoa(apiErr.Status)
 
-	if code != fiber.StatusNotFound {
+	if apiErr.Status != fiber.StatusNotFound {
 		logger.Err(err).Str("httpStatusCode", codeStr).
+			Str("errorCode", apiErr.Code).
 			Str("httpMethod", c.Method()).
 			Str("httpPath", c.Path()).
 			Msg("caught an error from http request")
 	}
-	// return an opaque error if we're in a higher level environment and we haven't specified an fiber type err.
-	//if !isFiberErr && isProduction {
-	//	err = fiber.NewError(fiber.StatusInternalServerError, "Internal error")
-	//}
-
-	return c.Status(code).JSON(ErrorRes{
-		Code:    code,
-		Message: err.Error(),
-	})
-}
 
-type ErrorRes struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	// return an opaque error if we're in a higher level environment, internal errors may contain anything
+	if isProduction && apiErr.Code == controllers.ErrCodeInternal {
+		masked := *apiErr
+		masked.Message = internalErrorMessage
+		apiErr = &masked
+	}
+
+	return c.Status(apiErr.Status).JSON(apiErr)
 }
+
+const internalErrorMessage = "Internal error"
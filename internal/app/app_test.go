@@ -0,0 +1,144 @@
+package app
+
+import (
+	"encoding/json"
+	"github.com/DIMO-Network/go-transactions"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/DIMO-Network/volteras-oracle/internal/docs"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var routeParamRegexp = regexp.MustCompile(`:([A-Za-z]+)`)
+
+type AppTestSuite struct {
+	suite.Suite
+	jwks *httptest.Server
+	app  *fiber.App
+}
+
+func TestAppTestSuite(t *testing.T) {
+	suite.Run(t, new(AppTestSuite))
+}
+
+func (s *AppTestSuite) SetupSuite() {
+	s.jwks = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}))
+
+	s.app = s.newApp(&config.Settings{
+		AdminAPIKey: "admin-key",
+	})
+}
+
+func (s *AppTestSuite) newApp(settings *config.Settings) *fiber.App {
+	logger := zerolog.Nop()
+	settings.JwtKeySetURL = s.jwks.URL
+
+	return App(settings, &logger, nil, nil, nil, &transactions.Client{}, nil, nil, nil, nil)
+}
+
+func (s *AppTestSuite) TearDownSuite() {
+	s.jwks.Close()
+}
+
+// TestRoutesDocumented fails when a route is added or removed without updating the annotations of its handler
+func (s *AppTestSuite) TestRoutesDocumented() {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	s.Require().NoError(json.Unmarshal(docs.Spec(), &spec))
+
+	var documented []string
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	var routes []string
+	for _, route := range s.app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead || strings.HasPrefix(route.Path, "/docs") {
+			continue
+		}
+		routes = append(routes, route.Method+" "+routeParamRegexp.ReplaceAllString(route.Path, "{$1}"))
+	}
+
+	sort.Strings(documented)
+	sort.Strings(routes)
+	s.Equal(routes, documented, "routes and openapi.json differ, annotate the handlers and run `make docs`")
+}
+
+func (s *AppTestSuite) TestServeDocs() {
+	res, err := s.app.Test(httptest.NewRequest(fiber.MethodGet, "/docs/openapi.json", nil))
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusOK, res.StatusCode)
+	s.Contains(res.Header.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON)
+
+	res, err = s.app.Test(httptest.NewRequest(fiber.MethodGet, "/docs", nil))
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusOK, res.StatusCode)
+	s.Contains(res.Header.Get(fiber.HeaderContentType), fiber.MIMETextHTML)
+}
+
+func (s *AppTestSuite) TestCors() {
+	app := s.newApp(&config.Settings{
+		CorsAllowedOrigins: "https://app.example.com, https://fleet.example.com",
+	})
+
+	preflight := func(origin string) *http.Response {
+		req := httptest.NewRequest(fiber.MethodOptions, "/v1/vehicles", nil)
+		req.Header.Set(fiber.HeaderOrigin, origin)
+		req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodGet)
+		res, err := app.Test(req)
+		s.Require().NoError(err)
+		return res
+	}
+
+	res := preflight("https://fleet.example.com")
+	s.Equal("https://fleet.example.com", res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	s.Equal("true", res.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+	s.Contains(res.Header.Get(fiber.HeaderAccessControlAllowHeaders), "Idempotency-Key")
+
+	res = preflight("https://localdev.dimo.org:3008")
+	s.Empty(res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+
+	// credentials are not allowed with any origin
+	s.Equal(cors.Config{AllowOrigins: "*", AllowMethods: defaultCorsAllowedMethods, AllowHeaders: defaultCorsAllowedHeaders},
+		corsConfig(&config.Settings{CorsAllowedOrigins: "*"}))
+}
+
+func (s *AppTestSuite) TestRequestLimits() {
+	app := s.newApp(&config.Settings{
+		MaxRequestBodyBytes:    16,
+		RateLimitPerIP:         1,
+		RateLimitWindowSeconds: 60,
+	})
+
+	s.Equal(16, app.Config().BodyLimit)
+	s.Equal(defaultBodyLimit, bodyLimit(&config.Settings{}))
+
+	// requests without a valid JWT still count for the IP
+	res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/vehicles", nil))
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusBadRequest, res.StatusCode)
+
+	res, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/v1/vehicles", nil))
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusTooManyRequests, res.StatusCode)
+	s.NotEmpty(res.Header.Get(fiber.HeaderRetryAfter))
+
+	// health checks are not limited
+	res, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/health", nil))
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusOK, res.StatusCode)
+}
@@ -43,6 +43,7 @@ type Settings struct {
 	ChainID             int64          `yaml:"CHAIN_ID"`
 	VehicleNftAddress   common.Address `yaml:"VEHICLE_NFT_ADDRESS"`
 	SyntheticNftAddress common.Address `yaml:"SYNTHETIC_NFT_ADDRESS"`
+	SacdContractAddress common.Address `yaml:"SACD_CONTRACT_ADDRESS"` // DIMO SACD contract, SACDs of fleet operators are checked for revocation
 
 	// Identity-api - DIMO Identity Service api
 	IdentityAPIEndpoint url.URL `yaml:"IDENTITY_API_ENDPOINT"`
@@ -74,6 +75,80 @@ type Settings struct {
 	EnableVendorCapabilityCheck bool `yaml:"ENABLE_VENDOR_CAPABILITY_CHECK"`
 	EnableVendorConnection      bool `yaml:"ENABLE_VENDOR_CONNECTION"`
 	EnableVendorTestMode        bool `yaml:"ENABLE_VENDOR_TEST_MODE"`
+
+	// Telemetry - deduplication and out-of-order protection
+	TelemetryDedupWindowSeconds int  `yaml:"TELEMETRY_DEDUP_WINDOW_SECONDS"` // how long event ID + signal timestamp is remembered per VIN, 0 disables deduplication
+	TelemetryMaxSkewSeconds     int  `yaml:"TELEMETRY_MAX_SKEW_SECONDS"`     // events older than the newest seen for the VIN minus this skew are late
+	TelemetryDropLateEvents     bool `yaml:"TELEMETRY_DROP_LATE_EVENTS"`     // drop late events, otherwise they are forwarded flagged as late
+
+	// Telemetry - per vehicle rate limiting and downsampling, keeps noisy vehicles from starving the DIS limiter
+	TelemetryVINRateLimit            float64 `yaml:"TELEMETRY_VIN_RATE_LIMIT"`            // events per second per VIN, 0 disables the limit
+	TelemetryVINRateBurst            int     `yaml:"TELEMETRY_VIN_RATE_BURST"`            // token bucket size per VIN
+	TelemetryLocationIntervalSeconds int     `yaml:"TELEMETRY_LOCATION_INTERVAL_SECONDS"` // at most one location point per interval, 0 disables downsampling
+	TelemetryLocationSpeedDelta      float64 `yaml:"TELEMETRY_LOCATION_SPEED_DELTA"`      // speed change that forwards a location point regardless of the interval
+
+	// Telemetry - last seen tracking per vehicle
+	TelemetryStatusFlushSeconds int `yaml:"TELEMETRY_STATUS_FLUSH_SECONDS"` // how often last seen records are written to the DB
+	TelemetryStaleAfterMinutes  int `yaml:"TELEMETRY_STALE_AFTER_MINUTES"`  // vehicles not reporting for longer are counted as stale
+
+	// Telemetry - trips and charging sessions derived from signals, sent to DIS as events
+	EnableSessionDetection bool    `yaml:"ENABLE_SESSION_DETECTION"`
+	TripStartSpeed         float64 `yaml:"TRIP_START_SPEED"`      // km/h, trip starts when the vehicle is faster
+	TripEndIdleMinutes     int     `yaml:"TRIP_END_IDLE_MINUTES"` // trip ends when the vehicle stood still for this long
+
+	// Telemetry - odometer anomaly detection
+	OdometerMaxSpeed float64 `yaml:"ODOMETER_MAX_SPEED"` // km/h, odometer can't grow faster than this between readings
+
+	// Admin API - operator endpoints under /admin, disabled when the key is empty
+	AdminAPIKey string `yaml:"ADMIN_API_KEY"` // should be secret, sent in the X-API-Key header
+
+	// Webhooks - onboarding lifecycle events sent to developer subscriptions
+	WebhookTimeoutSeconds int `yaml:"WEBHOOK_TIMEOUT_SECONDS"` // timeout of a single delivery attempt
+
+	// Idempotency keys - POST requests sent again with the same Idempotency-Key header
+	IdempotencyKeyLeaseSeconds int `yaml:"IDEMPOTENCY_KEY_LEASE_SECONDS"` // extended while the request runs, retries take the key over once it lapsed
+
+	// Batch endpoints - VINs submitted in a single request
+	MaxBatchSize int `yaml:"MAX_BATCH_SIZE"` // larger requests are rejected, 0 disables the limit
+
+	// Fleet operators - backends of developer licenses onboarding vehicles on behalf of their owners
+	FleetDeveloperLicenses string `yaml:"FLEET_DEVELOPER_LICENSES"` // comma separated client IDs of the licenses, empty disables fleet operators
+
+	// CORS - frontends allowed to call the API, comma separated
+	CorsAllowedOrigins string `yaml:"CORS_ALLOWED_ORIGINS"` // eg. https://app.example.com, * allows any origin without credentials
+	CorsAllowedMethods string `yaml:"CORS_ALLOWED_METHODS"`
+	CorsAllowedHeaders string `yaml:"CORS_ALLOWED_HEADERS"`
+
+	// Rate limiting - requests per client within the window, clients are wallets once authenticated and IPs otherwise. 0 disables a limit
+	RateLimitWindowSeconds int    `yaml:"RATE_LIMIT_WINDOW_SECONDS"`
+	RateLimitPerIP         int    `yaml:"RATE_LIMIT_PER_IP"`     // all API requests of an IP
+	RateLimitRead          int    `yaml:"RATE_LIMIT_READ"`       // GET endpoints, they call identity-api
+	RateLimitOnboarding    int    `yaml:"RATE_LIMIT_ONBOARDING"` // verify, mint, disconnect, delete and register, they call device definitions and the chain
+	RateLimitWebhooks      int    `yaml:"RATE_LIMIT_WEBHOOKS"`   // webhook subscriptions and deliveries
+	RateLimitAdmin         int    `yaml:"RATE_LIMIT_ADMIN"`      // admin API, per IP
+	ProxyHeader            string `yaml:"PROXY_HEADER"`          // header with the client IP set by the ingress, eg. X-Real-Ip, empty uses the connection IP
+
+	// Request size
+	MaxRequestBodyBytes int `yaml:"MAX_REQUEST_BODY_BYTES"` // larger requests are rejected, defaults to 5MB
+
+	// Health checks - /livez and /readyz of the monitoring server
+	HealthCheckCacheSeconds int `yaml:"HEALTH_CHECK_CACHE_SECONDS"` // results are reused for this long, so probes don't hit the dependencies every time
+	CertExpiryWarningDays   int `yaml:"CERT_EXPIRY_WARNING_DAYS"`   // warns when the Dimo Node certificate expires within this many days
+
+	// Tracing - OpenTelemetry spans of requests, jobs, Kafka messages and outbound calls
+	TracingExporter     string  `yaml:"TRACING_EXPORTER"`      // stdout, otlp or empty to disable
+	TracingOTLPEndpoint string  `yaml:"TRACING_OTLP_ENDPOINT"` // eg. http://otel-collector:4318, empty uses the OTEL_EXPORTER_OTLP_ENDPOINT env
+	TracingSampleRatio  float64 `yaml:"TRACING_SAMPLE_RATIO"`  // share of the traces kept, defaults to all
+
+	// Fleet import - CSV files uploaded to /v1/vehicles/import
+	MaxImportRows int `yaml:"MAX_IMPORT_ROWS"` // larger files are rejected, 0 disables the limit
+
+	// Device definitions - verify jobs wait for the definition of a decoded VIN to show up in identity-api
+	DefinitionWaitSeconds        int `yaml:"DEFINITION_WAIT_SECONDS"`         // decoding fails when it doesn't show up in time, defaults to 60
+	DefinitionWaitBackoffSeconds int `yaml:"DEFINITION_WAIT_BACKOFF_SECONDS"` // first delay between checks, doubled every time, defaults to 5
+
+	// Admin API - keys of single operators, recorded as actor in the audit log
+	AdminAPIKeys string `yaml:"ADMIN_API_KEYS"` // should be secret, comma separated operator=key pairs, eg. alice@example.com=key1
 }
 
 func (s *Settings) IsProduction() bool {
@@ -0,0 +1,557 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"github.com/DIMO-Network/shared/pkg/logfields"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/rs/zerolog"
+	"github.com/volatiletech/null/v8"
+	"strings"
+)
+
+const (
+	// operator of the admin API key, recorded in the audit log
+	adminActorLocal     = "adminActor"
+	defaultAdminActor   = "admin"
+	maxAdminActorLength = 100
+	defaultAdminLimit   = 50
+	maxAdminLimit       = 500
+	adminJobHistorySize = 20
+
+	auditActionResetStatus        = "resetStatus"
+	auditActionRequeueJob         = "requeueJob"
+	auditActionDefinitionOverride = "definitionOverride"
+	auditActionExport             = "exportVehicles"
+	auditActionSearch             = "searchVehicles"
+	auditActionView               = "viewVehicle"
+)
+
+// requeueableJobKinds maps the requeue job names to River job kinds
+var requeueableJobKinds = map[string]string{
+	"verify":     onboarding.VerifyArgs{}.Kind(),
+	"mint":       onboarding.OnboardingArgs{}.Kind(),
+	"disconnect": onboarding.DisconnectArgs{}.Kind(),
+}
+
+type AdminController struct {
+	settings    *config.Settings
+	logger      *zerolog.Logger
+	vs          *service.Vehicle
+	riverClient *river.Client[pgx.Tx]
+	ws          service.SDWalletsAPI
+	identity    service.IdentityAPI
+	pool        *pgxpool.Pool
+}
+
+func NewAdminController(settings *config.Settings, logger *zerolog.Logger, vs *service.Vehicle, riverClient *river.Client[pgx.Tx], ws service.SDWalletsAPI, identity service.IdentityAPI, pool *pgxpool.Pool) *AdminController {
+	return &AdminController{
+		settings:    settings,
+		logger:      logger,
+		vs:          vs,
+		riverClient: riverClient,
+		ws:          ws,
+		identity:    identity,
+		pool:        pool,
+	}
+}
+
+// AdminAuth authenticates operators with their API key in the X-API-Key header, the operator of the key is recorded
+// in the audit log. ADMIN_API_KEYS has a key per operator, the shared ADMIN_API_KEY acts as the admin operator.
+// false is returned when no key is configured and the admin API is disabled.
+func AdminAuth(settings *config.Settings, logger *zerolog.Logger) (fiber.Handler, bool) {
+	keys := parseAdminAPIKeys(settings.AdminAPIKeys, logger)
+	if key := strings.TrimSpace(settings.AdminAPIKey); key != "" {
+		keys[key] = defaultAdminActor
+	}
+	if len(keys) == 0 {
+		return nil, false
+	}
+
+	return keyauth.New(keyauth.Config{
+		KeyLookup: "header:X-API-Key",
+		Validator: func(c *fiber.Ctx, key string) (bool, error) {
+			// every key is compared so the time doesn't tell which one matched
+			actor := ""
+			for candidate, operator := range keys {
+				if subtle.ConstantTimeCompare([]byte(key), []byte(candidate)) == 1 {
+					actor = operator
+				}
+			}
+			if actor == "" {
+				return false, keyauth.ErrMissingOrMalformedAPIKey
+			}
+
+			c.Locals(adminActorLocal, actor)
+			return true, nil
+		},
+	}), true
+}
+
+// parseAdminAPIKeys maps the keys of the comma separated operator=key pairs to their operator, invalid pairs are
+// skipped
+func parseAdminAPIKeys(value string, logger *zerolog.Logger) map[string]string {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		actor, key, ok := strings.Cut(pair, "=")
+		actor, key = strings.TrimSpace(actor), strings.TrimSpace(key)
+		if !ok || actor == "" || key == "" || len(actor) > maxAdminActorLength {
+			logger.Warn().Str("operator", actor).Msg("Skipped invalid admin API key")
+			continue
+		}
+
+		keys[key] = actor
+	}
+
+	return keys
+}
+
+// AdminVehicle is an onboarded vehicle of any wallet
+type AdminVehicle struct {
+	Vin                       string      `json:"vin"`
+	VehicleTokenID            null.Int64  `json:"vehicleTokenId"`
+	SyntheticTokenID          null.Int64  `json:"syntheticTokenId"`
+	ExternalID                null.String `json:"externalId"`
+	OwnerAddress              null.String `json:"ownerAddress"`
+	OnboardingStatus          int         `json:"onboardingStatus"`
+	OnboardingStatusDetails   string      `json:"onboardingStatusDetails"`
+	ConnectionStatus          null.String `json:"connectionStatus"`
+	DisconnectionStatus       null.String `json:"disconnectionStatus"`
+	DeviceDefinitionID        null.String `json:"deviceDefinitionId"`
+	DefinitionOverriddenBy    null.String `json:"definitionOverriddenBy"`
+	DefinitionOverriddenAt    null.Time   `json:"definitionOverriddenAt"`
+	WalletIndex               null.Int64  `json:"walletIndex"`
+	OperationErrorCode        null.String `json:"operationErrorCode"`
+	OperationErrorType        null.String `json:"operationErrorType"`
+	OperationErrorDescription null.String `json:"operationErrorDescription"`
+}
+
+// AdminVehiclesResponse vehicles matching the filters
+type AdminVehiclesResponse struct {
+	Vehicles []AdminVehicle `json:"vehicles"`
+}
+
+// AdminVehicleResponse is a vehicle with its jobs, telemetry, odometer anomalies and audit log
+type AdminVehicleResponse struct {
+	Vehicle           AdminVehicle                  `json:"vehicle"`
+	Jobs              []*service.RiverJob           `json:"jobs"`
+	Telemetry         *dbmodels.TelemetryStatus     `json:"telemetry,omitempty"`
+	OdometerAnomalies dbmodels.OdometerAnomalySlice `json:"odometerAnomalies"`
+	AuditLog          dbmodels.AdminAuditLogSlice   `json:"auditLog"`
+}
+
+// AdminRequeueParams job to requeue the vehicle with
+type AdminRequeueParams struct {
+	Job string `json:"job"`
+}
+
+// AdminDefinitionParams device definition to set
+type AdminDefinitionParams struct {
+	DefinitionID string `json:"definitionId"`
+}
+
+// AdminAuditLogResponse audit log entries
+type AdminAuditLogResponse struct {
+	AuditLog dbmodels.AdminAuditLogSlice `json:"auditLog"`
+}
+
+func toAdminVehicle(record *dbmodels.Vin) AdminVehicle {
+	return AdminVehicle{
+		Vin:                       record.Vin,
+		VehicleTokenID:            record.VehicleTokenID,
+		SyntheticTokenID:          record.SyntheticTokenID,
+		ExternalID:                record.ExternalID,
+		OwnerAddress:              record.OwnerAddress,
+		OnboardingStatus:          record.OnboardingStatus,
+		OnboardingStatusDetails:   onboarding.GetDetailedStatus(record.OnboardingStatus),
+		ConnectionStatus:          record.ConnectionStatus,
+		DisconnectionStatus:       record.DisconnectionStatus,
+		DeviceDefinitionID:        record.DeviceDefinitionID,
+		DefinitionOverriddenBy:    record.DefinitionOverriddenBy,
+		DefinitionOverriddenAt:    record.DefinitionOverriddenAt,
+		WalletIndex:               record.WalletIndex,
+		OperationErrorCode:        record.OperationErrorCode,
+		OperationErrorType:        record.OperationErrorType,
+		OperationErrorDescription: record.OperationErrorDescription,
+	}
+}
+
+func getAdminActor(c *fiber.Ctx) string {
+	if actor, ok := c.Locals(adminActorLocal).(string); ok && actor != "" {
+		return actor
+	}
+
+	return defaultAdminActor
+}
+
+func getAdminLimit(c *fiber.Ctx) int {
+	limit := c.QueryInt("limit", defaultAdminLimit)
+	if limit <= 0 || limit > maxAdminLimit {
+		return defaultAdminLimit
+	}
+
+	return limit
+}
+
+// auditEntry the audit log entry of the admin action, stored together with the change it records
+func (a *AdminController) auditEntry(c *fiber.Ctx, action, vin string, details interface{}) service.AuditEntry {
+	actor := getAdminActor(c)
+	a.logger.Info().Str("actor", actor).Str("action", action).Str(logfields.VIN, vin).Interface("details", details).Msg("Admin action")
+
+	return service.AuditEntry{
+		Actor:   actor,
+		Action:  action,
+		Vin:     vin,
+		Details: details,
+	}
+}
+
+// auditRead stores the audit log entry of an admin action which doesn't change anything, such as reading VINs
+func (a *AdminController) auditRead(c *fiber.Ctx, action, vin string, details interface{}) error {
+	if err := a.vs.InsertAuditLog(c.UserContext(), a.auditEntry(c, action, vin, details)); err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to store the audit log")
+	}
+
+	return nil
+}
+
+func (a *AdminController) getVehicle(c *fiber.Ctx) (*dbmodels.Vin, error) {
+	record, err := a.vs.GetVehicleByVin(c.Context(), strings.TrimSpace(c.Params("vin")))
+	if err != nil {
+		if errors.Is(err, service.ErrVehicleNotFound) {
+			return nil, NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
+		}
+
+		return nil, NewAPIError(ErrCodeInternal, "Failed to load vehicle from Database")
+	}
+
+	return record, nil
+}
+
+// SearchVehicles
+// @Summary Search VINs
+// @Description Lists VINs, filtered by VIN (partial match), owner, onboarding status and operation error code
+// @Produce json
+// @Param vin query string false "VIN or part of it"
+// @Param owner query string false "owner wallet address"
+// @Param status query int false "onboarding status"
+// @Param errorCode query string false "operation error code"
+// @Param limit query int false "page size"
+// @Param offset query int false "page offset"
+// @Success 200 {object} AdminVehiclesResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security AdminAPIKey
+// @Router /admin/v1/vehicles [get]
+func (a *AdminController) SearchVehicles(c *fiber.Ctx) error {
+	filter := service.VehicleFilter{
+		Vin:          strings.TrimSpace(c.Query("vin")),
+		OwnerAddress: strings.TrimSpace(c.Query("owner")),
+		ErrorCode:    strings.TrimSpace(c.Query("errorCode")),
+		Limit:        getAdminLimit(c),
+		Offset:       max(c.QueryInt("offset", 0), 0),
+	}
+	if c.Query("status") != "" {
+		status := c.QueryInt("status", -1)
+		if status < 0 {
+			return NewAPIError(ErrCodeInvalidRequest, "Invalid status")
+		}
+		filter.OnboardingStatus = &status
+	}
+
+	if err := a.auditRead(c, auditActionSearch, filter.Vin, fiber.Map{
+		"status":    c.Query("status"),
+		"owner":     filter.OwnerAddress,
+		"errorCode": filter.ErrorCode,
+		"limit":     filter.Limit,
+		"offset":    filter.Offset,
+	}); err != nil {
+		return err
+	}
+
+	records, err := a.vs.SearchVehicles(c.Context(), filter)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
+	}
+
+	vehicles := make([]AdminVehicle, 0, len(records))
+	for _, record := range records {
+		vehicles = append(vehicles, toAdminVehicle(record))
+	}
+
+	return c.JSON(AdminVehiclesResponse{
+		Vehicles: vehicles,
+	})
+}
+
+// GetVehicle
+// @Summary Get VIN details
+// @Description Full VIN record with River job history, telemetry status, odometer anomalies and admin actions
+// @Produce json
+// @Param vin path string true "VIN"
+// @Success 200 {object} AdminVehicleResponse
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Security AdminAPIKey
+// @Router /admin/v1/vehicles/{vin} [get]
+func (a *AdminController) GetVehicle(c *fiber.Ctx) error {
+	record, err := a.getVehicle(c)
+	if err != nil {
+		return err
+	}
+
+	if err := a.auditRead(c, auditActionView, record.Vin, nil); err != nil {
+		return err
+	}
+
+	return a.vehicleResponse(c, record)
+}
+
+// LookupVehicle
+// @Summary Find VIN by any identifier
+// @Description Finds the VIN by VIN, vehicle token ID, synthetic device token ID, vendor external ID or synthetic
+// @Description device address, exactly one of them must be set. Returns the same record as the VIN details.
+// @Produce json
+// @Param vin query string false "VIN"
+// @Param tokenId query int false "vehicle token ID"
+// @Param syntheticTokenId query int false "synthetic device token ID"
+// @Param externalId query string false "vendor vehicle ID"
+// @Param syntheticDeviceAddress query string false "synthetic device address"
+// @Success 200 {object} AdminVehicleResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Security AdminAPIKey
+// @Router /admin/v1/vehicles/lookup [get]
+func (a *AdminController) LookupVehicle(c *fiber.Ctx) error {
+	record, err := findVehicle(c, a.vs, a.ws)
+	if err != nil {
+		return err
+	}
+
+	if err := a.auditRead(c, auditActionView, record.Vin, fiber.Map{
+		"lookup": string(c.Request().URI().QueryString()),
+	}); err != nil {
+		return err
+	}
+
+	return a.vehicleResponse(c, record)
+}
+
+// vehicleResponse the VIN with its jobs, telemetry, odometer anomalies and audit log
+func (a *AdminController) vehicleResponse(c *fiber.Ctx, record *dbmodels.Vin) error {
+	jobs, err := a.vs.GetJobsByVin(c.Context(), record.Vin, nil, adminJobHistorySize)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load jobs from Database")
+	}
+
+	telemetryStatuses, err := a.vs.GetTelemetryStatusByVins(c.Context(), []string{record.Vin})
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load telemetry status from Database")
+	}
+
+	anomalies, err := a.vs.GetOdometerAnomalies(c.Context(), record.Vin, defaultAdminLimit)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load odometer anomalies from Database")
+	}
+
+	auditLog, err := a.vs.GetAuditLogs(c.Context(), record.Vin, defaultAdminLimit)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load audit log from Database")
+	}
+
+	return c.JSON(AdminVehicleResponse{
+		Vehicle:           toAdminVehicle(record),
+		Jobs:              jobs,
+		Telemetry:         telemetryStatuses[record.Vin],
+		OdometerAnomalies: anomalies,
+		AuditLog:          auditLog,
+	})
+}
+
+// ResetVehicleStatus
+// @Summary Reset VIN onboarding status
+// @Description Moves the VIN to the failure status of its current stage, so the stage can be submitted again by the owner
+// @Produce json
+// @Param vin path string true "VIN"
+// @Success 200 {object} AdminVehicleResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Security AdminAPIKey
+// @Router /admin/v1/vehicles/{vin}/reset [post]
+func (a *AdminController) ResetVehicleStatus(c *fiber.Ctx) error {
+	record, err := a.getVehicle(c)
+	if err != nil {
+		return err
+	}
+
+	previous := record.OnboardingStatus
+	status, ok := onboarding.GetResetStatus(previous)
+	if !ok {
+		return NewAPIError(ErrCodeInvalidRequest, "Vehicle is not in a stage that can be reset")
+	}
+
+	record.OnboardingStatus = status
+	entry := a.auditEntry(c, auditActionResetStatus, record.Vin, fiber.Map{
+		"from": onboarding.GetDetailedStatus(previous),
+		"to":   onboarding.GetDetailedStatus(status),
+	})
+	if err := a.vs.UpdateVinAudited(c.Context(), record, entry, dbmodels.VinColumns.OnboardingStatus); err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to update vehicle")
+	}
+
+	return c.JSON(AdminVehicleResponse{
+		Vehicle: toAdminVehicle(record),
+	})
+}
+
+// RequeueVehicleJob
+// @Summary Requeue VIN onboarding job
+// @Description Makes the latest verify, mint or disconnect job of the VIN available to run again.
+// @Description Workers only act on VINs in the matching stage, reset the status first if needed.
+// @Accept json
+// @Produce json
+// @Param vin path string true "VIN"
+// @Param params body AdminRequeueParams true "job to requeue: verify, mint or disconnect"
+// @Success 200 {object} object
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Security AdminAPIKey
+// @Router /admin/v1/vehicles/{vin}/requeue [post]
+func (a *AdminController) RequeueVehicleJob(c *fiber.Ctx) error {
+	params := new(AdminRequeueParams)
+	if err := c.BodyParser(params); err != nil {
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse request")
+	}
+
+	kind, ok := requeueableJobKinds[params.Job]
+	if !ok {
+		return NewAPIError(ErrCodeInvalidRequest, "Job must be one of verify, mint or disconnect")
+	}
+
+	record, err := a.getVehicle(c)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := a.vs.GetJobsByVin(c.Context(), record.Vin, []string{kind}, 1)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load jobs from Database")
+	}
+	if len(jobs) == 0 {
+		return NewAPIError(ErrCodeNotFound, "Could not find job for Vehicle")
+	}
+
+	entry := a.auditEntry(c, auditActionRequeueJob, record.Vin, fiber.Map{
+		"jobId": jobs[0].ID,
+		"kind":  jobs[0].Kind,
+	})
+
+	var job *rivertype.JobRow
+	err = pgx.BeginFunc(c.Context(), a.pool, func(tx pgx.Tx) error {
+		job, err = a.riverClient.JobRetryTx(c.Context(), tx, jobs[0].ID)
+		if err != nil {
+			return err
+		}
+		return service.InsertAuditLogTx(c.Context(), tx, entry)
+	})
+	if err != nil {
+		a.logger.Error().Err(err).Str(logfields.VIN, record.Vin).Int64("jobId", jobs[0].ID).Msg("Failed to requeue job")
+		return NewAPIError(ErrCodeInternal, "Failed to requeue job")
+	}
+
+	return c.JSON(fiber.Map{
+		"jobId": job.ID,
+		"state": job.State,
+	})
+}
+
+// SetVehicleDefinition
+// @Summary Override VIN device definition
+// @Description Sets the device definition manually, used for minting instead of the decoded one. Decoding the VIN
+// @Description later doesn't replace it, VINs which failed verification resume at vendor validation. The definition
+// @Description must be known to identity-api, and can't change once minting started.
+// @Accept json
+// @Produce json
+// @Param vin path string true "VIN"
+// @Param params body AdminDefinitionParams true "device definition ID"
+// @Success 200 {object} AdminVehicleResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Security AdminAPIKey
+// @Router /admin/v1/vehicles/{vin}/definition [put]
+func (a *AdminController) SetVehicleDefinition(c *fiber.Ctx) error {
+	params := new(AdminDefinitionParams)
+	if err := c.BodyParser(params); err != nil {
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse request")
+	}
+
+	definitionID := strings.TrimSpace(params.DefinitionID)
+	if definitionID == "" {
+		return NewAPIError(ErrCodeInvalidRequest, "Missing definition ID")
+	}
+
+	record, err := a.getVehicle(c)
+	if err != nil {
+		return err
+	}
+
+	if !onboarding.CanOverrideDefinition(record) {
+		return NewAPIError(ErrCodeInvalidStatus, "Device definition can't change once minting started")
+	}
+
+	dd, err := lookupDefinition(c.UserContext(), a.logger, a.identity, definitionID)
+	if err != nil {
+		return err
+	}
+
+	entry := a.auditEntry(c, auditActionDefinitionOverride, record.Vin, fiber.Map{
+		"from": record.DeviceDefinitionID,
+		"to":   definitionID,
+	})
+	if err := overrideDefinition(c.Context(), a.logger, a.vs, a.riverClient, record, dd, entry.Actor, &entry); err != nil {
+		return err
+	}
+
+	return c.JSON(AdminVehicleResponse{
+		Vehicle: toAdminVehicle(record),
+	})
+}
+
+// GetAuditLog
+// @Summary Get admin audit log
+// @Description Latest admin actions, optionally for a single VIN
+// @Produce json
+// @Param vin query string false "VIN"
+// @Param limit query int false "page size"
+// @Success 200 {object} AdminAuditLogResponse
+// @Failure 500 {object} APIError
+// @Security AdminAPIKey
+// @Router /admin/v1/audit [get]
+func (a *AdminController) GetAuditLog(c *fiber.Ctx) error {
+	auditLog, err := a.vs.GetAuditLogs(c.Context(), strings.TrimSpace(c.Query("vin")), getAdminLimit(c))
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load audit log from Database")
+	}
+
+	return c.JSON(AdminAuditLogResponse{
+		AuditLog: auditLog,
+	})
+}
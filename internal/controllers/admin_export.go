@@ -0,0 +1,353 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+	"github.com/volatiletech/null/v8"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of the vehicle export
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// vehicleExportTimeout bounds an export streamed to a client, which runs after its request returned
+const vehicleExportTimeout = 30 * time.Minute
+
+// exportFailedMessage is the error of the summary of exports cut short, details are logged
+const exportFailedMessage = "Failed to export vehicles"
+
+var exportContentTypes = map[string]string{
+	ExportFormatCSV:    "text/csv",
+	ExportFormatNDJSON: "application/x-ndjson",
+}
+
+var exportCSVHeader = []string{
+	"vin", "vehicle_token_id", "synthetic_token_id", "synthetic_device_address", "owner_address", "external_id",
+	"connection_status", "disconnection_status", "onboarding_status", "onboarding_status_details",
+	"device_definition_id", "operation_error_code", "operation_error_type", "operation_error_description",
+	"status_changed_at", "last_received_at", "last_forwarded_at", "last_telemetry_error",
+}
+
+// ExportedVehicle is a line of the vehicle export
+type ExportedVehicle struct {
+	Vin                       string      `json:"vin"`
+	VehicleTokenID            null.Int64  `json:"vehicleTokenId"`
+	SyntheticTokenID          null.Int64  `json:"syntheticTokenId"`
+	SyntheticDeviceAddress    null.String `json:"syntheticDeviceAddress"`
+	OwnerAddress              null.String `json:"ownerAddress"`
+	ExternalID                null.String `json:"externalId"`
+	ConnectionStatus          null.String `json:"connectionStatus"`
+	DisconnectionStatus       null.String `json:"disconnectionStatus"`
+	OnboardingStatus          int         `json:"onboardingStatus"`
+	OnboardingStatusDetails   string      `json:"onboardingStatusDetails"`
+	DeviceDefinitionID        null.String `json:"deviceDefinitionId"`
+	OperationErrorCode        null.String `json:"operationErrorCode"`
+	OperationErrorType        null.String `json:"operationErrorType"`
+	OperationErrorDescription null.String `json:"operationErrorDescription"`
+	StatusChangedAt           null.Time   `json:"statusChangedAt"`
+	LastReceivedAt            null.Time   `json:"lastReceivedAt"`
+	LastForwardedAt           null.Time   `json:"lastForwardedAt"`
+	LastTelemetryError        null.String `json:"lastTelemetryError"`
+}
+
+// ExportSummary is the last record of an export, exports without it or which are not complete were cut short
+type ExportSummary struct {
+	Vehicles int    `json:"vehicles"`
+	Complete bool   `json:"complete"`
+	Error    string `json:"error,omitempty"`
+}
+
+// VehicleExportWriter writes exported vehicles in one of the export formats
+type VehicleExportWriter interface {
+	Write(vehicle *ExportedVehicle) error
+	// Finish writes the summary after the vehicles and flushes the export
+	Finish(summary ExportSummary) error
+}
+
+// NewVehicleExportWriter creates a writer of the format, the header of CSV exports is written with the first vehicle.
+// The summary is the last line, a # comment in CSV exports and a summary object in NDJSON ones.
+func NewVehicleExportWriter(format string, w io.Writer) (VehicleExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportWriter{out: w, w: csv.NewWriter(w)}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonExportWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %s", format)
+	}
+}
+
+type csvExportWriter struct {
+	out           io.Writer
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvExportWriter) Write(vehicle *ExportedVehicle) error {
+	if !e.headerWritten {
+		if err := e.w.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+
+	return e.w.Write([]string{
+		csvCell(vehicle.Vin),
+		csvInt(vehicle.VehicleTokenID),
+		csvInt(vehicle.SyntheticTokenID),
+		csvCell(vehicle.SyntheticDeviceAddress.String),
+		csvCell(vehicle.OwnerAddress.String),
+		csvCell(vehicle.ExternalID.String),
+		csvCell(vehicle.ConnectionStatus.String),
+		csvCell(vehicle.DisconnectionStatus.String),
+		strconv.Itoa(vehicle.OnboardingStatus),
+		csvCell(vehicle.OnboardingStatusDetails),
+		csvCell(vehicle.DeviceDefinitionID.String),
+		csvCell(vehicle.OperationErrorCode.String),
+		csvCell(vehicle.OperationErrorType.String),
+		csvCell(vehicle.OperationErrorDescription.String),
+		csvTime(vehicle.StatusChangedAt),
+		csvTime(vehicle.LastReceivedAt),
+		csvTime(vehicle.LastForwardedAt),
+		csvCell(vehicle.LastTelemetryError.String),
+	})
+}
+
+func (e *csvExportWriter) Finish(summary ExportSummary) error {
+	if !e.headerWritten {
+		// empty exports still have the header
+		if err := e.w.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+
+	line := fmt.Sprintf("# vehicles=%d complete=%t", summary.Vehicles, summary.Complete)
+	if summary.Error != "" {
+		line += fmt.Sprintf(" error=%q", summary.Error)
+	}
+	_, err := io.WriteString(e.out, line+"\n")
+	return err
+}
+
+// csvCell neutralises text which spreadsheets would run as a formula
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func csvInt(value null.Int64) string {
+	if !value.Valid {
+		return ""
+	}
+
+	return strconv.FormatInt(value.Int64, 10)
+}
+
+func csvTime(value null.Time) string {
+	if !value.Valid {
+		return ""
+	}
+
+	return value.Time.UTC().Format(time.RFC3339)
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonExportWriter) Write(vehicle *ExportedVehicle) error {
+	return e.enc.Encode(vehicle)
+}
+
+func (e *ndjsonExportWriter) Finish(summary ExportSummary) error {
+	return e.enc.Encode(struct {
+		Summary ExportSummary `json:"summary"`
+	}{summary})
+}
+
+// ParseVehicleExportFilter builds the export filter from an onboarding status or phase and an RFC3339 date range of
+// the last status change, empty values are not filtered on.
+func ParseVehicleExportFilter(status, phase, from, to string) (service.VehicleExportFilter, error) {
+	var filter service.VehicleExportFilter
+
+	if status != "" && phase != "" {
+		return filter, errors.New("status and phase can't be used together")
+	}
+	if status != "" {
+		value, err := strconv.Atoi(status)
+		if err != nil || value < 0 {
+			return filter, errors.New("invalid status")
+		}
+		maxStatus := value + 1
+		filter.MinStatus, filter.MaxStatus = &value, &maxStatus
+	}
+	if phase != "" {
+		minStatus, maxStatus, ok := onboarding.GetPhaseStatusRange(phase)
+		if !ok {
+			return filter, errors.New("phase must be one of verification, mint, disconnect or delete")
+		}
+		filter.MinStatus, filter.MaxStatus = &minStatus, &maxStatus
+	}
+	if from != "" {
+		value, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, errors.New("from must be an RFC3339 date")
+		}
+		filter.From = &value
+	}
+	if to != "" {
+		value, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, errors.New("to must be an RFC3339 date")
+		}
+		filter.To = &value
+	}
+
+	return filter, nil
+}
+
+// WriteVehicleExport streams the vehicles matching the filter to the writer, one database row at a time, and ends
+// with the summary, which tells exports that failed part way.
+func WriteVehicleExport(ctx context.Context, vs *service.Vehicle, ws service.SDWalletsAPI, filter service.VehicleExportFilter, w VehicleExportWriter) error {
+	summary := ExportSummary{}
+	err := vs.ExportVehicles(ctx, filter, func(vehicle *service.VehicleExport) error {
+		exported, err := toExportedVehicle(ws, vehicle)
+		if err != nil {
+			return err
+		}
+
+		if err := w.Write(exported); err != nil {
+			return err
+		}
+		summary.Vehicles++
+		return nil
+	})
+	if err != nil {
+		summary.Error = exportFailedMessage
+		// the writer may be what failed, the export error is the one returned
+		_ = w.Finish(summary)
+		return err
+	}
+
+	summary.Complete = true
+	return w.Finish(summary)
+}
+
+func toExportedVehicle(ws service.SDWalletsAPI, vehicle *service.VehicleExport) (*ExportedVehicle, error) {
+	exported := ExportedVehicle{
+		Vin:                       vehicle.Vin,
+		VehicleTokenID:            vehicle.VehicleTokenID,
+		SyntheticTokenID:          vehicle.SyntheticTokenID,
+		OwnerAddress:              vehicle.OwnerAddress,
+		ExternalID:                vehicle.ExternalID,
+		ConnectionStatus:          vehicle.ConnectionStatus,
+		DisconnectionStatus:       vehicle.DisconnectionStatus,
+		OnboardingStatus:          vehicle.OnboardingStatus,
+		OnboardingStatusDetails:   onboarding.GetDetailedStatus(vehicle.OnboardingStatus),
+		DeviceDefinitionID:        vehicle.DeviceDefinitionID,
+		OperationErrorCode:        vehicle.OperationErrorCode,
+		OperationErrorType:        vehicle.OperationErrorType,
+		OperationErrorDescription: vehicle.OperationErrorDescription,
+		StatusChangedAt:           vehicle.StatusChangedAt,
+		LastReceivedAt:            vehicle.LastReceivedAt,
+		LastForwardedAt:           vehicle.LastForwardedAt,
+		LastTelemetryError:        vehicle.LastError,
+	}
+
+	if vehicle.SyntheticTokenID.Valid && vehicle.WalletIndex.Valid {
+		address, err := ws.GetAddress(uint32(vehicle.WalletIndex.Int64))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get synthetic device address of %s: %w", vehicle.Vin, err)
+		}
+		exported.SyntheticDeviceAddress = null.StringFrom(address.Hex())
+	}
+
+	return &exported, nil
+}
+
+// ExportVehicles
+// @Summary Export VINs
+// @Description Streams every VIN with token IDs, synthetic device, owner, statuses, errors, last status change and
+// @Description telemetry freshness, as CSV or NDJSON. Filtered by onboarding status or phase and by the date range of
+// @Description the last status change. The last line is the summary with the number of vehicles and whether the export
+// @Description is complete, a # comment in CSV and a summary object in NDJSON. Exports are recorded in the audit log.
+// @Produce csv
+// @Param format query string false "csv (default) or ndjson"
+// @Param status query int false "onboarding status"
+// @Param phase query string false "onboarding phase: verification, mint, disconnect or delete"
+// @Param from query string false "last status change from, RFC3339"
+// @Param to query string false "last status change before, RFC3339"
+// @Success 200 {string} string
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security AdminAPIKey
+// @Router /admin/v1/vehicles/export [get]
+func (a *AdminController) ExportVehicles(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", ExportFormatCSV))
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return NewAPIError(ErrCodeInvalidRequest, "Format must be one of csv or ndjson")
+	}
+
+	filter, err := ParseVehicleExportFilter(strings.TrimSpace(c.Query("status")), strings.TrimSpace(c.Query("phase")),
+		strings.TrimSpace(c.Query("from")), strings.TrimSpace(c.Query("to")))
+	if err != nil {
+		return NewAPIError(ErrCodeInvalidRequest, err.Error())
+	}
+
+	if err := a.auditRead(c, auditActionExport, "", fiber.Map{
+		"format": format,
+		"status": c.Query("status"),
+		"phase":  c.Query("phase"),
+		"from":   c.Query("from"),
+		"to":     c.Query("to"),
+	}); err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Attachment(fmt.Sprintf("vehicles-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format))
+
+	// the request context is cancelled once the handler returns, before the body is streamed: the export keeps its
+	// values, stops when writing to the client fails and is bounded by vehicleExportTimeout
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.UserContext()), vehicleExportTimeout)
+	actor := getAdminActor(c)
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		writer, err := NewVehicleExportWriter(format, w)
+		if err == nil {
+			err = WriteVehicleExport(ctx, a.vs, a.ws, filter, writer)
+		}
+		if err != nil {
+			// the status is already sent, the summary at the end of the export tells it failed
+			a.logger.Error().Err(err).Str("actor", actor).Msg("Failed to export vehicles")
+		}
+		_ = w.Flush()
+	}))
+
+	return nil
+}
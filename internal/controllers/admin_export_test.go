@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/stretchr/testify/suite"
+	"github.com/volatiletech/null/v8"
+	"strings"
+	"testing"
+	"time"
+)
+
+type AdminExportTestSuite struct {
+	suite.Suite
+}
+
+func TestAdminExportTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminExportTestSuite))
+}
+
+func (s *AdminExportTestSuite) TestParseVehicleExportFilter() {
+	filter, err := ParseVehicleExportFilter("", "", "", "")
+	s.Require().NoError(err)
+	s.Nil(filter.MinStatus)
+	s.Nil(filter.MaxStatus)
+	s.Nil(filter.From)
+	s.Nil(filter.To)
+
+	filter, err = ParseVehicleExportFilter("52", "", "2026-01-01T00:00:00Z", "2026-02-01T00:00:00+01:00")
+	s.Require().NoError(err)
+	s.Equal(onboarding.OnboardingStatusMintFailure, *filter.MinStatus)
+	s.Equal(onboarding.OnboardingStatusMintSuccess, *filter.MaxStatus)
+	s.True(filter.From.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+	s.True(filter.To.Equal(time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)))
+
+	filter, err = ParseVehicleExportFilter("", onboarding.OnboardingPhaseMint, "", "")
+	s.Require().NoError(err)
+	s.Equal(onboarding.OnboardingStatusMintSubmitUnknown, *filter.MinStatus)
+	s.Equal(onboarding.OnboardingStatusDisconnectSubmitUnknown, *filter.MaxStatus)
+
+	for _, invalid := range [][4]string{
+		{"abc", "", "", ""},
+		{"-1", "", "", ""},
+		{"52", onboarding.OnboardingPhaseMint, "", ""},
+		{"", "unknown", "", ""},
+		{"", "", "2026-01-01", ""},
+		{"", "", "", "yesterday"},
+	} {
+		_, err = ParseVehicleExportFilter(invalid[0], invalid[1], invalid[2], invalid[3])
+		s.Error(err, invalid)
+	}
+}
+
+func exportTestVehicle() *ExportedVehicle {
+	return &ExportedVehicle{
+		Vin:                       "1HGCM82633A004352",
+		VehicleTokenID:            null.Int64From(12),
+		SyntheticTokenID:          null.Int64From(34),
+		SyntheticDeviceAddress:    null.StringFrom("0x0000000000000000000000000000000000000001"),
+		OwnerAddress:              null.StringFrom("0x0000000000000000000000000000000000000002"),
+		OnboardingStatus:          onboarding.OnboardingStatusMintSuccess,
+		OnboardingStatusDetails:   onboarding.GetDetailedStatus(onboarding.OnboardingStatusMintSuccess),
+		OperationErrorDescription: null.StringFrom("vendor said \"no\", twice"),
+		StatusChangedAt:           null.TimeFrom(time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))),
+	}
+}
+
+func (s *AdminExportTestSuite) TestCSVExportWriter() {
+	var buf bytes.Buffer
+	writer, err := NewVehicleExportWriter(ExportFormatCSV, &buf)
+	s.Require().NoError(err)
+
+	s.Require().NoError(writer.Write(exportTestVehicle()))
+	s.Require().NoError(writer.Write(&ExportedVehicle{Vin: "1HGCM82633A004353"}))
+	s.Require().NoError(writer.Finish(ExportSummary{Vehicles: 2, Complete: true}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	s.Require().Len(lines, 4)
+	s.Equal(strings.Join(exportCSVHeader, ","), lines[0])
+	s.Equal(`1HGCM82633A004352,12,34,0x0000000000000000000000000000000000000001,0x0000000000000000000000000000000000000002,,,,53,MintSuccess,,,,"vendor said ""no"", twice",2026-03-04T04:06:07Z,,,`, lines[1])
+	s.Equal("1HGCM82633A004353,,,,,,,,0,,,,,,,,,", lines[2])
+	s.Equal("# vehicles=2 complete=true", lines[3])
+
+	reader := csv.NewReader(strings.NewReader(buf.String()))
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	s.Require().NoError(err)
+	s.Len(records, 3)
+}
+
+func (s *AdminExportTestSuite) TestCSVExportWriterFormulas() {
+	var buf bytes.Buffer
+	writer, err := NewVehicleExportWriter(ExportFormatCSV, &buf)
+	s.Require().NoError(err)
+
+	s.Require().NoError(writer.Write(&ExportedVehicle{
+		Vin:                       "1HGCM82633A004352",
+		ExternalID:                null.StringFrom("=HYPERLINK(\"http://example.com\")"),
+		OperationErrorCode:        null.StringFrom("-1"),
+		OperationErrorDescription: null.StringFrom("@SUM(A1:A2)"),
+		LastTelemetryError:        null.StringFrom("+cmd"),
+	}))
+	s.Require().NoError(writer.Finish(ExportSummary{Vehicles: 1, Complete: true}))
+
+	records, err := csv.NewReader(strings.NewReader(strings.Split(buf.String(), "#")[0])).ReadAll()
+	s.Require().NoError(err)
+	s.Require().Len(records, 2)
+	s.Equal("'=HYPERLINK(\"http://example.com\")", records[1][5])
+	s.Equal("'-1", records[1][11])
+	s.Equal("'@SUM(A1:A2)", records[1][13])
+	s.Equal("'+cmd", records[1][17])
+}
+
+func (s *AdminExportTestSuite) TestCSVExportWriterEmpty() {
+	var buf bytes.Buffer
+	writer, err := NewVehicleExportWriter(ExportFormatCSV, &buf)
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Finish(ExportSummary{Complete: true}))
+
+	s.Equal(strings.Join(exportCSVHeader, ",")+"\n# vehicles=0 complete=true\n", buf.String())
+}
+
+func (s *AdminExportTestSuite) TestExportWriterFailedSummary() {
+	var buf bytes.Buffer
+	writer, err := NewVehicleExportWriter(ExportFormatCSV, &buf)
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Finish(ExportSummary{Vehicles: 3, Error: exportFailedMessage}))
+	s.Contains(buf.String(), `# vehicles=3 complete=false error="Failed to export vehicles"`)
+
+	buf.Reset()
+	writer, err = NewVehicleExportWriter(ExportFormatNDJSON, &buf)
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Finish(ExportSummary{Vehicles: 3, Error: exportFailedMessage}))
+	s.Equal(`{"summary":{"vehicles":3,"complete":false,"error":"Failed to export vehicles"}}`+"\n", buf.String())
+}
+
+func (s *AdminExportTestSuite) TestNDJSONExportWriter() {
+	var buf bytes.Buffer
+	writer, err := NewVehicleExportWriter(ExportFormatNDJSON, &buf)
+	s.Require().NoError(err)
+
+	s.Require().NoError(writer.Write(exportTestVehicle()))
+	s.Require().NoError(writer.Write(&ExportedVehicle{Vin: "1HGCM82633A004353"}))
+	s.Require().NoError(writer.Finish(ExportSummary{Vehicles: 2, Complete: true}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	s.Require().Len(lines, 3)
+
+	var first ExportedVehicle
+	s.Require().NoError(json.Unmarshal([]byte(lines[0]), &first))
+	s.Equal("1HGCM82633A004352", first.Vin)
+	s.Equal(int64(34), first.SyntheticTokenID.Int64)
+	s.Equal("MintSuccess", first.OnboardingStatusDetails)
+	s.True(first.StatusChangedAt.Time.Equal(time.Date(2026, 3, 4, 4, 6, 7, 0, time.UTC)))
+
+	s.Contains(lines[1], `"vehicleTokenId":null`)
+	s.Equal(`{"summary":{"vehicles":2,"complete":true}}`, lines[2])
+}
+
+func (s *AdminExportTestSuite) TestUnknownFormat() {
+	_, err := NewVehicleExportWriter("xml", &bytes.Buffer{})
+	s.Error(err)
+}
@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/test"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"gotest.tools/v3/assert"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (s *VehicleControllerTestSuite) TestAdminResetVehicleStatus() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	c := NewAdminController(&config.Settings{Port: "3000"}, &mockDeps.logger, s.vs, s.river, nil, nil, s.pool)
+	app := fiber.New()
+	app.Post("/admin/vehicles/:vin/reset", func(c *fiber.Ctx) error {
+		c.Locals(adminActorLocal, "support@example.com")
+		return c.Next()
+	}, c.ResetVehicleStatus)
+
+	s.Run("Reset unknown VIN", func() {
+		req := test.BuildRequest("POST", "/admin/vehicles/ABCDEFG1234567811/reset", "")
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusNotFound, response.StatusCode)
+	})
+
+	s.Run("Reset VIN stuck in mint", func() {
+		dbVin := dbmodels.Vin{
+			Vin:              "ABCDEFG1234567812",
+			OnboardingStatus: onboarding.OnboardingStatusMintPending,
+		}
+		require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+		req := test.BuildRequest("POST", "/admin/vehicles/ABCDEFG1234567812/reset", "")
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		body, _ := io.ReadAll(response.Body)
+		var result AdminVehicleResponse
+		assert.NilError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, onboarding.OnboardingStatusMintFailure, result.Vehicle.OnboardingStatus)
+
+		logs, err := s.vs.GetAuditLogs(s.ctx, dbVin.Vin, 10)
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(logs))
+		assert.Equal(t, "support@example.com", logs[0].Actor)
+		assert.Equal(t, auditActionResetStatus, logs[0].Action)
+	})
+
+	s.Run("Minted VIN can't be reset", func() {
+		dbVin := dbmodels.Vin{
+			Vin:              "ABCDEFG1234567813",
+			OnboardingStatus: onboarding.OnboardingStatusMintSuccess,
+		}
+		require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+		req := test.BuildRequest("POST", "/admin/vehicles/ABCDEFG1234567813/reset", "")
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func (s *VehicleControllerTestSuite) TestAdminReadsAreAudited() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	c := NewAdminController(&config.Settings{Port: "3000"}, &mockDeps.logger, s.vs, s.river, nil, nil, s.pool)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(adminActorLocal, "support@example.com")
+		return c.Next()
+	})
+	app.Get("/admin/vehicles", c.SearchVehicles)
+	app.Get("/admin/vehicles/:vin", c.GetVehicle)
+
+	dbVin := dbmodels.Vin{
+		Vin:              "ADMINREAD00000001",
+		OnboardingStatus: onboarding.OnboardingStatusMintPending,
+	}
+	require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+	for _, path := range []string{"/admin/vehicles?vin=" + dbVin.Vin, "/admin/vehicles/" + dbVin.Vin} {
+		response, _ := app.Test(test.BuildRequest("GET", path, ""))
+		assert.Equal(t, fiber.StatusOK, response.StatusCode, path)
+	}
+
+	logs, err := s.vs.GetAuditLogs(s.ctx, dbVin.Vin, 10)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(logs))
+	for _, log := range logs {
+		assert.Equal(t, "support@example.com", log.Actor)
+	}
+	actions := []string{logs[0].Action, logs[1].Action}
+	assert.Assert(t, slices.Contains(actions, auditActionSearch))
+	assert.Assert(t, slices.Contains(actions, auditActionView))
+}
+
+func (s *VehicleControllerTestSuite) TestAdminRequeueVehicleJob() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	c := NewAdminController(&config.Settings{Port: "3000"}, &mockDeps.logger, s.vs, s.river, nil, nil, s.pool)
+	app := fiber.New()
+	app.Post("/admin/vehicles/:vin/requeue", func(c *fiber.Ctx) error {
+		c.Locals(adminActorLocal, "support@example.com")
+		return c.Next()
+	}, c.RequeueVehicleJob)
+
+	dbVin := dbmodels.Vin{Vin: "ABCDEFG1234567814", OnboardingStatus: onboarding.OnboardingStatusDecodingFailure}
+	require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+	_, err := s.river.Insert(s.ctx, onboarding.VerifyArgs{VIN: dbVin.Vin}, nil)
+	require.NoError(t, err)
+
+	req := test.BuildRequest("POST", "/admin/vehicles/ABCDEFG1234567814/requeue", `{"job": "verify"}`)
+	response, _ := app.Test(req)
+	assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+	// recorded together with the retry
+	logs, err := s.vs.GetAuditLogs(s.ctx, dbVin.Vin, 10)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, "support@example.com", logs[0].Actor)
+	assert.Equal(t, auditActionRequeueJob, logs[0].Action)
+}
+
+func (s *VehicleControllerTestSuite) TestAdminExportVehicles() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	c := NewAdminController(&config.Settings{Port: "3000"}, &mockDeps.logger, s.vs, s.river, nil, nil, s.pool)
+	app := fiber.New()
+	app.Get("/admin/vehicles/export", c.ExportVehicles)
+
+	for _, dbVin := range []dbmodels.Vin{
+		{Vin: "ABCDEFG1234567821", OnboardingStatus: onboarding.OnboardingStatusMintFailure},
+		{Vin: "ABCDEFG1234567822", OnboardingStatus: onboarding.OnboardingStatusSubmitPending},
+	} {
+		require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+	}
+	telemetry := dbmodels.TelemetryStatus{Vin: "ABCDEFG1234567821", LastReceivedAt: null.TimeFrom(time.Now())}
+	require.NoError(t, telemetry.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+	s.Run("Export mint phase as NDJSON", func() {
+		req := test.BuildRequest("GET", "/admin/vehicles/export?format=ndjson&phase=mint", "")
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		assert.Equal(t, "application/x-ndjson", response.Header.Get(fiber.HeaderContentType))
+
+		body, _ := io.ReadAll(response.Body)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		assert.Equal(t, 1, len(lines))
+
+		var exported ExportedVehicle
+		assert.NilError(t, json.Unmarshal([]byte(lines[0]), &exported))
+		assert.Equal(t, "ABCDEFG1234567821", exported.Vin)
+		assert.Equal(t, "MintFailure", exported.OnboardingStatusDetails)
+		// recorded by the status history trigger on insert
+		assert.Assert(t, exported.StatusChangedAt.Valid)
+		assert.Assert(t, exported.LastReceivedAt.Valid)
+	})
+
+	s.Run("Export status changes in the future", func() {
+		from := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		req := test.BuildRequest("GET", "/admin/vehicles/export?from="+from, "")
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, strings.Join(exportCSVHeader, ",")+"\n", string(body))
+	})
+
+	s.Run("Invalid filter", func() {
+		req := test.BuildRequest("GET", "/admin/vehicles/export?from=yesterday", "")
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+	})
+}
+
+type AdminAuthTestSuite struct {
+	suite.Suite
+}
+
+func TestAdminAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminAuthTestSuite))
+}
+
+func (s *AdminAuthTestSuite) actorOf(settings *config.Settings, key string) (int, string) {
+	logger := zerolog.Nop()
+	adminAuth, ok := AdminAuth(settings, &logger)
+	s.Require().True(ok)
+
+	app := fiber.New()
+	app.Get("/actor", adminAuth, func(c *fiber.Ctx) error {
+		return c.SendString(getAdminActor(c))
+	})
+
+	req := test.BuildRequest("GET", "/actor", "")
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	response, err := app.Test(req)
+	s.Require().NoError(err)
+	body, _ := io.ReadAll(response.Body)
+
+	return response.StatusCode, string(body)
+}
+
+func (s *AdminAuthTestSuite) TestActorOfKey() {
+	settings := &config.Settings{
+		AdminAPIKey:  "shared",
+		AdminAPIKeys: "alice@example.com=key1, bob = key2,invalid,=key3," + strings.Repeat("x", 101) + "=key4",
+	}
+
+	code, actor := s.actorOf(settings, "key1")
+	s.Equal(fiber.StatusOK, code)
+	s.Equal("alice@example.com", actor)
+
+	code, actor = s.actorOf(settings, "key2")
+	s.Equal(fiber.StatusOK, code)
+	s.Equal("bob", actor)
+
+	code, actor = s.actorOf(settings, "shared")
+	s.Equal(fiber.StatusOK, code)
+	s.Equal(defaultAdminActor, actor)
+
+	for _, key := range []string{"", "key3", "key4", "unknown"} {
+		code, _ = s.actorOf(settings, key)
+		s.Equal(fiber.StatusUnauthorized, code, key)
+	}
+}
+
+func (s *AdminAuthTestSuite) TestIdempotencyKeysScope() {
+	logger := zerolog.Nop()
+	adminAuth, ok := AdminAuth(&config.Settings{AdminAPIKey: "shared", AdminAPIKeys: "alice=key1,bob=key2"}, &logger)
+	s.Require().True(ok)
+
+	app := fiber.New()
+	app.Get("/scope", adminAuth, func(c *fiber.Ctx) error {
+		scope, err := idempotencyKeysScope(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(scope)
+	})
+
+	// operators don't share their keys
+	for key, scope := range map[string]string{"key1": "admin:alice", "key2": "admin:bob", "shared": "admin:" + defaultAdminActor} {
+		req := test.BuildRequest("GET", "/scope", "")
+		req.Header.Set("X-API-Key", key)
+		response, err := app.Test(req)
+		s.Require().NoError(err)
+		body, _ := io.ReadAll(response.Body)
+		s.Equal(scope, string(body), key)
+	}
+}
+
+func (s *AdminAuthTestSuite) TestDisabled() {
+	logger := zerolog.Nop()
+	_, ok := AdminAuth(&config.Settings{AdminAPIKeys: "invalid"}, &logger)
+	s.False(ok)
+}
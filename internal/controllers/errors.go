@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Error codes of the HTTP API, these are stable and can be relied on by clients.
+// The same codes are used as reason of single VINs in batch responses.
+const (
+	ErrCodeInvalidRequest           = "INVALID_REQUEST"
+	ErrCodeInvalidVin               = "INVALID_VIN"
+	ErrCodeDuplicateVin             = "DUPLICATE_VIN"
+	ErrCodeInvalidData              = "INVALID_DATA"
+	ErrCodeBatchTooLarge            = "BATCH_TOO_LARGE"
+	ErrCodeRequestTooLarge          = "REQUEST_TOO_LARGE"
+	ErrCodeRateLimited              = "RATE_LIMITED"
+	ErrCodeUnauthorized             = "UNAUTHORIZED"
+	ErrCodeForbidden                = "FORBIDDEN"
+	ErrCodeInvalidSacd              = "INVALID_SACD"
+	ErrCodeSacdNotAllowed           = "SACD_NOT_ALLOWED"
+	ErrCodeNotOwned                 = "NOT_OWNED"
+	ErrCodeNotFound                 = "NOT_FOUND"
+	ErrCodeInvalidStatus            = "INVALID_STATUS"
+	ErrCodeVinNotVerified           = "VIN_NOT_VERIFIED"
+	ErrCodeAlreadyMinted            = "ALREADY_MINTED"
+	ErrCodeTokenMismatch            = "TOKEN_MISMATCH"
+	ErrCodeConflict                 = "CONFLICT"
+	ErrCodeIdempotencyKeyReused     = "IDEMPOTENCY_KEY_REUSED"
+	ErrCodeIdempotencyKeyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	ErrCodeDecodingFailed           = "DECODING_FAILED"
+	ErrCodeVendorFailure            = "VENDOR_FAILURE"
+	ErrCodeTransactionFailed        = "TRANSACTION_FAILED"
+	ErrCodeSubmitFailed             = "SUBMIT_FAILED"
+	ErrCodeInternal                 = "INTERNAL_ERROR"
+)
+
+// errCodeStatus HTTP status of every error code, unknown codes are internal errors
+var errCodeStatus = map[string]int{
+	ErrCodeInvalidRequest:           fiber.StatusBadRequest,
+	ErrCodeInvalidVin:               fiber.StatusBadRequest,
+	ErrCodeDuplicateVin:             fiber.StatusBadRequest,
+	ErrCodeInvalidData:              fiber.StatusBadRequest,
+	ErrCodeBatchTooLarge:            fiber.StatusBadRequest,
+	ErrCodeRequestTooLarge:          fiber.StatusRequestEntityTooLarge,
+	ErrCodeRateLimited:              fiber.StatusTooManyRequests,
+	ErrCodeUnauthorized:             fiber.StatusUnauthorized,
+	ErrCodeForbidden:                fiber.StatusForbidden,
+	ErrCodeInvalidSacd:              fiber.StatusUnauthorized,
+	ErrCodeSacdNotAllowed:           fiber.StatusForbidden,
+	ErrCodeNotOwned:                 fiber.StatusForbidden,
+	ErrCodeNotFound:                 fiber.StatusNotFound,
+	ErrCodeInvalidStatus:            fiber.StatusConflict,
+	ErrCodeVinNotVerified:           fiber.StatusConflict,
+	ErrCodeAlreadyMinted:            fiber.StatusConflict,
+	ErrCodeTokenMismatch:            fiber.StatusConflict,
+	ErrCodeConflict:                 fiber.StatusConflict,
+	ErrCodeIdempotencyKeyReused:     fiber.StatusConflict,
+	ErrCodeIdempotencyKeyInProgress: fiber.StatusConflict,
+	ErrCodeDecodingFailed:           fiber.StatusBadGateway,
+	ErrCodeVendorFailure:            fiber.StatusBadGateway,
+	ErrCodeTransactionFailed:        fiber.StatusBadGateway,
+	ErrCodeSubmitFailed:             fiber.StatusInternalServerError,
+	ErrCodeInternal:                 fiber.StatusInternalServerError,
+}
+
+// statusErrCode error codes of errors which only carry an HTTP status, like the ones of fiber middlewares
+var statusErrCode = map[int]string{
+	fiber.StatusBadRequest:            ErrCodeInvalidRequest,
+	fiber.StatusUnauthorized:          ErrCodeUnauthorized,
+	fiber.StatusForbidden:             ErrCodeNotOwned,
+	fiber.StatusNotFound:              ErrCodeNotFound,
+	fiber.StatusConflict:              ErrCodeConflict,
+	fiber.StatusRequestEntityTooLarge: ErrCodeRequestTooLarge,
+	fiber.StatusTooManyRequests:       ErrCodeRateLimited,
+}
+
+// APIError is the body of every failed request
+type APIError struct {
+	Status  int         `json:"code"`
+	Code    string      `json:"errorCode"`
+	Message string      `json:"message"`
+	Details []VinStatus `json:"details,omitempty"`
+}
+
+// NewAPIError creates an error of the catalogue, its HTTP status is given by the code
+func NewAPIError(code, message string) *APIError {
+	status, ok := errCodeStatus[code]
+	if !ok {
+		status = fiber.StatusInternalServerError
+	}
+
+	return &APIError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// WithDetails adds the VINs which caused the error
+func (e *APIError) WithDetails(details []VinStatus) *APIError {
+	e.Details = details
+	return e
+}
+
+// ErrCodeForStatus returns the error code for an HTTP status without a more specific one
+func ErrCodeForStatus(status int) string {
+	if code, ok := statusErrCode[status]; ok {
+		return code
+	}
+
+	if status < fiber.StatusInternalServerError {
+		return ErrCodeInvalidRequest
+	}
+
+	return ErrCodeInternal
+}
+
+// failureErrCode returns the error code of a failed onboarding status, empty if the status isn't a failure
+func failureErrCode(status int) string {
+	if !onboarding.IsFailure(status) {
+		return ""
+	}
+
+	switch status {
+	case onboarding.OnboardingStatusDecodingFailure:
+		return ErrCodeDecodingFailed
+	case onboarding.OnboardingStatusVendorValidationFailure, onboarding.OnboardingStatusConnectFailure, onboarding.OnboardingStatusDisconnectFailure:
+		return ErrCodeVendorFailure
+	case onboarding.OnboardingStatusMintFailure, onboarding.OnboardingStatusBurnSDFailure, onboarding.OnboardingStatusBurnVehicleFailure:
+		return ErrCodeTransactionFailed
+	default:
+		return ErrCodeSubmitFailed
+	}
+}
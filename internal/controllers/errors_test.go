@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type APIErrorTestSuite struct {
+	suite.Suite
+}
+
+func TestAPIErrorTestSuite(t *testing.T) {
+	suite.Run(t, new(APIErrorTestSuite))
+}
+
+func (s *APIErrorTestSuite) TestNewAPIError() {
+	err := NewAPIError(ErrCodeVinNotVerified, "VIN is not verified")
+	s.Equal(fiber.StatusConflict, err.Status)
+	s.Equal("VIN is not verified", err.Error())
+
+	s.Equal(fiber.StatusNotFound, NewAPIError(ErrCodeNotFound, "").Status)
+	s.Equal(fiber.StatusBadGateway, NewAPIError(ErrCodeVendorFailure, "").Status)
+	s.Equal(fiber.StatusInternalServerError, NewAPIError("SOMETHING_ELSE", "").Status)
+
+	// every code of the catalogue has a status
+	for code, status := range errCodeStatus {
+		s.NotZero(status, code)
+	}
+}
+
+func (s *APIErrorTestSuite) TestErrCodeForStatus() {
+	s.Equal(ErrCodeUnauthorized, ErrCodeForStatus(fiber.StatusUnauthorized))
+	s.Equal(ErrCodeInvalidRequest, ErrCodeForStatus(fiber.StatusMethodNotAllowed))
+	s.Equal(ErrCodeInternal, ErrCodeForStatus(fiber.StatusServiceUnavailable))
+}
+
+func (s *APIErrorTestSuite) TestFailureErrCode() {
+	s.Empty(failureErrCode(onboarding.OnboardingStatusMintSuccess))
+	s.Equal(ErrCodeDecodingFailed, failureErrCode(onboarding.OnboardingStatusDecodingFailure))
+	s.Equal(ErrCodeVendorFailure, failureErrCode(onboarding.OnboardingStatusConnectFailure))
+	s.Equal(ErrCodeTransactionFailed, failureErrCode(onboarding.OnboardingStatusBurnSDFailure))
+	s.Equal(ErrCodeSubmitFailed, failureErrCode(onboarding.OnboardingStatusMintSubmitFailure))
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"context"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"strings"
+	"time"
+)
+
+const (
+	OwnerAddressHeader = "X-Owner-Address"
+	OwnerSacdHeader    = "X-Owner-Sacd"
+
+	// wallet the request acts for, when it isn't the one of the JWT
+	onBehalfOfLocal = "onBehalfOf"
+)
+
+// sacdGrantKey user context key of the SACD the request acts with
+type sacdGrantKey struct{}
+
+// sacdPrivilegeKey user context key of the SACD privilege the route requires
+type sacdPrivilegeKey struct{}
+
+// clientIDKey user context key of the developer client ID the JWT was issued to
+type clientIDKey struct{}
+
+// FleetAuth lets fleet operators onboard vehicles on behalf of their owners from a backend. A JWT issued to one of
+// the configured developer licenses (its client ID is the audience) must name the owner wallet in the X-Owner-Address
+// header and prove it with a SACD document granted to the license and signed by the owner, sent in the X-Owner-Sacd
+// header, which the owner didn't revoke in the SACD contract. The handlers then act for the owner. Other JWTs are user
+// JWTs of Login with DIMO and pass unchanged.
+// The license is kept as the client ID of the request, VINs are recorded for it so its webhooks get their events.
+func FleetAuth(settings *config.Settings, logger *zerolog.Logger, sacdVerifier *service.SacdVerifier) fiber.Handler {
+	licenses := parseDeveloperLicenses(settings.FleetDeveloperLicenses)
+
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return c.Next()
+		}
+
+		if len(licenses) == 0 {
+			return c.Next()
+		}
+
+		license, ok := developerLicense(token, licenses)
+		if !ok {
+			return c.Next()
+		}
+		c.SetUserContext(context.WithValue(c.UserContext(), clientIDKey{}, license.Hex()))
+
+		ownerHeader := strings.TrimSpace(c.Get(OwnerAddressHeader))
+		if !common.IsHexAddress(ownerHeader) {
+			return NewAPIError(ErrCodeUnauthorized, "Missing or invalid "+OwnerAddressHeader+" header")
+		}
+		owner := common.HexToAddress(ownerHeader)
+
+		grant, err := sacdVerifier.Verify(c.UserContext(), c.Get(OwnerSacdHeader), owner, license, time.Now())
+		if err != nil {
+			logger.Info().Err(err).Str("license", license.Hex()).Str("owner", owner.Hex()).Msg("Rejected SACD of fleet operator")
+
+			switch {
+			case errors.Is(err, service.ErrSacdSignerMismatch):
+				return NewAPIError(ErrCodeInvalidSacd, "SACD is not signed by the owner")
+			case errors.Is(err, service.ErrSacdGranteeMismatch):
+				return NewAPIError(ErrCodeInvalidSacd, "SACD is not granted to the developer license")
+			case errors.Is(err, service.ErrSacdExpired):
+				return NewAPIError(ErrCodeInvalidSacd, "SACD expired")
+			case errors.Is(err, service.ErrSacdRevoked):
+				return NewAPIError(ErrCodeInvalidSacd, "SACD was revoked")
+			case errors.Is(err, service.ErrInvalidSacd):
+				return NewAPIError(ErrCodeInvalidSacd, "Missing or invalid "+OwnerSacdHeader+" header")
+			default:
+				return NewAPIError(ErrCodeInternal, "Failed to verify the SACD")
+			}
+		}
+
+		logger.Debug().Str("license", license.Hex()).Str("owner", owner.Hex()).Time("expiresAt", grant.ExpiresAt).Msg("Acting on behalf of owner")
+		c.Locals(onBehalfOfLocal, owner)
+		// the owner checks of the handlers need it to check the VINs are covered
+		c.SetUserContext(context.WithValue(c.UserContext(), sacdGrantKey{}, grant))
+
+		return c.Next()
+	}
+}
+
+// DeveloperLicenseAuth only lets JWTs issued to one of the configured developer licenses through. The audience of user
+// JWTs is the client ID of the frontend, shared by all of its users, so it can't own resources of its own. The license
+// is kept as the client ID of the request.
+func DeveloperLicenseAuth(settings *config.Settings) fiber.Handler {
+	licenses := parseDeveloperLicenses(settings.FleetDeveloperLicenses)
+
+	return func(c *fiber.Ctx) error {
+		token, ok := c.Locals("user").(*jwt.Token)
+		if !ok {
+			return NewAPIError(ErrCodeUnauthorized, "Missing JWT")
+		}
+
+		license, ok := developerLicense(token, licenses)
+		if !ok {
+			return NewAPIError(ErrCodeForbidden, "Only available to developer licenses")
+		}
+		c.SetUserContext(context.WithValue(c.UserContext(), clientIDKey{}, license.Hex()))
+
+		return c.Next()
+	}
+}
+
+// RequireSacd checks the SACD of a fleet operator grants the privilege of the route on some vehicle, routes which
+// aren't about given vehicles need it on all vehicles of the owner. The handlers check the vehicles of the request
+// against the privilege. Requests without a SACD pass unchanged.
+func RequireSacd(privilege string, allVehicles bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		grant := sacdGrantOf(c.UserContext())
+		if grant == nil {
+			return c.Next()
+		}
+
+		if allVehicles && !grant.AllowsAllVehicles(privilege) {
+			return NewAPIError(ErrCodeSacdNotAllowed, "SACD doesn't grant the privilege required on all vehicles of the owner")
+		}
+
+		if !grant.AllowsAnyVehicle(privilege) {
+			return NewAPIError(ErrCodeSacdNotAllowed, "SACD doesn't grant the privilege required")
+		}
+
+		c.SetUserContext(context.WithValue(c.UserContext(), sacdPrivilegeKey{}, privilege))
+
+		return c.Next()
+	}
+}
+
+// sacdGrantOf SACD the request acts with, nil for user JWTs
+func sacdGrantOf(ctx context.Context) *service.SacdGrant {
+	grant, _ := ctx.Value(sacdGrantKey{}).(*service.SacdGrant)
+	return grant
+}
+
+// sacdCoversVehicle tells whether the request may act on the vehicle with the privilege of the route, always true for
+// user JWTs. Vehicles which aren't minted (token ID 0) are only covered by grants on all vehicles.
+func sacdCoversVehicle(ctx context.Context, tokenID int64) bool {
+	grant := sacdGrantOf(ctx)
+	if grant == nil {
+		return true
+	}
+
+	privilege, ok := ctx.Value(sacdPrivilegeKey{}).(string)
+	return ok && grant.AllowsVehicle(privilege, tokenID)
+}
+
+// sacdCoversVin tells whether the request may act on the VIN record, see sacdCoversVehicle
+func sacdCoversVin(ctx context.Context, record *dbmodels.Vin) bool {
+	return sacdCoversVehicle(ctx, record.VehicleTokenID.Int64)
+}
+
+// clientIDOf developer license the request was made through, see FleetAuth and DeveloperLicenseAuth
+func clientIDOf(ctx context.Context) (string, bool) {
+	clientID, ok := ctx.Value(clientIDKey{}).(string)
+	return clientID, ok
+}
+
+func parseDeveloperLicenses(value string) map[common.Address]struct{} {
+	licenses := make(map[common.Address]struct{})
+	for _, license := range strings.Split(value, ",") {
+		license = strings.TrimSpace(license)
+		if common.IsHexAddress(license) {
+			licenses[common.HexToAddress(license)] = struct{}{}
+		}
+	}
+
+	return licenses
+}
+
+// developerLicense returns the configured developer license the JWT was issued to
+func developerLicense(token *jwt.Token, licenses map[common.Address]struct{}) (common.Address, bool) {
+	audience, err := token.Claims.GetAudience()
+	if err != nil {
+		return common.Address{}, false
+	}
+
+	for _, clientID := range audience {
+		if !common.IsHexAddress(clientID) {
+			continue
+		}
+		if _, ok := licenses[common.HexToAddress(clientID)]; ok {
+			return common.HexToAddress(clientID), true
+		}
+	}
+
+	return common.Address{}, false
+}
@@ -0,0 +1,317 @@
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/test"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	fleetTestVehicleNft   = common.HexToAddress("0xbA5738a18d83D41847dfFbDC6101d37C69c9B0cF")
+	fleetTestSacdContract = common.HexToAddress("0x0000000000000000000000000000000000005acd")
+)
+
+// fakeSacdContract answers every permission record of the SACD contract, cleared once revoked
+type fakeSacdContract struct {
+	revoked bool
+}
+
+func (f *fakeSacdContract) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	recordType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "permissions", Type: "uint256"},
+		{Name: "expiration", Type: "uint256"},
+		{Name: "source", Type: "string"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	record := struct {
+		Permissions *big.Int
+		Expiration  *big.Int
+		Source      string
+	}{Permissions: big.NewInt(3), Expiration: big.NewInt(time.Now().Add(time.Hour).Unix())}
+	if f.revoked {
+		record.Permissions, record.Expiration = new(big.Int), new(big.Int)
+	}
+
+	return abi.Arguments{{Type: recordType}}.Pack(record)
+}
+
+type FleetAuthTestSuite struct {
+	suite.Suite
+	app      *fiber.App
+	ownerKey *ecdsa.PrivateKey
+	owner    common.Address
+	license  common.Address
+	operator common.Address
+}
+
+func TestFleetAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(FleetAuthTestSuite))
+}
+
+func (s *FleetAuthTestSuite) SetupSuite() {
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	s.ownerKey = key
+	s.owner = crypto.PubkeyToAddress(key.PublicKey)
+	s.license = common.HexToAddress("0x0000000000000000000000000000000000000123")
+	s.operator = common.HexToAddress("0x0000000000000000000000000000000000000456")
+
+	logger := zerolog.Nop()
+	settings := &config.Settings{FleetDeveloperLicenses: "0xnotAnAddress, " + s.license.Hex()}
+	// only signatures of wallet keys are used, the RPC client only answers the SACD contract
+	verifier := service.NewSacdVerifierWithCaller(logger, &fakeSacdContract{}, 137, fleetTestVehicleNft, fleetTestSacdContract)
+
+	s.app = fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			apiErr := err.(*APIError)
+			return c.Status(apiErr.Status).JSON(apiErr)
+		},
+	})
+	s.app.Get("/wallet", s.injectJWT, FleetAuth(settings, &logger, verifier), func(c *fiber.Ctx) error {
+		walletAddress, err := getWalletAddress(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(walletAddress.Hex())
+	})
+	s.app.Get("/client", s.injectJWT, FleetAuth(settings, &logger, verifier), func(c *fiber.Ctx) error {
+		clientID, err := getClientID(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(clientID)
+	})
+	// routes of the webhook subscriptions, see app.go
+	s.app.Get("/webhooks", s.injectJWT, DeveloperLicenseAuth(settings), func(c *fiber.Ctx) error {
+		clientID, err := getClientID(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(clientID)
+	})
+	// routes of the vehicle list and of deletions, see app.go
+	ok := func(c *fiber.Ctx) error { return c.SendString("ok") }
+	s.app.Get("/vehicles", s.injectJWT, FleetAuth(settings, &logger, verifier), RequireSacd(service.SacdPrivilegeNonLocationHistory, true), ok)
+	s.app.Get("/delete/:tokenID", s.injectJWT, FleetAuth(settings, &logger, verifier), RequireSacd(service.SacdPrivilegeExecuteCommands, false), func(c *fiber.Ctx) error {
+		tokenID, _ := c.ParamsInt("tokenID")
+		if !sacdCoversVehicle(c.UserContext(), int64(tokenID)) {
+			return NewAPIError(ErrCodeSacdNotAllowed, "Vehicle not covered by the SACD")
+		}
+		return c.SendString("ok")
+	})
+}
+
+// injectJWT injects a fake JWT of the wallet, issued to the client ID of the Audience header
+func (s *FleetAuthTestSuite) injectJWT(c *fiber.Ctx) error {
+	claims := jwt.MapClaims{
+		"sub":              "testUserID",
+		"ethereum_address": s.operator.Hex(),
+		"aud":              c.Get("Audience"),
+	}
+	c.Locals("user", jwt.NewWithClaims(jwt.SigningMethodHS256, claims))
+	return c.Next()
+}
+
+func (s *FleetAuthTestSuite) signSacd(grantee common.Address, expiration time.Time) string {
+	return s.signSacdData(service.SacdData{
+		Grantor:     service.SacdParty{Address: s.owner},
+		Grantee:     service.SacdParty{Address: grantee},
+		EffectiveAt: time.Now().Add(-time.Hour),
+		ExpiresAt:   expiration,
+		Agreements: []service.SacdAgreement{{
+			Type:        "permission",
+			Asset:       "did:ethr:137:" + s.owner.Hex(),
+			Permissions: []service.SacdPermission{{Name: service.SacdPrivilegeNonLocationHistory}, {Name: service.SacdPrivilegeExecuteCommands}},
+		}},
+	})
+}
+
+func (s *FleetAuthTestSuite) signSacdData(data service.SacdData) string {
+	payload, err := json.Marshal(data)
+	s.Require().NoError(err)
+
+	signature, err := crypto.Sign(accounts.TextHash(payload), s.ownerKey)
+	s.Require().NoError(err)
+
+	return service.EncodeSacdDocument(service.SacdDocument{SpecVersion: "1.0", Type: "dimo.sacd", Data: payload, Signature: hexutil.Encode(signature)})
+}
+
+func (s *FleetAuthTestSuite) getWallet(audience string, headers map[string]string) (int, string, string) {
+	return s.get("/wallet", audience, headers)
+}
+
+func (s *FleetAuthTestSuite) get(path, audience string, headers map[string]string) (int, string, string) {
+	req := test.BuildRequest("GET", path, "")
+	req.Header.Set("Audience", audience)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	response, err := s.app.Test(req)
+	s.Require().NoError(err)
+	body, _ := io.ReadAll(response.Body)
+
+	var apiErr APIError
+	_ = json.Unmarshal(body, &apiErr)
+
+	return response.StatusCode, string(body), apiErr.Code
+}
+
+func (s *FleetAuthTestSuite) TestUserJWT() {
+	// user JWTs keep acting for their own wallet, owner headers are ignored
+	code, body, _ := s.getWallet("0x0000000000000000000000000000000000000789", map[string]string{
+		OwnerAddressHeader: s.owner.Hex(),
+	})
+	s.Equal(fiber.StatusOK, code)
+	s.Equal(s.operator.Hex(), body)
+}
+
+func (s *FleetAuthTestSuite) TestOnBehalfOfOwner() {
+	code, body, _ := s.getWallet(s.license.Hex(), map[string]string{
+		OwnerAddressHeader: s.owner.Hex(),
+		OwnerSacdHeader:    s.signSacd(s.license, time.Now().Add(time.Hour)),
+	})
+	s.Equal(fiber.StatusOK, code)
+	s.Equal(s.owner.Hex(), body)
+}
+
+func (s *FleetAuthTestSuite) TestOnBehalfOfOwner_Rejected() {
+	valid := s.signSacd(s.license, time.Now().Add(time.Hour))
+
+	code, _, errCode := s.getWallet(s.license.Hex(), map[string]string{OwnerSacdHeader: valid})
+	s.Equal(fiber.StatusUnauthorized, code)
+	s.Equal(ErrCodeUnauthorized, errCode)
+
+	for _, headers := range []map[string]string{
+		{OwnerAddressHeader: s.owner.Hex()},
+		{OwnerAddressHeader: s.operator.Hex(), OwnerSacdHeader: valid},
+		{OwnerAddressHeader: s.owner.Hex(), OwnerSacdHeader: s.signSacd(s.operator, time.Now().Add(time.Hour))},
+		{OwnerAddressHeader: s.owner.Hex(), OwnerSacdHeader: s.signSacd(s.license, time.Now().Add(-time.Minute))},
+	} {
+		code, _, errCode = s.getWallet(s.license.Hex(), headers)
+		s.Equal(fiber.StatusUnauthorized, code)
+		s.Equal(ErrCodeInvalidSacd, errCode)
+	}
+}
+
+func (s *FleetAuthTestSuite) TestOnBehalfOfOwner_Revoked() {
+	logger := zerolog.Nop()
+	settings := &config.Settings{FleetDeveloperLicenses: s.license.Hex()}
+	verifier := service.NewSacdVerifierWithCaller(logger, &fakeSacdContract{revoked: true}, 137, fleetTestVehicleNft, fleetTestSacdContract)
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			apiErr := err.(*APIError)
+			return c.Status(apiErr.Status).JSON(apiErr)
+		},
+	})
+	app.Get("/wallet", s.injectJWT, FleetAuth(settings, &logger, verifier), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	req := test.BuildRequest("GET", "/wallet", "")
+	req.Header.Set("Audience", s.license.Hex())
+	req.Header.Set(OwnerAddressHeader, s.owner.Hex())
+	req.Header.Set(OwnerSacdHeader, s.signSacd(s.license, time.Now().Add(time.Hour)))
+	response, err := app.Test(req)
+	s.Require().NoError(err)
+
+	var apiErr APIError
+	s.Require().NoError(json.NewDecoder(response.Body).Decode(&apiErr))
+	s.Equal(fiber.StatusUnauthorized, response.StatusCode)
+	s.Equal(ErrCodeInvalidSacd, apiErr.Code)
+}
+
+func (s *FleetAuthTestSuite) TestClientID() {
+	// only for fleet operators, the audience of user JWTs is shared by all users of the frontend
+	code, _, errCode := s.get("/client", "0x000000000000000000000000000000000000abcd", nil)
+	s.Equal(fiber.StatusUnauthorized, code)
+	s.Equal(ErrCodeUnauthorized, errCode)
+
+	code, body, _ := s.get("/client", s.license.Hex(), map[string]string{
+		OwnerAddressHeader: s.owner.Hex(),
+		OwnerSacdHeader:    s.signSacd(s.license, time.Now().Add(time.Hour)),
+	})
+	s.Equal(fiber.StatusOK, code)
+	s.Equal(s.license.Hex(), body)
+
+	code, _, errCode = s.get("/client", "", nil)
+	s.Equal(fiber.StatusUnauthorized, code)
+	s.Equal(ErrCodeUnauthorized, errCode)
+}
+
+func (s *FleetAuthTestSuite) TestDeveloperLicenseAuth() {
+	// no owner headers needed, checksummed
+	code, body, _ := s.get("/webhooks", strings.ToLower(s.license.Hex()), nil)
+	s.Equal(fiber.StatusOK, code)
+	s.Equal(s.license.Hex(), body)
+
+	for _, audience := range []string{"", "frontend-client", s.operator.Hex()} {
+		code, _, errCode := s.get("/webhooks", audience, nil)
+		s.Equal(fiber.StatusForbidden, code, audience)
+		s.Equal(ErrCodeForbidden, errCode, audience)
+	}
+}
+
+func (s *FleetAuthTestSuite) TestRequireSacd() {
+	// commands on vehicle 7, read on vehicle 8
+	scoped := s.signSacdData(service.SacdData{
+		Grantor:     service.SacdParty{Address: s.owner},
+		Grantee:     service.SacdParty{Address: s.license},
+		EffectiveAt: time.Now().Add(-time.Hour),
+		ExpiresAt:   time.Now().Add(time.Hour),
+		Agreements: []service.SacdAgreement{
+			{Type: "permission", Asset: fmt.Sprintf("did:erc721:137:%s:7", fleetTestVehicleNft.Hex()), Permissions: []service.SacdPermission{{Name: service.SacdPrivilegeExecuteCommands}}},
+			{Type: "permission", Asset: fmt.Sprintf("did:erc721:137:%s:8", fleetTestVehicleNft.Hex()), Permissions: []service.SacdPermission{{Name: service.SacdPrivilegeNonLocationHistory}}},
+		},
+	})
+	all := s.signSacd(s.license, time.Now().Add(time.Hour))
+	scopedHeaders := map[string]string{OwnerAddressHeader: s.owner.Hex(), OwnerSacdHeader: scoped}
+
+	// user JWTs don't need a SACD
+	code, _, _ := s.get("/vehicles", "0x0000000000000000000000000000000000000789", nil)
+	s.Equal(fiber.StatusOK, code)
+
+	code, _, _ = s.get("/vehicles", s.license.Hex(), map[string]string{OwnerAddressHeader: s.owner.Hex(), OwnerSacdHeader: all})
+	s.Equal(fiber.StatusOK, code)
+
+	code, _, _ = s.get("/delete/7", s.license.Hex(), scopedHeaders)
+	s.Equal(fiber.StatusOK, code)
+
+	code, _, _ = s.get("/delete/9", s.license.Hex(), map[string]string{OwnerAddressHeader: s.owner.Hex(), OwnerSacdHeader: all})
+	s.Equal(fiber.StatusOK, code)
+
+	// the list needs the privilege on all vehicles, deletions the commands privilege on the vehicle
+	code, _, errCode := s.get("/vehicles", s.license.Hex(), scopedHeaders)
+	s.Equal(fiber.StatusForbidden, code)
+	s.Equal(ErrCodeSacdNotAllowed, errCode)
+
+	for _, path := range []string{"/delete/8", "/delete/0"} {
+		code, _, errCode = s.get(path, s.license.Hex(), scopedHeaders)
+		s.Equal(fiber.StatusForbidden, code, path)
+		s.Equal(ErrCodeSacdNotAllowed, errCode, path)
+	}
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Livez responds 503 when a component failed that only a restart recovers, meant for the Kubernetes liveness probe
+func Livez(checker *service.HealthChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return healthResponse(c, checker.Liveness(c.Context()))
+	}
+}
+
+// Readyz responds 503 when any component is failing, meant for the Kubernetes readiness probe
+func Readyz(checker *service.HealthChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return healthResponse(c, checker.Readiness(c.Context()))
+	}
+}
+
+func healthResponse(c *fiber.Ctx, report service.HealthReport) error {
+	if report.Status != service.HealthStatusOK {
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+
+	return c.JSON(report)
+}
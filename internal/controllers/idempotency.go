@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	adminIdempotencyKeysScope = "admin:"
+)
+
+// Idempotency answers a POST request sent again with the same Idempotency-Key header with the stored
+// response of the first one, instead of processing it again. Keys are scoped to the wallet of the user, or to the
+// operator of the admin API key.
+// Failed requests (5xx) don't keep the key, so they can be retried with it.
+func Idempotency(logger *zerolog.Logger, idempotencyKeys *service.IdempotencyKeys) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" || c.Method() != fiber.MethodPost {
+			return c.Next()
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			return NewAPIError(ErrCodeInvalidRequest, "Idempotency-Key is too long")
+		}
+
+		scope, err := idempotencyKeysScope(c)
+		if err != nil {
+			return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+		}
+
+		requestHash := service.HashIdempotentRequest(c.Method(), c.OriginalURL(), c.Body())
+		record, err := idempotencyKeys.Begin(c.Context(), scope, key, requestHash)
+		if err != nil {
+			if errors.Is(err, service.ErrIdempotencyKeyMismatch) {
+				return NewAPIError(ErrCodeIdempotencyKeyReused, "Idempotency-Key was already used for a different request")
+			}
+			if errors.Is(err, service.ErrIdempotencyKeyInProgress) {
+				return NewAPIError(ErrCodeIdempotencyKeyInProgress, "Request with this Idempotency-Key is still being processed")
+			}
+
+			return NewAPIError(ErrCodeInternal, "Failed to check Idempotency-Key")
+		}
+
+		if record.ResponseCode.Valid {
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, record.ResponseContentType.String)
+			return c.Status(record.ResponseCode.Int).Send(record.ResponseBody.Bytes)
+		}
+
+		// the key stays reserved for slow handlers
+		stopLease := idempotencyKeys.KeepLease(record)
+		defer stopLease()
+		// a panicking handler doesn't keep the key either, the panic goes on to the recover middleware
+		defer func() {
+			if r := recover(); r != nil {
+				stopLease()
+				if err := idempotencyKeys.Release(c.Context(), record); err != nil {
+					logger.Error().Err(err).Str("idempotencyKey", key).Msg("Failed to release Idempotency-Key of panicked request")
+				}
+				panic(r)
+			}
+		}()
+
+		err = c.Next()
+		stopLease()
+
+		// handler errors are turned into the response here, so it can be stored
+		if err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = idempotencyKeys.Release(c.Context(), record)
+				return err
+			}
+		}
+
+		code := c.Response().StatusCode()
+		if code >= fiber.StatusInternalServerError {
+			if err := idempotencyKeys.Release(c.Context(), record); err != nil {
+				logger.Error().Err(err).Str("idempotencyKey", key).Msg("Failed to release Idempotency-Key of failed request")
+			}
+			return nil
+		}
+
+		if err := idempotencyKeys.Complete(c.Context(), record, code, string(c.Response().Header.ContentType()), c.Response().Body()); err != nil {
+			logger.Error().Err(err).Str("idempotencyKey", key).Msg("Failed to store response of Idempotency-Key")
+		}
+
+		return nil
+	}
+}
+
+// idempotencyKeysScope keys of users are scoped to their wallet, requests authenticated with an admin API key to its
+// operator, see AdminAuth
+func idempotencyKeysScope(c *fiber.Ctx) (string, error) {
+	if _, ok := c.Locals("user").(*jwt.Token); !ok {
+		return adminIdempotencyKeysScope + getAdminActor(c), nil
+	}
+
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return "", err
+	}
+
+	return walletAddress.Hex(), nil
+}
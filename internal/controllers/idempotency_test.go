@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/test"
+	"github.com/gofiber/fiber/v2"
+	fiberrecover "github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"gotest.tools/v3/assert"
+	"io"
+	"time"
+)
+
+func (s *VehicleControllerTestSuite) TestIdempotency() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	calls := 0
+	app := fiber.New()
+	app.Post("/vehicle/mint", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), Idempotency(&mockDeps.logger, service.NewIdempotencyKeysService(&s.pdb, &mockDeps.logger, 0)), func(c *fiber.Ctx) error {
+		calls++
+		if string(c.Body()) == "fail" {
+			return fiber.NewError(fiber.StatusServiceUnavailable, "try again")
+		}
+		return c.JSON(fiber.Map{"calls": calls})
+	})
+
+	s.Run("Request without key is always processed", func() {
+		calls = 0
+		for range 2 {
+			response, _ := app.Test(test.BuildRequest("POST", "/vehicle/mint", "{}"))
+			assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	s.Run("Retried request gets the first response", func() {
+		calls = 0
+		for range 2 {
+			req := test.BuildRequest("POST", "/vehicle/mint", "{}")
+			req.Header.Set(IdempotencyKeyHeader, "key-1")
+			response, _ := app.Test(req)
+			assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+			body, _ := io.ReadAll(response.Body)
+			assert.Equal(t, `{"calls":1}`, string(body))
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	s.Run("Key reused with a different body", func() {
+		req := test.BuildRequest("POST", "/vehicle/mint", `{"vin":"other"}`)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusConflict, response.StatusCode)
+	})
+
+	s.Run("Failed request can be retried with the same key", func() {
+		calls = 0
+		for range 2 {
+			req := test.BuildRequest("POST", "/vehicle/mint", "fail")
+			req.Header.Set(IdempotencyKeyHeader, "key-2")
+			response, _ := app.Test(req)
+			assert.Equal(t, fiber.StatusServiceUnavailable, response.StatusCode)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	s.Run("Panicked request can be retried with the same key", func() {
+		panicking := fiber.New()
+		panicking.Use(fiberrecover.New())
+		panicking.Post("/vehicle/mint", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), Idempotency(&mockDeps.logger, service.NewIdempotencyKeysService(&s.pdb, &mockDeps.logger, 0)), func(_ *fiber.Ctx) error {
+			calls++
+			panic("handler failed")
+		})
+
+		calls = 0
+		for range 2 {
+			req := test.BuildRequest("POST", "/vehicle/mint", "{}")
+			req.Header.Set(IdempotencyKeyHeader, "key-6")
+			response, _ := panicking.Test(req)
+			assert.Equal(t, fiber.StatusInternalServerError, response.StatusCode)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	s.Run("Key of a request which didn't finish", func() {
+		requestHash := service.HashIdempotentRequest(fiber.MethodPost, "/vehicle/mint", []byte("{}"))
+		for key, lockedUntil := range map[string]time.Time{
+			"key-3": time.Now().Add(time.Minute),
+			"key-4": time.Now().Add(-time.Second),
+		} {
+			record := dbmodels.IdempotencyKey{
+				Scope:       testWalletAddress.Hex(),
+				Key:         key,
+				RequestHash: requestHash,
+				ExpiresAt:   time.Now().Add(time.Hour),
+				LockedUntil: null.TimeFrom(lockedUntil),
+			}
+			require.NoError(t, record.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+		}
+
+		// still processed by the other request
+		req := test.BuildRequest("POST", "/vehicle/mint", "{}")
+		req.Header.Set(IdempotencyKeyHeader, "key-3")
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusConflict, response.StatusCode)
+
+		// its lease ended, the retry takes the key over
+		calls = 0
+		req = test.BuildRequest("POST", "/vehicle/mint", "{}")
+		req.Header.Set(IdempotencyKeyHeader, "key-4")
+		response, _ = app.Test(req)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		assert.Equal(t, 1, calls)
+	})
+
+	s.Run("Lease of a slow request is extended", func() {
+		idempotencyKeys := service.NewIdempotencyKeysService(&s.pdb, &mockDeps.logger, 200*time.Millisecond)
+		requestHash := service.HashIdempotentRequest(fiber.MethodPost, "/vehicle/mint", []byte("{}"))
+
+		record, err := idempotencyKeys.Begin(s.ctx, testWalletAddress.Hex(), "key-5", requestHash)
+		require.NoError(t, err)
+
+		stopLease := idempotencyKeys.KeepLease(record)
+		time.Sleep(500 * time.Millisecond)
+
+		// the retry doesn't take over a key whose request still runs
+		_, err = idempotencyKeys.Begin(s.ctx, testWalletAddress.Hex(), "key-5", requestHash)
+		require.ErrorIs(t, err, service.ErrIdempotencyKeyInProgress)
+		stopLease()
+	})
+
+	s.Run("Request which lost its lease leaves the key to the retry", func() {
+		idempotencyKeys := service.NewIdempotencyKeysService(&s.pdb, &mockDeps.logger, 100*time.Millisecond)
+		requestHash := service.HashIdempotentRequest(fiber.MethodPost, "/vehicle/mint", []byte("{}"))
+
+		first, err := idempotencyKeys.Begin(s.ctx, testWalletAddress.Hex(), "key-7", requestHash)
+		require.NoError(t, err)
+		time.Sleep(200 * time.Millisecond)
+
+		retry, err := idempotencyKeys.Begin(s.ctx, testWalletAddress.Hex(), "key-7", requestHash)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, idempotencyKeys.Release(s.ctx, first), service.ErrIdempotencyKeyLeaseLost)
+		require.ErrorIs(t, idempotencyKeys.Complete(s.ctx, first, fiber.StatusOK, fiber.MIMEApplicationJSON, []byte("{}")), service.ErrIdempotencyKeyLeaseLost)
+		require.NoError(t, idempotencyKeys.Complete(s.ctx, retry, fiber.StatusCreated, fiber.MIMEApplicationJSON, []byte("{}")))
+
+		stored, err := dbmodels.FindIdempotencyKey(s.ctx, s.pdb.DBS().Reader, testWalletAddress.Hex(), "key-7")
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, stored.ResponseCode.Int)
+	})
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"time"
+)
+
+// Route groups with their own rate limit
+const (
+	RateLimitGroupIP         = "ip"
+	RateLimitGroupRead       = "read"
+	RateLimitGroupOnboarding = "onboarding"
+	RateLimitGroupWebhooks   = "webhooks"
+	RateLimitGroupAdmin      = "admin"
+)
+
+// RateLimit allows every client max requests to the route group within the window, over the limit requests are
+// rejected with a Retry-After header. Clients are the wallet of authenticated requests, so it must run after the
+// authentication, and the IP otherwise. Limits are kept in memory of every instance.
+func RateLimit(group string, max int, window time.Duration) fiber.Handler {
+	if max <= 0 || window <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: window,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return group + ":" + rateLimitClient(c)
+		},
+		LimitReached: func(_ *fiber.Ctx) error {
+			rateLimitedRequests.WithLabelValues(group).Inc()
+			return NewAPIError(ErrCodeRateLimited, "Too many requests")
+		},
+		LimiterMiddleware: limiter.SlidingWindow{},
+	})
+}
+
+// rateLimitClient wallet of the request, IP if it isn't authenticated with a JWT
+func rateLimitClient(c *fiber.Ctx) string {
+	if _, ok := c.Locals("user").(*jwt.Token); ok {
+		if walletAddress, err := getWalletAddress(c); err == nil {
+			return walletAddress.Hex()
+		}
+	}
+
+	return c.IP()
+}
+
+var rateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "dimo_oracle_http_rate_limited_total",
+	Help: "Requests rejected by the rate limit of their route group",
+}, []string{"group"})
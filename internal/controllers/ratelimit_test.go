@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/DIMO-Network/volteras-oracle/internal/test"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"testing"
+	"time"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
+
+func (s *RateLimitTestSuite) newApp(handlers ...fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			apiErr := err.(*APIError)
+			return c.Status(apiErr.Status).JSON(apiErr)
+		},
+	})
+	app.Get("/limited", append(handlers, func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})...)
+
+	return app
+}
+
+func (s *RateLimitTestSuite) get(app *fiber.App) (int, string, string) {
+	response, err := app.Test(test.BuildRequest("GET", "/limited", ""))
+	s.Require().NoError(err)
+	body, _ := io.ReadAll(response.Body)
+
+	var apiErr APIError
+	_ = json.Unmarshal(body, &apiErr)
+
+	return response.StatusCode, response.Header.Get(fiber.HeaderRetryAfter), apiErr.Code
+}
+
+func (s *RateLimitTestSuite) TestLimitPerWallet() {
+	wallet := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	limit := RateLimit(RateLimitGroupRead, 2, time.Minute)
+
+	app := s.newApp(test.AuthInjectorTestHandler("user", &wallet), limit)
+	for range 2 {
+		code, _, _ := s.get(app)
+		s.Equal(fiber.StatusOK, code)
+	}
+
+	code, retryAfter, errCode := s.get(app)
+	s.Equal(fiber.StatusTooManyRequests, code)
+	s.Equal(ErrCodeRateLimited, errCode)
+	s.NotEmpty(retryAfter)
+
+	// other wallets have their own limit, also behind the same IP
+	otherApp := s.newApp(test.AuthInjectorTestHandler("other", &other), limit)
+	code, _, _ = s.get(otherApp)
+	s.Equal(fiber.StatusOK, code)
+}
+
+func (s *RateLimitTestSuite) TestLimitPerIP() {
+	app := s.newApp(RateLimit(RateLimitGroupIP, 1, time.Minute))
+
+	code, _, _ := s.get(app)
+	s.Equal(fiber.StatusOK, code)
+
+	code, _, errCode := s.get(app)
+	s.Equal(fiber.StatusTooManyRequests, code)
+	s.Equal(ErrCodeRateLimited, errCode)
+}
+
+func (s *RateLimitTestSuite) TestDisabled() {
+	app := s.newApp(RateLimit(RateLimitGroupIP, 0, time.Minute))
+
+	for range 5 {
+		code, _, _ := s.get(app)
+		s.Equal(fiber.StatusOK, code)
+	}
+}
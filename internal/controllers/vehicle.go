@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/DIMO-Network/go-transactions"
@@ -28,12 +29,16 @@ import (
 	"github.com/tidwall/gjson"
 	"github.com/volatiletech/null/v8"
 	"regexp"
-	"slices"
 	"strings"
 )
 
 var vinRegexp, _ = regexp.Compile("^[A-HJ-NPR-Z0-9]{17}$")
 
+const (
+	defaultVehiclesPageSize = 50
+	maxVehiclesPageSize     = 100
+)
+
 type VehicleController struct {
 	settings    *config.Settings
 	logger      *zerolog.Logger
@@ -42,9 +47,10 @@ type VehicleController struct {
 	riverClient *river.Client[pgx.Tx]
 	ws          service.SDWalletsAPI
 	tr          *transactions.Client
+	events      *service.VinEventsHub
 }
 
-func NewVehiclesController(settings *config.Settings, logger *zerolog.Logger, identity service.IdentityAPI, vs *service.Vehicle, riverClient *river.Client[pgx.Tx], ws service.SDWalletsAPI, tr *transactions.Client) *VehicleController {
+func NewVehiclesController(settings *config.Settings, logger *zerolog.Logger, identity service.IdentityAPI, vs *service.Vehicle, riverClient *river.Client[pgx.Tx], ws service.SDWalletsAPI, tr *transactions.Client, events *service.VinEventsHub) *VehicleController {
 	return &VehicleController{
 		settings:    settings,
 		logger:      logger,
@@ -53,10 +59,16 @@ func NewVehiclesController(settings *config.Settings, logger *zerolog.Logger, id
 		riverClient: riverClient,
 		ws:          ws,
 		tr:          tr,
+		events:      events,
 	}
 }
 
 func getWalletAddress(c *fiber.Ctx) (common.Address, error) {
+	// fleet operators act for the owner proven by FleetAuth
+	if owner, ok := c.Locals(onBehalfOfLocal).(common.Address); ok {
+		return owner, nil
+	}
+
 	user := c.Locals("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	address, ok := claims["ethereum_address"].(string)
@@ -66,102 +78,369 @@ func getWalletAddress(c *fiber.Ctx) (common.Address, error) {
 	return common.HexToAddress(address), nil
 }
 
+// checkVinsOwner returns a forbidden error if any of the VINs is not owned by the wallet
+func (v *VehicleController) checkVinsOwner(ctx context.Context, walletAddress common.Address, dbVins dbmodels.VinSlice) error {
+	ownedVins, err := v.ownedVins(ctx, walletAddress, dbVins)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Identity API")
+	}
+	if len(ownedVins) != len(dbVins) {
+		return NewAPIError(ErrCodeNotOwned, "Vehicle not owned by wallet")
+	}
+
+	return nil
+}
+
+// ownedVins returns the records owned by the wallet in their order, see isVinOwner. The owners of minted vehicles
+// come from a single Identity API lookup of their token IDs, owners which changed on-chain are stored with the VIN.
+func (v *VehicleController) ownedVins(ctx context.Context, walletAddress common.Address, dbVins dbmodels.VinSlice) (dbmodels.VinSlice, error) {
+	tokenIDs := make([]int64, 0, len(dbVins))
+	for _, dbVin := range dbVins {
+		if sacdCoversVin(ctx, dbVin) && !dbVin.VehicleTokenID.IsZero() {
+			tokenIDs = append(tokenIDs, dbVin.VehicleTokenID.Int64)
+		}
+	}
+
+	var identityVehicles map[int64]models.Vehicle
+	if len(tokenIDs) > 0 {
+		var err error
+		identityVehicles, err = v.identity.FetchVehiclesByTokenIDs(ctx, tokenIDs)
+		if err != nil {
+			v.logger.Error().Err(err).Msg("Failed to load vehicles from Identity API")
+			return nil, err
+		}
+	}
+
+	ownedVins := make(dbmodels.VinSlice, 0, len(dbVins))
+	for _, dbVin := range dbVins {
+		if !sacdCoversVin(ctx, dbVin) {
+			continue
+		}
+
+		if dbVin.VehicleTokenID.IsZero() {
+			if isStoredOwner(walletAddress, dbVin) {
+				ownedVins = append(ownedVins, dbVin)
+			}
+			continue
+		}
+
+		identityVehicle, ok := identityVehicles[dbVin.VehicleTokenID.Int64]
+		if !ok || !common.IsHexAddress(identityVehicle.Owner) {
+			continue
+		}
+
+		owner := common.HexToAddress(identityVehicle.Owner)
+		if !isStoredOwner(owner, dbVin) {
+			if err := v.vs.SetVinOwner(ctx, dbVin.Vin, owner.Hex()); err != nil {
+				v.logger.Error().Err(err).Str(logfields.VIN, dbVin.Vin).Msg("Failed to store on-chain owner of vehicle")
+			}
+		}
+
+		if owner == walletAddress {
+			ownedVins = append(ownedVins, dbVin)
+		}
+	}
+
+	return ownedVins, nil
+}
+
+// isVinOwner checks the wallet against the on-chain owner of the minted vehicle, or the owner stored with the VIN
+// while it isn't minted. Identity API is the source of truth once minted, so vehicles transferred away are not owned
+// anymore. The owner is always loaded, not taken from the cache, which lags behind transfers. VINs not covered by the
+// SACD of a fleet operator are not owned.
+func (v *VehicleController) isVinOwner(ctx context.Context, walletAddress common.Address, record *dbmodels.Vin) bool {
+	// fleet operators only act on the VINs of their SACD
+	if !sacdCoversVin(ctx, record) {
+		return false
+	}
+
+	if record.VehicleTokenID.IsZero() {
+		return isStoredOwner(walletAddress, record)
+	}
+
+	vehicle, err := v.identity.FetchVehicleByTokenID(ctx, record.VehicleTokenID.Int64)
+	if err != nil {
+		v.logger.Error().Err(err).Str(logfields.VIN, record.Vin).Msg("Failed to load vehicle owner from Identity API")
+		return false
+	}
+
+	// we may not find vehicle (graphql still returns 200 and unmarshal creates empty objects)
+	return vehicle.TokenID != 0 && common.HexToAddress(vehicle.Owner) == walletAddress
+}
+
+func isStoredOwner(walletAddress common.Address, record *dbmodels.Vin) bool {
+	return record.OwnerAddress.Valid && common.HexToAddress(record.OwnerAddress.String) == walletAddress
+}
+
 // GetVehicles
 // @Summary Get user's vehicles
-// @Description Get user's vehicles from Identity API and add external ID (VIN)
+// @Description Pages through user's VINs with their onboarding status, last error and telemetry status.
+// @Description VINs are listed by their stored owner. Minted vehicles are checked against Identity API and completed
+// @Description with its data, the ones transferred away or missing in Identity API are left out, the ones transferred to the wallet show up once
+// @Description their owner was synced. Pages can therefore hold fewer vehicles than the limit.
 // @Produce json
-// @Success 200
-// @Security     BearerAuth
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param limit query int false "page size, up to 100"
+// @Param connectionStatus query string false "connection status"
+// @Param phase query string false "onboarding phase: verification, mint, disconnect or delete"
+// @Param make query string false "make"
+// @Param model query string false "model"
+// @Param year query int false "year"
+// @Param hasError query bool false "only VINs with (true) or without (false) an error"
+// @Param sort query string false "vin, onboardingStatus, make, model or year, prefixed with - for descending order"
+// @Success 200 {object} VehiclesResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
 // @Router /v1/vehicles [get]
 func (v *VehicleController) GetVehicles(c *fiber.Ctx) error {
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
 	}
 
-	identityVehicles, err := (v.identity).FetchVehiclesByWalletAddress(walletAddress.String())
+	filter, apiErr := parseVehicleListFilter(c)
+	if apiErr != nil {
+		return apiErr
+	}
+	filter.OwnerAddress = walletAddress.Hex()
+
+	vins, nextCursor, err := v.vs.ListVehicles(c.Context(), *filter)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to load vehicles from Identity API",
-		})
+		if errors.Is(err, service.ErrInvalidVehicleCursor) {
+			return NewAPIError(ErrCodeInvalidRequest, "Invalid cursor")
+		}
+		return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 	}
 
-	tokenIDsToCheck := []int64{}
-	vehiclesByTokenID := make(map[int64]models.Vehicle)
-	returnVehicles := []models.Vehicle{}
+	// identity-api is the source of truth for minted vehicles, only the vehicles of the page are looked up
+	identityVehicles, vins, err := v.onChainOwned(c.UserContext(), walletAddress, vins)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Identity API")
+	}
 
-	for _, vehicle := range identityVehicles {
-		tokenIDsToCheck = append(tokenIDsToCheck, vehicle.TokenID)
-		vehiclesByTokenID[vehicle.TokenID] = vehicle
+	vinsToCheck := make([]string, 0, len(vins))
+	for _, vin := range vins {
+		vinsToCheck = append(vinsToCheck, vin.Vin)
+	}
+
+	telemetryStatuses, err := v.vs.GetTelemetryStatusByVins(c.Context(), vinsToCheck)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load telemetry status from Vehicle Service")
+	}
+
+	returnVehicles := make([]models.Vehicle, 0, len(vins))
+	for _, vin := range vins {
+		vehicle := toVehicle(vin)
+
+		if identityVehicle, ok := identityVehicles[vin.VehicleTokenID.Int64]; ok && vin.VehicleTokenID.Valid {
+			identityVehicle.VIN = vehicle.VIN
+			identityVehicle.ConnectionStatus = vehicle.ConnectionStatus
+			identityVehicle.DisconnectionStatus = vehicle.DisconnectionStatus
+			identityVehicle.Onboarding = vehicle.Onboarding
+			vehicle = identityVehicle
+		}
+
+		vehicle.Telemetry = toTelemetryStatus(telemetryStatuses[vin.Vin])
+		returnVehicles = append(returnVehicles, vehicle)
+	}
+
+	return c.JSON(VehiclesResponse{
+		Vehicles:   returnVehicles,
+		NextCursor: nextCursor,
+	})
+}
+
+// onChainOwned looks up the minted VINs in Identity API and returns them with the VINs the wallet still holds. VINs
+// transferred away get their new owner stored, so they are listed for it from now on. Minted VINs Identity API doesn't
+// return can't be shown to be held by the wallet and are left out.
+func (v *VehicleController) onChainOwned(ctx context.Context, walletAddress common.Address, vins dbmodels.VinSlice) (map[int64]models.Vehicle, dbmodels.VinSlice, error) {
+	tokenIDs := make([]int64, 0, len(vins))
+	for _, vin := range vins {
+		if vin.VehicleTokenID.Valid {
+			tokenIDs = append(tokenIDs, vin.VehicleTokenID.Int64)
+		}
+	}
+	if len(tokenIDs) == 0 {
+		return map[int64]models.Vehicle{}, vins, nil
 	}
 
-	vins, err := v.vs.GetVinsByTokenIDs(c.Context(), tokenIDsToCheck)
+	identityVehicles, err := v.identity.FetchVehiclesByTokenIDs(ctx, tokenIDs)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to load vehicles from Vehicle Service",
-		})
+		v.logger.Error().Err(err).Msg("Failed to load vehicles from Identity API")
+		return nil, nil, err
 	}
 
+	held := make(dbmodels.VinSlice, 0, len(vins))
 	for _, vin := range vins {
-		tid, tidErr := vin.VehicleTokenID.Value()
-		if tidErr != nil {
+		if !vin.VehicleTokenID.Valid {
+			held = append(held, vin)
 			continue
 		}
 
-		vehicle, ok := vehiclesByTokenID[tid.(int64)]
-		if !ok {
+		identityVehicle, ok := identityVehicles[vin.VehicleTokenID.Int64]
+		if !ok || !common.IsHexAddress(identityVehicle.Owner) {
+			v.logger.Warn().Str(logfields.VIN, vin.Vin).Int64("vehicleTokenId", vin.VehicleTokenID.Int64).Msg("Minted vehicle not found in Identity API, left out")
 			continue
 		}
 
-		vehicle.VIN = vin.Vin
-		vehicle.ConnectionStatus = vin.ConnectionStatus.String
-		vehicle.DisconnectionStatus = vin.DisconnectionStatus.String
-		returnVehicles = append(returnVehicles, vehicle)
+		owner := common.HexToAddress(identityVehicle.Owner)
+		if owner == walletAddress {
+			held = append(held, vin)
+			continue
+		}
+
+		if err := v.vs.SetVinOwner(ctx, vin.Vin, owner.Hex()); err != nil {
+			v.logger.Error().Err(err).Str(logfields.VIN, vin.Vin).Msg("Failed to store owner of transferred vehicle")
+		}
 	}
 
-	return c.JSON(VehiclesResponse{
-		Vehicles: returnVehicles,
-	})
+	return identityVehicles, held, nil
 }
 
+// parseVehicleListFilter parses the filters, sorting and page of the vehicles listing
+func parseVehicleListFilter(c *fiber.Ctx) (*service.VehicleListFilter, *APIError) {
+	filter := service.VehicleListFilter{
+		ConnectionStatus: strings.TrimSpace(c.Query("connectionStatus")),
+		Make:             strings.TrimSpace(c.Query("make")),
+		Model:            strings.TrimSpace(c.Query("model")),
+		Cursor:           strings.TrimSpace(c.Query("cursor")),
+		Limit:            c.QueryInt("limit", defaultVehiclesPageSize),
+	}
+
+	if filter.Limit <= 0 || filter.Limit > maxVehiclesPageSize {
+		return nil, NewAPIError(ErrCodeInvalidRequest, fmt.Sprintf("Limit must be between 1 and %d", maxVehiclesPageSize))
+	}
+
+	if phase := strings.TrimSpace(c.Query("phase")); phase != "" {
+		minStatus, maxStatus, ok := onboarding.GetPhaseStatusRange(phase)
+		if !ok {
+			return nil, NewAPIError(ErrCodeInvalidRequest, "Invalid phase")
+		}
+		filter.MinStatus = &minStatus
+		filter.MaxStatus = &maxStatus
+	}
+
+	if c.Query("year") != "" {
+		filter.Year = c.QueryInt("year", 0)
+		if filter.Year <= 0 {
+			return nil, NewAPIError(ErrCodeInvalidRequest, "Invalid year")
+		}
+	}
+
+	if c.Query("hasError") != "" {
+		hasError, err := strconv.ParseBool(c.Query("hasError"))
+		if err != nil {
+			return nil, NewAPIError(ErrCodeInvalidRequest, "Invalid hasError")
+		}
+		filter.HasError = &hasError
+	}
+
+	sort := strings.TrimSpace(c.Query("sort"))
+	if strings.HasPrefix(sort, "-") {
+		filter.Desc = true
+		sort = sort[1:]
+	}
+	if sort != "" && !service.IsVehicleSort(sort) {
+		return nil, NewAPIError(ErrCodeInvalidRequest, "Invalid sort")
+	}
+	filter.Sort = sort
+
+	return &filter, nil
+}
+
+// getIdentityVehicle loads the vehicle from Identity API, using the cache first
+func (v *VehicleController) getIdentityVehicle(ctx context.Context, tokenID int64) (*models.Vehicle, error) {
+	if cached, err := v.identity.GetCachedVehicleByTokenID(tokenID); err == nil {
+		copied := *cached
+		return &copied, nil
+	}
+
+	vehicle, err := v.identity.FetchVehicleByTokenID(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := *vehicle
+	return &copied, nil
+}
+
+// toVehicle builds the vehicle from stored data, for VINs not minted yet or missing in Identity API
+func toVehicle(vin *dbmodels.Vin) models.Vehicle {
+	vehicle := models.Vehicle{
+		VIN:                 vin.Vin,
+		TokenID:             vin.VehicleTokenID.Int64,
+		Owner:               vin.OwnerAddress.String,
+		ConnectionStatus:    vin.ConnectionStatus.String,
+		DisconnectionStatus: vin.DisconnectionStatus.String,
+		DataServices:        vin.DataServices,
+		Definition: models.Definition{
+			ID:    vin.DeviceDefinitionID.String,
+			Make:  vin.DefinitionMake.String,
+			Model: vin.DefinitionModel.String,
+			Year:  vin.DefinitionYear.Int,
+		},
+		SyntheticDevice: models.SyntheticDevice{
+			TokenID: vin.SyntheticTokenID.Int64,
+		},
+		Onboarding: &models.OnboardingState{
+			Status:  onboarding.GetStatus(vin.OnboardingStatus),
+			Details: onboarding.GetDetailedStatus(vin.OnboardingStatus),
+		},
+	}
+
+	if vin.OperationErrorCode.Valid || vin.OperationErrorDescription.Valid {
+		vehicle.Onboarding.LastError = &models.OperationError{
+			Code:        vin.OperationErrorCode.String,
+			Type:        vin.OperationErrorType.String,
+			Description: vin.OperationErrorDescription.String,
+		}
+	}
+
+	return vehicle
+}
+
+// VehiclesResponse vehicles of the wallet
 type VehiclesResponse struct {
 	Vehicles []models.Vehicle `json:"vehicles"`
+	// cursor of the next page, missing on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // GetVehicleByExternalID
 // @Summary Get user's vehicle by external ID
-// @Description Get user's vehicle by external ID (VIN)
+// @Description Get user's vehicle by external ID (VIN), with its connection and onboarding status, the address of
+// @Description the synthetic device and the last forwarded value of every signal
 // @Produce json
-// @Success 200
-// @Security     BearerAuth
+// @Param externalID path string true "VIN"
+// @Success 200 {object} VehicleResponse
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
 // @Router /v1/vehicle/{externalID} [get]
 func (v *VehicleController) GetVehicleByExternalID(c *fiber.Ctx) error {
 	externalID := c.Params("externalID")
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
 	}
 
-	identityVehicles, err := (v.identity).FetchVehiclesByWalletAddress(walletAddress.String())
+	identityVehicles, err := (v.identity).FetchVehiclesByWalletAddress(c.UserContext(), walletAddress.String())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to load vehicles from Identity API",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Identity API")
 	}
 
 	vin, err := v.vs.GetVehicleByExternalID(c.Context(), externalID)
 	if err != nil {
 		if errors.Is(err, service.ErrVehicleNotFound) {
-			return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicle")
+			return NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to load vehicle from Database",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to load vehicle from Database")
+	}
+
+	if !sacdCoversVin(c.UserContext(), vin) {
+		return NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
 	}
 
 	vehiclesByTokenID := make(map[int64]models.Vehicle)
@@ -171,21 +450,93 @@ func (v *VehicleController) GetVehicleByExternalID(c *fiber.Ctx) error {
 
 	tid, tidErr := vin.VehicleTokenID.Value()
 	if tidErr != nil {
-		return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicle")
+		return NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
 	}
 
 	vehicle, ok := vehiclesByTokenID[tid.(int64)]
 	if !ok {
-		return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicle")
+		return NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
 	}
 
-	vehicle.VIN = vin.Vin
+	if err := v.completeVehicle(c.Context(), &vehicle, vin); err != nil {
+		return err
+	}
 
 	return c.JSON(VehicleResponse{
 		Vehicle: vehicle,
 	})
 }
 
+// completeVehicle adds the stored statuses, the synthetic device address and the telemetry of the VIN to the vehicle
+func (v *VehicleController) completeVehicle(ctx context.Context, vehicle *models.Vehicle, vin *dbmodels.Vin) error {
+	vehicle.VIN = vin.Vin
+
+	// the oracle is the source of the connection and onboarding status
+	stored := toVehicle(vin)
+	vehicle.ConnectionStatus = stored.ConnectionStatus
+	vehicle.DisconnectionStatus = stored.DisconnectionStatus
+	vehicle.DataServices = stored.DataServices
+	vehicle.Onboarding = stored.Onboarding
+
+	if vin.WalletIndex.Valid {
+		sdAddress, err := v.ws.GetAddress(uint32(vin.WalletIndex.Int64))
+		if err != nil {
+			v.logger.Error().Err(err).Str(logfields.VIN, vin.Vin).Msg("Failed to get synthetic device address")
+		} else {
+			vehicle.SyntheticDevice.Address = sdAddress.Hex()
+		}
+	}
+
+	telemetryStatuses, err := v.vs.GetTelemetryStatusByVins(ctx, []string{vin.Vin})
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load telemetry status from Database")
+	}
+	vehicle.Telemetry = toTelemetryStatus(telemetryStatuses[vin.Vin])
+
+	signals, err := v.vs.GetTelemetrySignals(ctx, vin.Vin)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load telemetry signals from Database")
+	}
+	if len(signals) > 0 {
+		if vehicle.Telemetry == nil {
+			vehicle.Telemetry = &models.TelemetryStatus{}
+		}
+		vehicle.Telemetry.Signals = toSignalValues(signals)
+	}
+
+	return nil
+}
+
+func toTelemetryStatus(record *dbmodels.TelemetryStatus) *models.TelemetryStatus {
+	if record == nil {
+		return nil
+	}
+
+	return &models.TelemetryStatus{
+		LastReceivedAt:  record.LastReceivedAt.Ptr(),
+		LastForwardedAt: record.LastForwardedAt.Ptr(),
+		LastError:       record.LastError.String,
+		LastErrorAt:     record.LastErrorAt.Ptr(),
+	}
+}
+
+func toSignalValues(signals dbmodels.TelemetrySignalSlice) map[string]models.SignalValue {
+	values := make(map[string]models.SignalValue, len(signals))
+	for _, signal := range signals {
+		var value any
+		if err := signal.Value.Unmarshal(&value); err != nil {
+			continue
+		}
+		values[signal.Name] = models.SignalValue{
+			Value:     value,
+			Timestamp: signal.Timestamp,
+		}
+	}
+
+	return values
+}
+
+// VehicleResponse is a vehicle with its onboarding status
 type VehicleResponse struct {
 	Vehicle models.Vehicle `json:"vehicle"`
 }
@@ -193,75 +544,92 @@ type VehicleResponse struct {
 // RegisterVehicle
 // @Summary Checks and registers existing vehicle in internal oracle mapping DB
 // @Description Checks and registers existing vehicle in internal oracle mapping DB
+// @Accept json
 // @Produce json
-// @Success 200
-// @Security     BearerAuth
+// @Param params body VehicleRegisterPayload true "VIN and vehicle token ID"
+// @Success 200 {object} VehicleResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
 // @Router /v1/vehicle/register [post]
 func (v *VehicleController) RegisterVehicle(c *fiber.Ctx) error {
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
 	}
 
 	vinToRegister := gjson.GetBytes(c.Body(), "vin")
 	tokenIDToRegister := gjson.GetBytes(c.Body(), "token_id")
 
 	if !vinToRegister.Exists() || len(vinToRegister.String()) != 17 || !tokenIDToRegister.Exists() {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Missing or invalid VIN or Token ID",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Missing or invalid VIN or Token ID")
+	}
+
+	vehicle, err := v.registerVin(c.UserContext(), walletAddress, vinToRegister.String(), tokenIDToRegister.Int())
+	if err != nil {
+		return err
 	}
 
+	return c.JSON(VehicleResponse{
+		Vehicle: *vehicle,
+	})
+}
+
+// registerVin maps the VIN to the vehicle token of the wallet, which was minted outside the oracle
+func (v *VehicleController) registerVin(ctx context.Context, walletAddress common.Address, vinToRegister string, tokenIDToRegister int64) (*models.Vehicle, error) {
 	// Check if the vehicle is available in identity-api
-	identityVehicle, err := (v.identity).FetchVehicleByTokenID(tokenIDToRegister.Int())
+	identityVehicle, err := (v.identity).FetchVehicleByTokenID(ctx, tokenIDToRegister)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to load vehicle from Identity API",
-		})
+		return nil, NewAPIError(ErrCodeInternal, "Failed to load vehicle from Identity API")
 	}
 
 	// we may not find vehicle (graphql still returns 200 and unmarshal creates empty objects)
 	if identityVehicle.TokenID == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Vehicle not found",
-		})
+		return nil, NewAPIError(ErrCodeNotFound, "Vehicle not found")
 	}
 
 	// vehicle can't be owned by someone else
 	if identityVehicle.Owner != walletAddress.String() {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Vehicle not owned by wallet",
-		})
+		return nil, NewAPIError(ErrCodeNotOwned, "Vehicle not owned by wallet")
 	}
 
+	if !sacdCoversVehicle(ctx, identityVehicle.TokenID) {
+		return nil, NewAPIError(ErrCodeSacdNotAllowed, "Vehicle not covered by the SACD")
+	}
+
+	return v.storeRegisteredVin(ctx, walletAddress, vinToRegister, identityVehicle)
+}
+
+// storeRegisteredVin maps the VIN to the identity vehicle of the wallet
+func (v *VehicleController) storeRegisteredVin(ctx context.Context, walletAddress common.Address, vinToRegister string, identityVehicle *models.Vehicle) (*models.Vehicle, error) {
+	tokenIDToRegister := identityVehicle.TokenID
+
 	var newVin dbmodels.Vin
 	newVin.OnboardingStatus = onboarding.OnboardingStatusSubmitUnknown
-	newVin.Vin = vinToRegister.String()
-	newVin.VehicleTokenID = null.Int64From(tokenIDToRegister.Int())
+	newVin.Vin = vinToRegister
+	newVin.VehicleTokenID = null.Int64From(tokenIDToRegister)
+	newVin.OwnerAddress = null.StringFrom(walletAddress.Hex())
 
 	// check if this VIN is already registered
-	vin, err := v.vs.GetVehicleByExternalID(c.Context(), vinToRegister.String())
+	vin, err := v.vs.GetVehicleByExternalID(ctx, vinToRegister)
 
 	if err != nil {
 		// if now found, we're still good, so fail only on other errors
 		if !errors.Is(err, service.ErrVehicleNotFound) {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicle from Database",
-			})
+			return nil, NewAPIError(ErrCodeInternal, "Failed to load vehicle from Database")
 		}
 	}
 
 	if vin != nil {
 		// If registered VIN has TokenID, but it's different, that's bad
-		if !vin.VehicleTokenID.IsZero() && vin.VehicleTokenID.Int64 != tokenIDToRegister.Int() {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-				"error": "Vehicle VIN assigned to another TokenID",
-			})
+		if !vin.VehicleTokenID.IsZero() && vin.VehicleTokenID.Int64 != tokenIDToRegister {
+			return nil, NewAPIError(ErrCodeConflict, "Vehicle VIN assigned to another TokenID")
 		}
 
 		newVin = *vin
+		newVin.OwnerAddress = null.StringFrom(walletAddress.Hex())
 	}
 
 	if identityVehicle.SyntheticDevice.TokenID != 0 {
@@ -270,70 +638,75 @@ func (v *VehicleController) RegisterVehicle(c *fiber.Ctx) error {
 
 	if identityVehicle.Definition.ID != "" {
 		newVin.DeviceDefinitionID = null.StringFrom(identityVehicle.Definition.ID)
+		newVin.DefinitionMake = null.StringFrom(identityVehicle.Definition.Make)
+		newVin.DefinitionModel = null.StringFrom(identityVehicle.Definition.Model)
+		newVin.DefinitionYear = null.IntFrom(identityVehicle.Definition.Year)
+	}
+
+	// webhook events of the VIN go to the developer client it was registered through
+	if clientID, ok := clientIDOf(ctx); ok {
+		newVin.ClientID = null.StringFrom(clientID)
 	}
 
 	// We allow to either insert new row or update Synthetic TokenID for existing row
-	err = v.vs.InsertOrUpdateVin(c.Context(), &newVin)
+	err = v.vs.InsertOrUpdateVin(ctx, &newVin)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to register Vehicle",
-		})
+		return nil, NewAPIError(ErrCodeInternal, "Failed to register Vehicle")
 	}
 
-	identityVehicle.VIN = vinToRegister.String()
+	identityVehicle.VIN = vinToRegister
 
-	return c.JSON(VehicleResponse{
-		Vehicle: *identityVehicle,
-	})
+	return identityVehicle, nil
 }
 
+// VehicleRegisterPayload maps an already minted vehicle to its VIN
 type VehicleRegisterPayload struct {
 	Vin     string `json:"vin"`
 	TokenID string `json:"token_id"`
 }
 
+// VinsGetParams VINs of a request
 type VinsGetParams struct {
 	Vins []string `json:"vins" query:"vins"`
 }
 
 // GetVerificationStatusForVins
 // @Summary Get verification status for each of the submitted VINs
+// @Description Invalid and repeated VINs are returned with status Rejected and a reason code
 // @Produce json
-// @Success 200
-// @Security     BearerAuth
+// @Param vins query []string true "VINs"
+// @Success 200 {object} StatusForVinsResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
 // @Router /v1/vehicle/verify [get]
 func (v *VehicleController) GetVerificationStatusForVins(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
 	params := new(VinsGetParams)
 	if err := c.QueryParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse VINs",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse VINs")
+	}
+
+	if err := v.checkBatchSize(len(params.Vins)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Interface("vins", params.Vins).Str(logfields.FunctionName, "GetVerificationStatusForVins").Logger()
 	localLog.Debug().Interface("vins", params.Vins).Msg("Checking Verification Status for Vins")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.Vins))
 	for _, vin := range params.Vins {
-		strippedVin := strings.TrimSpace(vin)
-		if v.isValidVin(strippedVin) {
+		if strippedVin, ok := batch.accept(vin); ok {
 			validVins = append(validVins, strippedVin)
 		}
 	}
 
-	if len(validVins) != len(params.Vins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid VINs provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs", len(validVins))
 
 	statuses := make([]VinStatus, 0, len(validVins))
@@ -342,12 +715,14 @@ func (v *VehicleController) GetVerificationStatusForVins(c *fiber.Ctx) error {
 		dbVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
 			if errors.Is(err, service.ErrVehicleNotFound) {
-				return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicles")
+				return NewAPIError(ErrCodeNotFound, "Could not find Vehicles")
 			}
 
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
+		}
+
+		if err := v.checkVinsOwner(c.UserContext(), walletAddress, dbVins); err != nil {
+			return err
 		}
 
 		indexedVins := make(map[string]*dbmodels.Vin)
@@ -368,20 +743,24 @@ func (v *VehicleController) GetVerificationStatusForVins(c *fiber.Ctx) error {
 					Vin:     vin,
 					Status:  onboarding.GetVerificationStatus(dbVin.OnboardingStatus),
 					Details: onboarding.GetDetailedStatus(dbVin.OnboardingStatus),
+					Reason:  failureErrCode(dbVin.OnboardingStatus),
 				})
 			}
 		}
 	}
 
 	return c.JSON(StatusForVinsResponse{
-		Statuses: statuses,
+		Statuses: append(statuses, batch.rejected...),
 	})
 }
 
+// VinWithCountryCode is a VIN to verify, the country code helps decoding it
 type VinWithCountryCode struct {
 	Vin         string `json:"vin"`
 	CountryCode string `json:"countryCode"`
 }
+
+// SubmitVinVerificationParams VINs to verify
 type SubmitVinVerificationParams struct {
 	Vins []VinWithCountryCode `json:"vins"`
 }
@@ -408,78 +787,104 @@ func (v *VehicleController) isValidVin(vin string) bool {
 
 // SubmitVerificationForVins
 // @Summary Submits VINs with country codes for verification
-// @Description Decodes the VINs to Device Definitions and validates vendor connectivity
+// @Description Decodes the VINs to Device Definitions and validates vendor connectivity.
+// @Description Invalid VINs don't fail the request, they are returned with status Rejected and a reason code.
+// @Accept json
 // @Produce json
-// @Success 200
+// @Param params body SubmitVinVerificationParams true "VINs with country codes"
+// @Success 200 {object} StatusForVinsResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
 // @Security BearerAuth
 // @Router /v1/vehicle/verify [post]
 func (v *VehicleController) SubmitVerificationForVins(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
 	params := new(SubmitVinVerificationParams)
 	if err := c.BodyParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse VINs",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse VINs")
 	}
+
+	if err := v.checkBatchSize(len(params.Vins)); err != nil {
+		return err
+	}
+
 	localLog := v.logger.With().Interface("vins", params.Vins).Str(logfields.FunctionName, "SubmitVerificationForVins").Logger()
 	localLog.Debug().Interface("vins", params.Vins).Msg("Submitting Verification for VINs")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.Vins))
 	validVinsWithCountryCode := make([]VinWithCountryCode, 0, len(params.Vins))
 	for _, paramVin := range params.Vins {
-		strippedVin := strings.TrimSpace(paramVin.Vin)
-		strippedCountryCode := strings.TrimSpace(paramVin.CountryCode)
-		if v.isValidVin(strippedVin) {
-			validVins = append(validVins, strippedVin)
-			validVinsWithCountryCode = append(validVinsWithCountryCode, VinWithCountryCode{Vin: strippedVin, CountryCode: strippedCountryCode})
+		strippedVin, ok := batch.accept(paramVin.Vin)
+		if !ok {
+			continue
 		}
-	}
 
-	if len(validVins) != len(params.Vins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid VINs provided",
-		})
+		strippedCountryCode := strings.TrimSpace(paramVin.CountryCode)
+		validVins = append(validVins, strippedVin)
+		validVinsWithCountryCode = append(validVinsWithCountryCode, VinWithCountryCode{Vin: strippedVin, CountryCode: strippedCountryCode})
 	}
 
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
+	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs", len(validVins))
+
+	vinStatuses, err := v.submitVerification(c.UserContext(), walletAddress, batch, validVinsWithCountryCode)
+	if err != nil {
+		return err
 	}
 
-	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs", len(validVins))
+	return c.JSON(StatusForVinsResponse{
+		Statuses: append(vinStatuses, batch.rejected...),
+	})
+}
 
-	vinStatuses := make([]VinStatus, 0, len(params.Vins))
+// submitVerification inserts verify jobs of the accepted VINs which can be verified, VINs owned by another wallet are
+// dropped from the batch
+func (v *VehicleController) submitVerification(ctx context.Context, walletAddress common.Address, batch *vinBatch, vins []VinWithCountryCode) ([]VinStatus, error) {
+	localLog := v.logger.With().Str(logfields.FunctionName, "submitVerification").Logger()
+
+	validVins := make([]string, 0, len(vins))
+	for _, vin := range vins {
+		validVins = append(validVins, vin.Vin)
+	}
+
+	vinStatuses := make([]VinStatus, 0, len(vins))
 
 	if len(validVins) > 0 {
-		dbVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
+		dbVins, err := v.vs.GetVehiclesByVins(ctx, validVins)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicles")
+				return nil, NewAPIError(ErrCodeNotFound, "Could not find Vehicles")
 			}
 
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return nil, NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
 		indexedDbVins := make(map[string]*dbmodels.Vin)
-		for _, vin := range dbVins {
+		for _, vin := range batch.claim(ctx, walletAddress, dbVins) {
 			indexedDbVins[vin.Vin] = vin
 		}
 
-		for _, vin := range validVinsWithCountryCode {
+		for _, vin := range vins {
+			if batch.isDropped(vin.Vin) {
+				continue
+			}
+
 			dbVin, ok := indexedDbVins[vin.Vin]
 			if !ok {
 				dbVin = &dbmodels.Vin{
 					Vin:              vin.Vin,
 					OnboardingStatus: onboarding.OnboardingStatusSubmitUnknown,
+					OwnerAddress:     null.StringFrom(walletAddress.Hex()),
 				}
 			}
 
 			if v.canSubmitVerificationJob(dbVin) {
 				localLog.Debug().Str(logfields.VIN, vin.Vin).Str(logfields.CountryCode, vin.CountryCode).Msg("Submitting VIN verification job")
-				_, err = v.riverClient.Insert(c.Context(), onboarding.VerifyArgs{
+				_, err = v.riverClient.Insert(ctx, onboarding.VerifyArgs{
 					VIN:         vin.Vin,
 					CountryCode: vin.CountryCode,
 				}, nil)
@@ -490,6 +895,7 @@ func (v *VehicleController) SubmitVerificationForVins(c *fiber.Ctx) error {
 						Vin:     vin.Vin,
 						Status:  "Failure",
 						Details: onboarding.GetDetailedStatus(onboarding.OnboardingStatusSubmitFailure),
+						Reason:  ErrCodeSubmitFailed,
 					})
 				} else {
 					v.logger.Debug().Str(logfields.VIN, vin.Vin).Str(logfields.CountryCode, vin.CountryCode).Msg("VIN verification job submitted")
@@ -505,105 +911,116 @@ func (v *VehicleController) SubmitVerificationForVins(c *fiber.Ctx) error {
 					Vin:     vin.Vin,
 					Status:  onboarding.GetVerificationStatus(dbVin.OnboardingStatus),
 					Details: onboarding.GetDetailedStatus(dbVin.OnboardingStatus),
+					Reason:  ErrCodeInvalidStatus,
 				})
 			}
 
-			err = v.vs.InsertOrUpdateVin(c.Context(), dbVin)
+			// webhook events of the VIN go to the developer client it was submitted through
+			if clientID, ok := clientIDOf(ctx); ok {
+				dbVin.ClientID = null.StringFrom(clientID)
+			}
+
+			err = v.vs.InsertOrUpdateVin(ctx, dbVin)
 
 			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": fmt.Sprintf("Failed to submit verification for vin: %s", vin),
-				})
+				return nil, NewAPIError(ErrCodeInternal, fmt.Sprintf("Failed to submit verification for vin: %s", vin))
 			}
 			localLog.Debug().Str(logfields.VIN, vin.Vin).Str(logfields.CountryCode, vin.CountryCode).Msg("Submitted Verification for VIN")
 		}
 	}
 
-	return c.JSON(StatusForVinsResponse{
-		Statuses: vinStatuses,
-	})
+	return vinStatuses, nil
 }
 
+// VinStatus is the onboarding status of a single VIN
 type VinStatus struct {
 	Vin     string `json:"vin"`
 	Status  string `json:"status"`
 	Details string `json:"details"`
+	Reason  string `json:"reason,omitempty"` // set when the VIN was not processed, see the ErrCode* codes
 }
 
+// StatusForVinsResponse statuses of the submitted VINs
 type StatusForVinsResponse struct {
 	Statuses []VinStatus `json:"statuses"`
 }
 
+// GetMintDataForVins
+// @Summary Get the typed data to be signed for minting each of the submitted VINs
+// @Description VINs which can't be minted are left out and returned as rejected, with a reason code
+// @Produce json
+// @Param vins query []string true "VINs"
+// @Success 200 {object} MintDataForVins
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/mint [get]
 func (v *VehicleController) GetMintDataForVins(c *fiber.Ctx) error {
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
 	}
 
 	params := new(VinsGetParams)
 	if err := c.QueryParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse VINs",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse VINs")
+	}
+
+	if err := v.checkBatchSize(len(params.Vins)); err != nil {
+		return err
 	}
+
 	localLog := v.logger.With().Interface("vins", params.Vins).Str(logfields.FunctionName, "GetMintDataForVins").Logger()
 	localLog.Debug().Msg("Checking Verification Status for Vins")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.Vins))
 	for _, vin := range params.Vins {
-		strippedVin := strings.TrimSpace(vin)
-		if v.isValidVin(strippedVin) {
+		if strippedVin, ok := batch.accept(vin); ok {
 			validVins = append(validVins, strippedVin)
 		}
 	}
 
-	if len(validVins) != len(params.Vins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid VINs provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs for get mint", len(validVins))
 
 	mintingData := make([]VinTransactionData, 0, len(validVins))
 
 	if len(validVins) > 0 {
+		knownVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
+		if err != nil {
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
+		}
+
+		batch.known(validVins, knownVins)
+		ownedRecords := batch.owned(c.UserContext(), walletAddress, knownVins)
+		for _, record := range ownedRecords {
+			if !onboarding.IsVerified(record.OnboardingStatus) {
+				batch.drop(record.Vin, ErrCodeVinNotVerified, "VIN is not verified")
+			} else if onboarding.IsMinted(record.OnboardingStatus) && record.ConnectionStatus.String != kafka.OperationStatusFailed {
+				batch.drop(record.Vin, ErrCodeAlreadyMinted, "VIN is already minted")
+			}
+		}
+		ownedVins := vinsOf(ownedRecords)
+
 		dbVins, err := v.vs.GetVehiclesByVinsAndOnboardingStatusRange(
 			c.Context(),
-			validVins,
+			ownedVins,
 			onboarding.OnboardingStatusVendorValidationSuccess,
 			onboarding.OnboardingStatusMintFailure,
 			[]int{onboarding.OnboardingStatusBurnSDSuccess, onboarding.OnboardingStatusBurnVehicleSuccess},
 		)
-		if err != nil {
-			if errors.Is(err, service.ErrVehicleNotFound) {
-				return fiber.NewError(fiber.StatusBadRequest, "Could not find Vehicles")
-			}
-
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+		if err != nil && !errors.Is(err, service.ErrVehicleNotFound) {
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
 		mintedVins, err := v.vs.GetVehiclesByVinsAndOnboardingStatus(
 			c.Context(),
-			validVins,
+			ownedVins,
 			onboarding.OnboardingStatusMintSuccess,
 		)
 		if err != nil {
 			if !errors.Is(err, service.ErrVehicleNotFound) {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Failed to load vehicles from Database",
-				})
+				return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 			}
 		}
 
@@ -615,25 +1032,15 @@ func (v *VehicleController) GetMintDataForVins(c *fiber.Ctx) error {
 		}
 
 		dbVins = append(dbVins, vendorFailedMintedVins...)
-
-		if len(dbVins) != len(validVins) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Some of the VINs are not verified or already onboarded",
-			})
-		}
-
-		indexedVins := make(map[string]*dbmodels.Vin)
-		for _, vin := range dbVins {
-			indexedVins[vin.Vin] = vin
-		}
+		batch.eligible(ownedVins, dbVins, "Onboarding of VIN is in progress")
 
 		for _, dbVin := range dbVins {
 			localLog.Debug().Str(logfields.DefinitionID, dbVin.DeviceDefinitionID.String).Msgf("getting definition for vin")
-			definition, err := v.identity.GetDeviceDefinitionByID(dbVin.DeviceDefinitionID.String)
+			definition, err := v.identity.GetDeviceDefinitionByID(c.UserContext(), dbVin.DeviceDefinitionID.String)
 			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Failed to load device definition",
-				})
+				localLog.Error().Err(err).Str(logfields.VIN, dbVin.Vin).Msg("Failed to load device definition")
+				batch.drop(dbVin.Vin, ErrCodeInternal, "Failed to load device definition")
+				continue
 			}
 
 			var typedData *signer.TypedData
@@ -673,16 +1080,13 @@ func (v *VehicleController) GetMintDataForVins(c *fiber.Ctx) error {
 
 				if !ok {
 					v.logger.Error().Err(err).Msg("Failed to set integration or connection token ID")
-					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-						"error": "Failed to set integration or connection token ID",
-					})
+					return NewAPIError(ErrCodeInternal, "Failed to set integration or connection token ID")
 				}
 
 			} else {
 				if dbVin.ConnectionStatus.String != kafka.OperationStatusFailed {
-					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-						"error": "VIN already fully minted and connected or connection in progress",
-					})
+					batch.drop(dbVin.Vin, ErrCodeAlreadyMinted, "VIN already fully minted and connected or connection in progress")
+					continue
 				}
 			}
 
@@ -700,9 +1104,11 @@ func (v *VehicleController) GetMintDataForVins(c *fiber.Ctx) error {
 
 	return c.JSON(MintDataForVins{
 		VinMintingData: mintingData,
+		Rejected:       batch.rejected,
 	})
 }
 
+// SacdInput permissions granted with the minted vehicle
 type SacdInput struct {
 	Grantee     common.Address
 	Permissions int64
@@ -710,17 +1116,21 @@ type SacdInput struct {
 	Source      string
 }
 
+// VinTransactionData is the EIP-712 typed data to mint a VIN, sent back with the signature of the wallet
 type VinTransactionData struct {
 	Vin       string            `json:"vin"`
 	TypedData *signer.TypedData `json:"typedData,omitempty"`
 	Signature hexutil.Bytes     `json:"signature,omitempty"`
 }
 
+// MintDataForVins minting data of the VINs, to be signed
 type MintDataForVins struct {
 	VinMintingData []VinTransactionData `json:"vinMintingData"`
 	Sacd           SacdInput            `json:"sacd,omitempty"`
+	Rejected       []VinStatus          `json:"rejected,omitempty"` // VINs left out of the minting data
 }
 
+// VinUserOperationData is the ERC-4337 user operation for a VIN and its hash, sent back with the signature of the hash
 type VinUserOperationData struct {
 	Vin           string                 `json:"vin"`
 	UserOperation *zerodev.UserOperation `json:"userOperation"`
@@ -728,55 +1138,60 @@ type VinUserOperationData struct {
 	Signature     hexutil.Bytes          `json:"signature,omitempty"`
 }
 
+// DisconnectDataForVins user operations burning the synthetic devices of the VINs, to be signed
 type DisconnectDataForVins struct {
 	VinDisconnectData []VinUserOperationData `json:"vinDisconnectData"`
+	Rejected          []VinStatus            `json:"rejected,omitempty"` // VINs left out of the disconnect data
 }
 
+// SubmitMintDataForVins
+// @Summary Submits the signed minting data
+// @Description Starts minting of the VINs, VINs which can't be minted are returned with status Rejected and a reason code
+// @Accept json
+// @Produce json
+// @Param params body MintDataForVins true "signed typed data of the VINs"
+// @Success 200 {object} StatusForVinsResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/mint [post]
 func (v *VehicleController) SubmitMintDataForVins(c *fiber.Ctx) error {
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
 	}
 
 	params := new(MintDataForVins)
 	if err := c.BodyParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse minting data",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse minting data")
+	}
+
+	if err := v.checkBatchSize(len(params.VinMintingData)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Str(logfields.FunctionName, "SubmitMintDataForVins").Logger()
 	localLog.Debug().Msg("Submitting VINs to mint")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.VinMintingData))
 	validVinsMintingData := make([]VinTransactionData, 0, len(params.VinMintingData))
 	for _, paramVin := range params.VinMintingData {
-		validatedVinMintingData, err := v.getValidatedMintingData(&paramVin, walletAddress)
+		strippedVin, ok := batch.accept(paramVin.Vin)
+		if !ok {
+			continue
+		}
+
+		validatedVinMintingData, err := v.getValidatedMintingData(c.UserContext(), &paramVin, walletAddress)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid minting data",
-			})
+			batch.drop(strippedVin, ErrCodeInvalidData, "Invalid minting data")
+			continue
 		}
 
 		validVins = append(validVins, validatedVinMintingData.Vin)
 		validVinsMintingData = append(validVinsMintingData, *validatedVinMintingData)
 	}
 
-	if len(validVins) != len(params.VinMintingData) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid minting data provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs", len(validVins))
 
 	statuses := make([]VinStatus, 0, len(params.VinMintingData))
@@ -785,50 +1200,26 @@ func (v *VehicleController) SubmitMintDataForVins(c *fiber.Ctx) error {
 		dbVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicles")
-			}
-
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
-		}
-
-		mintedVins, err := v.vs.GetVehiclesByVinsAndOnboardingStatus(
-			c.Context(),
-			validVins,
-			onboarding.OnboardingStatusMintSuccess,
-		)
-		if err != nil {
-			if !errors.Is(err, service.ErrVehicleNotFound) {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Failed to load vehicles from Database",
-				})
+				return NewAPIError(ErrCodeNotFound, "Could not find Vehicles")
 			}
-		}
 
-		vendorFailedMintedVins := make(dbmodels.VinSlice, 0, len(mintedVins))
-		for _, vin := range mintedVins {
-			if vin.ConnectionStatus.String == "failed" {
-				vendorFailedMintedVins = append(vendorFailedMintedVins, vin)
-			}
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
-		dbVins = append(dbVins, vendorFailedMintedVins...)
+		batch.known(validVins, dbVins)
 
 		indexedDbVins := make(map[string]*dbmodels.Vin)
-		for _, vin := range dbVins {
+		for _, vin := range batch.owned(c.UserContext(), walletAddress, dbVins) {
 			indexedDbVins[vin.Vin] = vin
 		}
 
 		for _, mint := range validVinsMintingData {
-			dbVin, ok := indexedDbVins[mint.Vin]
-			if !ok {
-				dbVin = &dbmodels.Vin{
-					Vin:              mint.Vin,
-					OnboardingStatus: onboarding.OnboardingStatusMintSubmitUnknown,
-				}
+			if batch.isDropped(mint.Vin) {
+				continue
 			}
 
+			dbVin := indexedDbVins[mint.Vin]
+
 			var sacd *onboarding.OnboardingSacd
 
 			if params.Sacd.Expiration != 0 && params.Sacd.Permissions != 0 {
@@ -842,7 +1233,7 @@ func (v *VehicleController) SubmitMintDataForVins(c *fiber.Ctx) error {
 
 			if v.canSubmitMintingJob(dbVin) {
 				localLog.Debug().Str(logfields.VIN, mint.Vin).Msg("Submitting minting job")
-				_, err = v.riverClient.Insert(c.Context(), onboarding.OnboardingArgs{
+				_, err = v.riverClient.Insert(c.UserContext(), onboarding.OnboardingArgs{
 					VIN:       mint.Vin,
 					TypedData: mint.TypedData,
 					Signature: mint.Signature,
@@ -856,6 +1247,7 @@ func (v *VehicleController) SubmitMintDataForVins(c *fiber.Ctx) error {
 						Vin:     mint.Vin,
 						Status:  "Failure",
 						Details: onboarding.GetDetailedStatus(onboarding.OnboardingStatusMintSubmitFailure),
+						Reason:  ErrCodeSubmitFailed,
 					})
 				} else {
 					v.logger.Debug().Str(logfields.VIN, mint.Vin).Msg("minting job submitted")
@@ -871,26 +1263,25 @@ func (v *VehicleController) SubmitMintDataForVins(c *fiber.Ctx) error {
 					Vin:     mint.Vin,
 					Status:  onboarding.GetVerificationStatus(dbVin.OnboardingStatus),
 					Details: onboarding.GetDetailedStatus(dbVin.OnboardingStatus),
+					Reason:  ErrCodeInvalidStatus,
 				})
 			}
 
 			err = v.vs.InsertOrUpdateVin(c.Context(), dbVin)
 
 			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": fmt.Sprintf("Failed to submit verification for mint: %v", mint),
-				})
+				return NewAPIError(ErrCodeInternal, fmt.Sprintf("Failed to submit verification for mint: %v", mint))
 			}
 			localLog.Debug().Str(logfields.VIN, mint.Vin).Msg("Submitted mint for VIN")
 		}
 	}
 
 	return c.JSON(StatusForVinsResponse{
-		Statuses: statuses,
+		Statuses: append(statuses, batch.rejected...),
 	})
 }
 
-func (v *VehicleController) getValidatedMintingData(data *VinTransactionData, _ common.Address) (*VinTransactionData, error) {
+func (v *VehicleController) getValidatedMintingData(ctx context.Context, data *VinTransactionData, _ common.Address) (*VinTransactionData, error) {
 	result := new(VinTransactionData)
 
 	// Validate VIN
@@ -921,7 +1312,7 @@ func (v *VehicleController) getValidatedMintingData(data *VinTransactionData, _
 
 	// Validate typed data with device definition (if applicable)
 	if data.TypedData != nil && data.TypedData.PrimaryType == "MintVehicleWithDeviceDefinitionSign" {
-		_, err := v.identity.GetDeviceDefinitionByID(data.TypedData.Message["deviceDefinitionId"].(string))
+		_, err := v.identity.GetDeviceDefinitionByID(ctx, data.TypedData.Message["deviceDefinitionId"].(string))
 		if err != nil {
 			return nil, err
 		}
@@ -949,38 +1340,43 @@ func (v *VehicleController) canSubmitMintingJob(record *dbmodels.Vin) bool {
 	return (minted && failedConnection) || (!minted || burned) && (failed || !pending)
 }
 
+// GetMintStatusForVins
+// @Summary Get minting status for each of the submitted VINs
+// @Description Invalid and repeated VINs are returned with status Rejected and a reason code
+// @Produce json
+// @Param vins query []string true "VINs"
+// @Success 200 {object} StatusForVinsResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/mint/status [get]
 func (v *VehicleController) GetMintStatusForVins(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
 	params := new(VinsGetParams)
 	if err := c.QueryParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse VINs",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse VINs")
+	}
+
+	if err := v.checkBatchSize(len(params.Vins)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Str(logfields.FunctionName, "GetMintStatusForVins").Interface("validVins", params.Vins).Logger()
 	localLog.Debug().Interface("vins", params.Vins).Msg("Checking Verification Status for Vins")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.Vins))
 	for _, vin := range params.Vins {
-		strippedVin := strings.TrimSpace(vin)
-		if v.isValidVin(strippedVin) {
+		if strippedVin, ok := batch.accept(vin); ok {
 			validVins = append(validVins, strippedVin)
 		}
 	}
 
-	if len(validVins) != len(params.Vins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid VINs provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs", len(validVins))
 
 	statuses := make([]VinStatus, 0, len(validVins))
@@ -989,12 +1385,14 @@ func (v *VehicleController) GetMintStatusForVins(c *fiber.Ctx) error {
 		dbVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
 			if errors.Is(err, service.ErrVehicleNotFound) {
-				return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicles")
+				return NewAPIError(ErrCodeNotFound, "Could not find Vehicles")
 			}
 
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
+		}
+
+		if err := v.checkVinsOwner(c.UserContext(), walletAddress, dbVins); err != nil {
+			return err
 		}
 
 		indexedVins := make(map[string]*dbmodels.Vin)
@@ -1015,93 +1413,76 @@ func (v *VehicleController) GetMintStatusForVins(c *fiber.Ctx) error {
 					Vin:     vin,
 					Status:  onboarding.GetMintStatus(dbVin.OnboardingStatus),
 					Details: onboarding.GetDetailedStatus(dbVin.OnboardingStatus),
+					Reason:  failureErrCode(dbVin.OnboardingStatus),
 				})
 			}
 		}
 	}
 
 	return c.JSON(StatusForVinsResponse{
-		Statuses: statuses,
+		Statuses: append(statuses, batch.rejected...),
 	})
 }
 
 // GetDisconnectDataForVins
-// @Summary Get verification status for each of the submitted VINs
+// @Summary Get the user operations to be signed for disconnecting each of the submitted VINs
+// @Description VINs which can't be disconnected are left out and returned as rejected, with a reason code
 // @Produce json
-// @Success 200
-// @Security     BearerAuth
+// @Param vins query []string true "VINs"
+// @Success 200 {object} DisconnectDataForVins
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
 // @Router /v1/vehicle/disconnect [get]
 func (v *VehicleController) GetDisconnectDataForVins(c *fiber.Ctx) error {
 	params := new(VinsGetParams)
 	if err := c.QueryParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse VINs",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse VINs")
 	}
 
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
+	if err := v.checkBatchSize(len(params.Vins)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Interface("vins", params.Vins).Str(logfields.FunctionName, "GetDisconnectDataForVins").Logger()
 	localLog.Debug().Msg("Getting disconnection data for Vins")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.Vins))
 	for _, vin := range params.Vins {
-		strippedVin := strings.TrimSpace(vin)
-		if v.isValidVin(strippedVin) {
+		if strippedVin, ok := batch.accept(vin); ok {
 			validVins = append(validVins, strippedVin)
 		}
 	}
 
-	if len(validVins) != len(params.Vins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid VINs provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs for disconnection", len(validVins))
 
 	disconnectionData := make([]VinUserOperationData, 0, len(validVins))
 
 	if len(validVins) > 0 {
-		dbVins, err := v.vs.GetVehiclesByVinsAndOnboardingStatusRange(c.Context(), validVins, onboarding.OnboardingStatusMintSuccess, onboarding.OnboardingStatusBurnSDFailure, nil)
+		knownVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
-			if errors.Is(err, service.ErrVehicleNotFound) {
-				return fiber.NewError(fiber.StatusBadRequest, "Could not find Vehicles")
-			}
-
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
-		if len(dbVins) != len(validVins) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Some of the VINs are not fully onboarded",
-			})
-		}
+		batch.known(validVins, knownVins)
+		ownedVins := vinsOf(batch.owned(c.UserContext(), walletAddress, knownVins))
 
-		indexedVins := make(map[string]*dbmodels.Vin)
-		for _, vin := range dbVins {
-			indexedVins[vin.Vin] = vin
+		dbVins, err := v.vs.GetVehiclesByVinsAndOnboardingStatusRange(c.Context(), ownedVins, onboarding.OnboardingStatusMintSuccess, onboarding.OnboardingStatusBurnSDFailure, nil)
+		if err != nil && !errors.Is(err, service.ErrVehicleNotFound) {
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
-		identityVehicles, err := v.identity.FetchVehiclesByWalletAddress(walletAddress.String())
+		batch.eligible(ownedVins, dbVins, "VIN is not fully onboarded")
+
+		identityVehicles, err := v.identity.FetchVehiclesByWalletAddress(c.UserContext(), walletAddress.String())
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to fetch identity vehicles",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to fetch identity vehicles")
 		}
 
 		indexedIdentityVehicles := make(map[int64]models.Vehicle)
@@ -1112,32 +1493,29 @@ func (v *VehicleController) GetDisconnectDataForVins(c *fiber.Ctx) error {
 		for _, dbVin := range dbVins {
 			identityVehicle, ok := indexedIdentityVehicles[dbVin.VehicleTokenID.Int64]
 			if !ok {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "VIN not owned",
-				})
+				batch.drop(dbVin.Vin, ErrCodeNotOwned, "VIN not owned")
+				continue
 			}
 
 			fullyConnected := !dbVin.VehicleTokenID.IsZero() && !dbVin.SyntheticTokenID.IsZero()
 
 			if !fullyConnected {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "VIN not minted",
-				})
+				batch.drop(dbVin.Vin, ErrCodeInvalidStatus, "VIN not minted")
+				continue
 			}
 
 			fullyConnectedIdentity := identityVehicle.TokenID == dbVin.VehicleTokenID.Int64 && identityVehicle.SyntheticDevice.TokenID == dbVin.SyntheticTokenID.Int64
 
 			if !fullyConnectedIdentity {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "TokenIDs mismatch",
-				})
+				batch.drop(dbVin.Vin, ErrCodeTokenMismatch, "TokenIDs mismatch")
+				continue
 			}
 
 			op, hash, err := v.tr.GetBurnSDByOwnerUserOperationAndHash(walletAddress, big.NewInt(dbVin.SyntheticTokenID.Int64))
 			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Failed to get Burn SD operation data",
-				})
+				localLog.Error().Err(err).Str(logfields.VIN, dbVin.Vin).Msg("Failed to get Burn SD operation data")
+				batch.drop(dbVin.Vin, ErrCodeInternal, "Failed to get Burn SD operation data")
+				continue
 			}
 
 			vinMintingData := VinUserOperationData{
@@ -1152,54 +1530,58 @@ func (v *VehicleController) GetDisconnectDataForVins(c *fiber.Ctx) error {
 
 	return c.JSON(DisconnectDataForVins{
 		VinDisconnectData: disconnectionData,
+		Rejected:          batch.rejected,
 	})
 }
 
+// SubmitDisconnectDataForVins
+// @Summary Submits the signed disconnect data
+// @Description Starts disconnecting the VINs, VINs which can't be disconnected are returned with status Rejected and a reason code
+// @Accept json
+// @Produce json
+// @Param params body DisconnectDataForVins true "signed user operations of the VINs"
+// @Success 200 {object} StatusForVinsResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/disconnect [post]
 func (v *VehicleController) SubmitDisconnectDataForVins(c *fiber.Ctx) error {
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
 	}
 
 	params := new(DisconnectDataForVins)
 	if err := c.BodyParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse disconnection data",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse disconnection data")
+	}
+
+	if err := v.checkBatchSize(len(params.VinDisconnectData)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Str(logfields.FunctionName, "SubmitDisconnectDataForVins").Logger()
 	localLog.Debug().Msg("Submitting VINs to disconnect")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.VinDisconnectData))
 	validVinsDisconnectData := make([]VinUserOperationData, 0, len(params.VinDisconnectData))
 	for _, paramVin := range params.VinDisconnectData {
+		strippedVin, ok := batch.accept(paramVin.Vin)
+		if !ok {
+			continue
+		}
+
 		validatedVinMintingData, err := v.getValidatedUserOperationData(&paramVin, walletAddress)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid disconnect data",
-			})
+			batch.drop(strippedVin, ErrCodeInvalidData, "Invalid disconnect data")
+			continue
 		}
 
 		validVins = append(validVins, validatedVinMintingData.Vin)
 		validVinsDisconnectData = append(validVinsDisconnectData, *validatedVinMintingData)
 	}
 
-	if len(validVins) != len(params.VinDisconnectData) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid disconnect data provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs submitted to disconnect", len(validVins))
 
 	statuses := make([]VinStatus, 0, len(params.VinDisconnectData))
@@ -1208,35 +1590,33 @@ func (v *VehicleController) SubmitDisconnectDataForVins(c *fiber.Ctx) error {
 		dbVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicles")
+				return NewAPIError(ErrCodeNotFound, "Could not find Vehicles")
 			}
 
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
+		batch.known(validVins, dbVins)
+
 		indexedDbVins := make(map[string]*dbmodels.Vin)
-		for _, vin := range dbVins {
+		for _, vin := range batch.owned(c.UserContext(), walletAddress, dbVins) {
 			indexedDbVins[vin.Vin] = vin
 		}
 
 		for _, disconnect := range validVinsDisconnectData {
-			dbVin, ok := indexedDbVins[disconnect.Vin]
-			if !ok {
-				dbVin = &dbmodels.Vin{
-					Vin:              disconnect.Vin,
-					OnboardingStatus: onboarding.OnboardingStatusDisconnectSubmitUnknown,
-				}
+			if batch.isDropped(disconnect.Vin) {
+				continue
 			}
 
+			dbVin := indexedDbVins[disconnect.Vin]
+
 			if v.canSubmitDisconnectJob(dbVin) {
 				localLog.Debug().Str(logfields.VIN, disconnect.Vin).Msg("Submitting disconnect job")
 
 				op := disconnect.UserOperation
 				op.Signature = disconnect.Signature
 
-				_, err = v.riverClient.Insert(c.Context(), onboarding.DisconnectArgs{
+				_, err = v.riverClient.Insert(c.UserContext(), onboarding.DisconnectArgs{
 					VIN:           disconnect.Vin,
 					UserOperation: disconnect.UserOperation,
 				}, nil)
@@ -1247,6 +1627,7 @@ func (v *VehicleController) SubmitDisconnectDataForVins(c *fiber.Ctx) error {
 						Vin:     disconnect.Vin,
 						Status:  "Failure",
 						Details: onboarding.GetDetailedStatus(onboarding.OnboardingStatusDisconnectSubmitFailure),
+						Reason:  ErrCodeSubmitFailed,
 					})
 				} else {
 					v.logger.Debug().Str(logfields.VIN, disconnect.Vin).Msg("disconnect job submitted")
@@ -1262,22 +1643,21 @@ func (v *VehicleController) SubmitDisconnectDataForVins(c *fiber.Ctx) error {
 					Vin:     disconnect.Vin,
 					Status:  onboarding.GetVerificationStatus(dbVin.OnboardingStatus),
 					Details: onboarding.GetDetailedStatus(dbVin.OnboardingStatus),
+					Reason:  ErrCodeInvalidStatus,
 				})
 			}
 
 			err = v.vs.InsertOrUpdateVin(c.Context(), dbVin)
 
 			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": fmt.Sprintf("Failed to submit disconnect: %v", disconnect),
-				})
+				return NewAPIError(ErrCodeInternal, fmt.Sprintf("Failed to submit disconnect: %v", disconnect))
 			}
 			localLog.Debug().Str(logfields.VIN, disconnect.Vin).Msg("Submitted disconnect for VIN")
 		}
 	}
 
 	return c.JSON(StatusForVinsResponse{
-		Statuses: statuses,
+		Statuses: append(statuses, batch.rejected...),
 	})
 }
 
@@ -1309,38 +1689,43 @@ func (v *VehicleController) canSubmitDisconnectJob(record *dbmodels.Vin) bool {
 	return (minted || failed) && !pending
 }
 
+// GetDisconnectStatusForVins
+// @Summary Get disconnect status for each of the submitted VINs
+// @Description Invalid and repeated VINs are returned with status Rejected and a reason code
+// @Produce json
+// @Param vins query []string true "VINs"
+// @Success 200 {object} StatusForVinsResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/disconnect/status [get]
 func (v *VehicleController) GetDisconnectStatusForVins(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
 	params := new(VinsGetParams)
 	if err := c.QueryParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse VINs",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse VINs")
+	}
+
+	if err := v.checkBatchSize(len(params.Vins)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Str(logfields.FunctionName, "GetDisconnectStatusForVins").Interface("validVins", params.Vins).Logger()
 	localLog.Debug().Interface("vins", params.Vins).Msg("Checking Disconnect Status for Vins")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.Vins))
 	for _, vin := range params.Vins {
-		strippedVin := strings.TrimSpace(vin)
-		if v.isValidVin(strippedVin) {
+		if strippedVin, ok := batch.accept(vin); ok {
 			validVins = append(validVins, strippedVin)
 		}
 	}
 
-	if len(validVins) != len(params.Vins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid VINs provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs", len(validVins))
 
 	statuses := make([]VinStatus, 0, len(validVins))
@@ -1349,12 +1734,14 @@ func (v *VehicleController) GetDisconnectStatusForVins(c *fiber.Ctx) error {
 		dbVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
 			if errors.Is(err, service.ErrVehicleNotFound) {
-				return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicles")
+				return NewAPIError(ErrCodeNotFound, "Could not find Vehicles")
 			}
 
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
+		}
+
+		if err := v.checkVinsOwner(c.UserContext(), walletAddress, dbVins); err != nil {
+			return err
 		}
 
 		indexedVins := make(map[string]*dbmodels.Vin)
@@ -1375,12 +1762,13 @@ func (v *VehicleController) GetDisconnectStatusForVins(c *fiber.Ctx) error {
 					Vin:     vin,
 					Status:  onboarding.GetDisconnectStatus(dbVin.OnboardingStatus),
 					Details: onboarding.GetDetailedStatus(dbVin.OnboardingStatus),
+					Reason:  failureErrCode(dbVin.OnboardingStatus),
 				})
 			}
 		}
 	}
 
 	return c.JSON(StatusForVinsResponse{
-		Statuses: statuses,
+		Statuses: append(statuses, batch.rejected...),
 	})
 }
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/volatiletech/null/v8"
+	"strings"
+)
+
+const vinStatusRejected = "Rejected"
+
+// checkBatchSize fails the whole request when more VINs are submitted than allowed
+func (v *VehicleController) checkBatchSize(size int) error {
+	if v.settings.MaxBatchSize > 0 && size > v.settings.MaxBatchSize {
+		return NewAPIError(ErrCodeBatchTooLarge, fmt.Sprintf("Too many VINs, at most %d can be submitted at once", v.settings.MaxBatchSize))
+	}
+
+	return nil
+}
+
+// vinBatch collects the VINs of a batch request which can't be processed, so every other VIN
+// still is, instead of failing the whole request
+type vinBatch struct {
+	v        *VehicleController
+	seen     map[string]struct{}
+	dropped  map[string]struct{}
+	rejected []VinStatus
+}
+
+func (v *VehicleController) newVinBatch() *vinBatch {
+	return &vinBatch{
+		v:        v,
+		seen:     make(map[string]struct{}),
+		dropped:  make(map[string]struct{}),
+		rejected: make([]VinStatus, 0),
+	}
+}
+
+// accept strips the submitted VIN, invalid and repeated VINs are rejected
+func (b *vinBatch) accept(vin string) (string, bool) {
+	strippedVin := strings.TrimSpace(vin)
+	if !b.v.isValidVin(strippedVin) {
+		b.reject(strippedVin, ErrCodeInvalidVin, "Invalid VIN")
+		return "", false
+	}
+
+	if _, ok := b.seen[strippedVin]; ok {
+		b.reject(strippedVin, ErrCodeDuplicateVin, "Duplicated VIN")
+		return "", false
+	}
+	b.seen[strippedVin] = struct{}{}
+
+	return strippedVin, true
+}
+
+func (b *vinBatch) reject(vin, reason, details string) {
+	b.rejected = append(b.rejected, VinStatus{
+		Vin:     vin,
+		Status:  vinStatusRejected,
+		Details: details,
+		Reason:  reason,
+	})
+}
+
+// drop rejects a VIN which was already accepted
+func (b *vinBatch) drop(vin, reason, details string) {
+	b.dropped[vin] = struct{}{}
+	b.reject(vin, reason, details)
+}
+
+// isDropped checks if an accepted VIN was rejected later on
+func (b *vinBatch) isDropped(vin string) bool {
+	_, ok := b.dropped[vin]
+	return ok
+}
+
+// known rejects the VINs without a record and returns the VINs which have one
+func (b *vinBatch) known(vins []string, dbVins dbmodels.VinSlice) []string {
+	indexedVins := make(map[string]struct{}, len(dbVins))
+	for _, dbVin := range dbVins {
+		indexedVins[dbVin.Vin] = struct{}{}
+	}
+
+	knownVins := make([]string, 0, len(vins))
+	for _, vin := range vins {
+		if _, ok := indexedVins[vin]; !ok {
+			b.drop(vin, ErrCodeNotFound, "Could not find Vehicle")
+			continue
+		}
+		knownVins = append(knownVins, vin)
+	}
+
+	return knownVins
+}
+
+// eligible rejects the VINs whose record was not returned for the required onboarding status, unless already rejected
+func (b *vinBatch) eligible(vins []string, dbVins dbmodels.VinSlice, details string) {
+	indexedVins := make(map[string]struct{}, len(dbVins))
+	for _, dbVin := range dbVins {
+		indexedVins[dbVin.Vin] = struct{}{}
+	}
+
+	for _, vin := range vins {
+		if _, ok := indexedVins[vin]; !ok && !b.isDropped(vin) {
+			b.drop(vin, ErrCodeInvalidStatus, details)
+		}
+	}
+}
+
+// covered rejects the VINs a fleet operator's SACD doesn't cover and returns the covered records
+func (b *vinBatch) covered(ctx context.Context, dbVins dbmodels.VinSlice) dbmodels.VinSlice {
+	coveredVins := make(dbmodels.VinSlice, 0, len(dbVins))
+	for _, dbVin := range dbVins {
+		if !sacdCoversVin(ctx, dbVin) {
+			b.drop(dbVin.Vin, ErrCodeSacdNotAllowed, "Vehicle not covered by the SACD")
+			continue
+		}
+		coveredVins = append(coveredVins, dbVin)
+	}
+
+	return coveredVins
+}
+
+// owned rejects the VINs not owned by the wallet or not covered by the SACD and returns the owned records
+func (b *vinBatch) owned(ctx context.Context, walletAddress common.Address, dbVins dbmodels.VinSlice) dbmodels.VinSlice {
+	dbVins = b.covered(ctx, dbVins)
+	ownedVins, err := b.v.ownedVins(ctx, walletAddress, dbVins)
+	if err != nil {
+		for _, dbVin := range dbVins {
+			b.drop(dbVin.Vin, ErrCodeInternal, "Failed to load vehicle owner from Identity API")
+		}
+		return dbmodels.VinSlice{}
+	}
+
+	owned := make(map[string]struct{}, len(ownedVins))
+	for _, dbVin := range ownedVins {
+		owned[dbVin.Vin] = struct{}{}
+	}
+	for _, dbVin := range dbVins {
+		if _, ok := owned[dbVin.Vin]; !ok {
+			b.drop(dbVin.Vin, ErrCodeNotOwned, "Vehicle not owned by wallet")
+		}
+	}
+
+	return ownedVins
+}
+
+// claim stores the wallet as owner of VINs which don't have one yet, VINs owned by another wallet or not covered by
+// the SACD are rejected
+func (b *vinBatch) claim(ctx context.Context, walletAddress common.Address, dbVins dbmodels.VinSlice) dbmodels.VinSlice {
+	dbVins = b.covered(ctx, dbVins)
+	claimed := make(map[string]struct{}, len(dbVins))
+	toCheck := make(dbmodels.VinSlice, 0, len(dbVins))
+	for _, dbVin := range dbVins {
+		// VINs submitted before owners were recorded, and not minted yet
+		if dbVin.OwnerAddress.IsZero() && dbVin.VehicleTokenID.IsZero() {
+			dbVin.OwnerAddress = null.StringFrom(walletAddress.Hex())
+			claimed[dbVin.Vin] = struct{}{}
+			continue
+		}
+		toCheck = append(toCheck, dbVin)
+	}
+
+	for _, dbVin := range b.owned(ctx, walletAddress, toCheck) {
+		claimed[dbVin.Vin] = struct{}{}
+	}
+
+	claimedVins := make(dbmodels.VinSlice, 0, len(claimed))
+	for _, dbVin := range dbVins {
+		if _, ok := claimed[dbVin.Vin]; ok {
+			claimedVins = append(claimedVins, dbVin)
+		}
+	}
+
+	return claimedVins
+}
+
+func vinsOf(dbVins dbmodels.VinSlice) []string {
+	vins := make([]string, 0, len(dbVins))
+	for _, dbVin := range dbVins {
+		vins = append(vins, dbVin.Vin)
+	}
+
+	return vins
+}
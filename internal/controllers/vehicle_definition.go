@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/DIMO-Network/shared/pkg/logfields"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"github.com/riverqueue/river"
+	"github.com/rs/zerolog"
+	"strings"
+)
+
+// VehicleDefinitionParams device definition picked for the VIN, by its ID or by make, model and year
+type VehicleDefinitionParams struct {
+	Vin          string `json:"vin"`
+	DefinitionID string `json:"definitionId,omitempty"`
+	Make         string `json:"make,omitempty"`
+	Model        string `json:"model,omitempty"`
+	Year         int    `json:"year,omitempty"`
+}
+
+// deviceDefinitionID ID of the device definition of the make, model and year, eg. ford_f-150_2022
+func deviceDefinitionID(manufacturer, model string, year int) string {
+	slug := func(value string) string {
+		return strings.Join(strings.Fields(strings.ToLower(value)), "-")
+	}
+
+	return fmt.Sprintf("%s_%s_%d", slug(manufacturer), slug(model), year)
+}
+
+// definitionIDOf the definition ID of the params, built from make, model and year when it isn't set
+func (p *VehicleDefinitionParams) definitionIDOf() (string, bool) {
+	if id := strings.TrimSpace(p.DefinitionID); id != "" {
+		return id, true
+	}
+
+	if strings.TrimSpace(p.Make) == "" || strings.TrimSpace(p.Model) == "" || p.Year <= 0 {
+		return "", false
+	}
+
+	return deviceDefinitionID(p.Make, p.Model, p.Year), true
+}
+
+// lookupDefinition loads the device definition from identity-api, unknown definitions are rejected and failures to
+// reach identity-api are internal errors
+func lookupDefinition(ctx context.Context, logger *zerolog.Logger, identity service.IdentityAPI, definitionID string) (*models.DeviceDefinition, error) {
+	dd, err := identity.GetDeviceDefinitionByID(ctx, definitionID)
+	if err != nil && !errors.Is(err, service.ErrBadRequest) {
+		logger.Error().Err(err).Str("definitionId", definitionID).Msg("Failed to load device definition")
+		return nil, NewAPIError(ErrCodeInternal, "Failed to load device definition from Identity API")
+	}
+
+	if err != nil || dd == nil || dd.DeviceDefinitionID == "" {
+		return nil, NewAPIError(ErrCodeInvalidData, "Unknown device definition "+definitionID)
+	}
+
+	return dd, nil
+}
+
+// overrideDefinition stores the manually picked device definition, VINs which failed verification get a new verify
+// job which resumes at vendor validation. Definitions of VINs past minting are rejected. Admin overrides are stored
+// together with their audit log entry.
+func overrideDefinition(ctx context.Context, logger *zerolog.Logger, vs *service.Vehicle, riverClient *river.Client[pgx.Tx], record *dbmodels.Vin, dd *models.DeviceDefinition, by string, audit *service.AuditEntry) error {
+	previous := record.OnboardingStatus
+	resume, err := onboarding.OverrideDefinition(record, dd, by)
+	if err != nil {
+		return NewAPIError(ErrCodeInvalidStatus, "Device definition can't change once minting started")
+	}
+
+	columns := []string{dbmodels.VinColumns.DeviceDefinitionID, dbmodels.VinColumns.DefinitionMake,
+		dbmodels.VinColumns.DefinitionModel, dbmodels.VinColumns.DefinitionYear, dbmodels.VinColumns.DefinitionOverriddenBy,
+		dbmodels.VinColumns.DefinitionOverriddenAt, dbmodels.VinColumns.OnboardingStatus}
+	if audit != nil {
+		err = vs.UpdateVinAudited(ctx, record, *audit, columns...)
+	} else {
+		err = vs.UpdateVin(ctx, record, columns...)
+	}
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to update vehicle")
+	}
+
+	if !resume {
+		return nil
+	}
+
+	if _, err := riverClient.Insert(ctx, onboarding.VerifyArgs{VIN: record.Vin}, nil); err != nil {
+		logger.Error().Err(err).Str(logfields.VIN, record.Vin).Msg("Failed to submit VIN verification job")
+
+		// back to the failure, so the verification can be submitted again
+		record.OnboardingStatus = previous
+		if err := vs.UpdateVin(ctx, record, dbmodels.VinColumns.OnboardingStatus); err != nil {
+			logger.Error().Err(err).Str(logfields.VIN, record.Vin).Msg("Failed to restore onboarding status")
+		}
+		return NewAPIError(ErrCodeSubmitFailed, "Failed to submit verification")
+	}
+
+	return nil
+}
+
+// SetVehicleDefinition
+// @Summary Pick the device definition of user's VIN
+// @Description Sets the device definition of a VIN which failed verification, by its ID or by make, model and year,
+// @Description and resumes the verification at vendor validation. Decoding the VIN later doesn't replace it.
+// @Accept json
+// @Produce json
+// @Param params body VehicleDefinitionParams true "VIN and device definition ID, or make, model and year"
+// @Success 200 {object} VinStatus
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/definition [put]
+func (v *VehicleController) SetVehicleDefinition(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
+	params := new(VehicleDefinitionParams)
+	if err := c.BodyParser(params); err != nil {
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse request")
+	}
+
+	vin := strings.ToUpper(strings.TrimSpace(params.Vin))
+	if !v.isValidVin(vin) {
+		return NewAPIError(ErrCodeInvalidVin, "Invalid VIN")
+	}
+
+	definitionID, ok := params.definitionIDOf()
+	if !ok {
+		return NewAPIError(ErrCodeInvalidRequest, "Missing definition ID, or make, model and year")
+	}
+
+	record, err := v.vs.GetVehicleByVin(c.Context(), vin)
+	if err != nil {
+		if errors.Is(err, service.ErrVehicleNotFound) {
+			return NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
+		}
+		return NewAPIError(ErrCodeInternal, "Failed to load vehicle from Database")
+	}
+
+	if !v.isVinOwner(c.UserContext(), walletAddress, record) {
+		return NewAPIError(ErrCodeNotOwned, "Vehicle not owned by wallet")
+	}
+
+	if !onboarding.IsVerificationFailure(record.OnboardingStatus) {
+		return NewAPIError(ErrCodeInvalidStatus, "Only VINs which failed verification can get a device definition")
+	}
+
+	dd, err := lookupDefinition(c.UserContext(), v.logger, v.identity, definitionID)
+	if err != nil {
+		return err
+	}
+
+	if err := overrideDefinition(c.Context(), v.logger, v.vs, v.riverClient, record, dd, walletAddress.Hex(), nil); err != nil {
+		return err
+	}
+
+	return c.JSON(VinStatus{
+		Vin:     record.Vin,
+		Status:  onboarding.GetVerificationStatus(record.OnboardingStatus),
+		Details: onboarding.GetDetailedStatus(record.OnboardingStatus),
+	})
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/mocks"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/test"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"gotest.tools/v3/assert"
+	"io"
+	"testing"
+)
+
+type VehicleDefinitionTestSuite struct {
+	suite.Suite
+}
+
+func TestVehicleDefinitionTestSuite(t *testing.T) {
+	suite.Run(t, new(VehicleDefinitionTestSuite))
+}
+
+func (s *VehicleDefinitionTestSuite) TestDefinitionIDOf() {
+	id, ok := (&VehicleDefinitionParams{Make: " Land Rover", Model: "Range  Rover ", Year: 2021}).definitionIDOf()
+	s.True(ok)
+	s.Equal("land-rover_range-rover_2021", id)
+
+	id, ok = (&VehicleDefinitionParams{DefinitionID: " ford_f-150_2022 ", Make: "Audi"}).definitionIDOf()
+	s.True(ok)
+	s.Equal("ford_f-150_2022", id)
+
+	_, ok = (&VehicleDefinitionParams{Make: "Ford", Model: "F-150"}).definitionIDOf()
+	s.False(ok)
+}
+
+func (s *VehicleControllerTestSuite) TestSetVehicleDefinition() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	identity := mocks.NewIdentityAPIMock([]models.Vehicle{
+		{TokenID: 44, Owner: testWalletAddress.Hex()},
+	}, []models.DeviceDefinition{
+		{DeviceDefinitionID: "ford_f-150_2022", Manufacturer: models.Manufacturer{Name: "Ford"}, Model: "F-150", Year: 2022},
+	})
+
+	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, identity, s.vs, s.river, nil, nil, nil)
+	app := fiber.New()
+	app.Put("/vehicle/definition", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.SetVehicleDefinition)
+
+	for _, dbVin := range []dbmodels.Vin{
+		{Vin: "ABCDEFG1234567841", OnboardingStatus: onboarding.OnboardingStatusDecodingFailure, OwnerAddress: null.StringFrom(testWalletAddress.Hex())},
+		{Vin: "ABCDEFG1234567842", OnboardingStatus: onboarding.OnboardingStatusMintSuccess, OwnerAddress: null.StringFrom(testWalletAddress.Hex())},
+		{Vin: "ABCDEFG1234567843", OnboardingStatus: onboarding.OnboardingStatusDecodingFailure, OwnerAddress: null.StringFrom(common.HexToAddress("0x2").Hex())},
+		{Vin: "ABCDEFG1234567844", OnboardingStatus: onboarding.OnboardingStatusDecodingFailure, OwnerAddress: null.StringFrom(testWalletAddress.Hex()), VehicleTokenID: null.Int64From(44)},
+	} {
+		require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+	}
+
+	setDefinition := func(params string) int {
+		response, _ := app.Test(test.BuildRequest("PUT", "/vehicle/definition", params))
+		return response.StatusCode
+	}
+
+	s.Run("Pick definition by make, model and year", func() {
+		response, _ := app.Test(test.BuildRequest("PUT", "/vehicle/definition", `{"vin": "ABCDEFG1234567841", "make": "Ford", "model": "F-150", "year": 2022}`))
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		body, _ := io.ReadAll(response.Body)
+		var status VinStatus
+		assert.NilError(t, json.Unmarshal(body, &status))
+		assert.Equal(t, onboarding.GetDetailedStatus(onboarding.OnboardingStatusDecodingSuccess), status.Details)
+
+		record, err := s.vs.GetVehicleByVin(s.ctx, "ABCDEFG1234567841")
+		assert.NilError(t, err)
+		assert.Equal(t, "ford_f-150_2022", record.DeviceDefinitionID.String)
+		assert.Equal(t, "Ford", record.DefinitionMake.String)
+		assert.Equal(t, testWalletAddress.Hex(), record.DefinitionOverriddenBy.String)
+		assert.Assert(t, record.DefinitionOverriddenAt.Valid)
+
+		// verification resumes at vendor validation
+		jobs, err := s.vs.GetJobsByVin(s.ctx, "ABCDEFG1234567841", []string{onboarding.VerifyArgs{}.Kind()}, 1)
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(jobs))
+	})
+
+	s.Run("Unknown definition", func() {
+		assert.Equal(t, fiber.StatusBadRequest, setDefinition(`{"vin": "ABCDEFG1234567841", "definitionId": "ford_f-150_1900"}`))
+	})
+
+	s.Run("Identity API unavailable", func() {
+		identity.DefinitionErr = errors.New("connection refused")
+		defer func() { identity.DefinitionErr = nil }()
+
+		assert.Equal(t, fiber.StatusInternalServerError, setDefinition(`{"vin": "ABCDEFG1234567841", "definitionId": "ford_f-150_2022"}`))
+	})
+
+	s.Run("Missing definition", func() {
+		assert.Equal(t, fiber.StatusBadRequest, setDefinition(`{"vin": "ABCDEFG1234567841", "make": "Ford"}`))
+	})
+
+	s.Run("VIN not failed in verification", func() {
+		assert.Equal(t, fiber.StatusConflict, setDefinition(`{"vin": "ABCDEFG1234567842", "definitionId": "ford_f-150_2022"}`))
+	})
+
+	s.Run("VIN with a vehicle NFT", func() {
+		assert.Equal(t, fiber.StatusConflict, setDefinition(`{"vin": "ABCDEFG1234567844", "definitionId": "ford_f-150_2022"}`))
+
+		record, err := s.vs.GetVehicleByVin(s.ctx, "ABCDEFG1234567844")
+		assert.NilError(t, err)
+		assert.Assert(t, !record.DeviceDefinitionID.Valid)
+	})
+
+	s.Run("VIN of another wallet", func() {
+		assert.Equal(t, fiber.StatusForbidden, setDefinition(`{"vin": "ABCDEFG1234567843", "definitionId": "ford_f-150_2022"}`))
+	})
+
+	s.Run("Unknown VIN", func() {
+		assert.Equal(t, fiber.StatusNotFound, setDefinition(`{"vin": "ABCDEFG1234567849", "definitionId": "ford_f-150_2022"}`))
+	})
+}
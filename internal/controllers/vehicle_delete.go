@@ -11,85 +11,73 @@ import (
 	"github.com/friendsofgo/errors"
 	"github.com/gofiber/fiber/v2"
 	"math/big"
-	"slices"
-	"strings"
 )
 
+// DeleteDataForVins user operations burning the vehicles of the VINs, to be signed
 type DeleteDataForVins struct {
 	VinDeleteData []VinUserOperationData `json:"vinDeleteData"`
+	Rejected      []VinStatus            `json:"rejected,omitempty"`
 }
 
+// GetDeleteDataForVins
+// @Summary Get the user operations to be signed for deleting each of the submitted VINs
+// @Description VINs which can't be deleted are left out and returned as rejected, with a reason code
+// @Produce json
+// @Param vins query []string true "VINs"
+// @Success 200 {object} DeleteDataForVins
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/delete [get]
 func (v *VehicleController) GetDeleteDataForVins(c *fiber.Ctx) error {
 	params := new(VinsGetParams)
 	if err := c.QueryParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse VINs",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse VINs")
 	}
 
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
+	if err := v.checkBatchSize(len(params.Vins)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Interface("vins", params.Vins).Str(logfields.FunctionName, "GetDeleteDataForVins").Logger()
 	localLog.Debug().Msg("Getting deletion data for Vins")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.Vins))
 	for _, vin := range params.Vins {
-		strippedVin := strings.TrimSpace(vin)
-		if v.isValidVin(strippedVin) {
+		if strippedVin, ok := batch.accept(vin); ok {
 			validVins = append(validVins, strippedVin)
 		}
 	}
 
-	if len(validVins) != len(params.Vins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid VINs provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs for deletion", len(validVins))
 
 	deletionData := make([]VinUserOperationData, 0, len(validVins))
 
 	if len(validVins) > 0 {
-		dbVins, err := v.vs.GetVehiclesByVinsAndOnboardingStatusRange(c.Context(), validVins, onboarding.OnboardingStatusBurnSDSuccess, onboarding.OnboardingStatusBurnVehicleFailure, nil)
+		knownVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
-			if errors.Is(err, service.ErrVehicleNotFound) {
-				return fiber.NewError(fiber.StatusBadRequest, "Could not find Vehicles")
-			}
-
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
-		if len(dbVins) != len(validVins) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Some of the VINs are not disconnected",
-			})
-		}
+		batch.known(validVins, knownVins)
+		ownedVins := vinsOf(batch.owned(c.UserContext(), walletAddress, knownVins))
 
-		indexedVins := make(map[string]*dbmodels.Vin)
-		for _, vin := range dbVins {
-			indexedVins[vin.Vin] = vin
+		dbVins, err := v.vs.GetVehiclesByVinsAndOnboardingStatusRange(c.Context(), ownedVins, onboarding.OnboardingStatusBurnSDSuccess, onboarding.OnboardingStatusBurnVehicleFailure, nil)
+		if err != nil && !errors.Is(err, service.ErrVehicleNotFound) {
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
-		identityVehicles, err := v.identity.FetchVehiclesByWalletAddress(walletAddress.String())
+		batch.eligible(ownedVins, dbVins, "VIN is not disconnected")
+
+		identityVehicles, err := v.identity.FetchVehiclesByWalletAddress(c.UserContext(), walletAddress.String())
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to fetch identity vehicles",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to fetch identity vehicles")
 		}
 
 		indexedIdentityVehicles := make(map[int64]models.Vehicle)
@@ -100,32 +88,29 @@ func (v *VehicleController) GetDeleteDataForVins(c *fiber.Ctx) error {
 		for _, dbVin := range dbVins {
 			identityVehicle, ok := indexedIdentityVehicles[dbVin.VehicleTokenID.Int64]
 			if !ok {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "VIN not owned",
-				})
+				batch.drop(dbVin.Vin, ErrCodeNotOwned, "VIN not owned")
+				continue
 			}
 
 			burnable := !dbVin.VehicleTokenID.IsZero() && dbVin.SyntheticTokenID.IsZero()
 
 			if !burnable {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "VIN cannot be burned",
-				})
+				batch.drop(dbVin.Vin, ErrCodeInvalidStatus, "VIN cannot be burned")
+				continue
 			}
 
 			burnableIdentity := identityVehicle.TokenID == dbVin.VehicleTokenID.Int64 && identityVehicle.SyntheticDevice.TokenID == 0
 
 			if !burnableIdentity {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-					"error": "TokenIDs mismatch",
-				})
+				batch.drop(dbVin.Vin, ErrCodeTokenMismatch, "TokenIDs mismatch")
+				continue
 			}
 
 			op, hash, err := v.tr.GetBurnVehicleByOwnerUserOperationAndHash(walletAddress, big.NewInt(dbVin.VehicleTokenID.Int64))
 			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Failed to get Burn Vehicle operation data",
-				})
+				localLog.Error().Err(err).Str(logfields.VIN, dbVin.Vin).Msg("Failed to get Burn Vehicle operation data")
+				batch.drop(dbVin.Vin, ErrCodeInternal, "Failed to get Burn Vehicle operation data")
+				continue
 			}
 
 			vinMintingData := VinUserOperationData{
@@ -140,54 +125,58 @@ func (v *VehicleController) GetDeleteDataForVins(c *fiber.Ctx) error {
 
 	return c.JSON(DeleteDataForVins{
 		VinDeleteData: deletionData,
+		Rejected:      batch.rejected,
 	})
 }
 
+// SubmitDeleteDataForVins
+// @Summary Submits the signed delete data
+// @Description Starts burning the vehicles of the VINs, VINs which can't be deleted are returned with status Rejected and a reason code
+// @Accept json
+// @Produce json
+// @Param params body DeleteDataForVins true "signed user operations of the VINs"
+// @Success 200 {object} StatusForVinsResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/delete [post]
 func (v *VehicleController) SubmitDeleteDataForVins(c *fiber.Ctx) error {
 	walletAddress, err := getWalletAddress(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get wallet address",
-		})
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
 	}
 
 	params := new(DeleteDataForVins)
 	if err := c.BodyParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse delete data",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse delete data")
+	}
+
+	if err := v.checkBatchSize(len(params.VinDeleteData)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Str(logfields.FunctionName, "SubmitDeleteDataForVins").Logger()
 	localLog.Debug().Msg("Submitting VINs to delete")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.VinDeleteData))
 	validVinsDeleteData := make([]VinUserOperationData, 0, len(params.VinDeleteData))
 	for _, paramVin := range params.VinDeleteData {
+		strippedVin, ok := batch.accept(paramVin.Vin)
+		if !ok {
+			continue
+		}
+
 		validatedVinDeleteData, err := v.getValidatedUserOperationData(&paramVin, walletAddress)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid delete data",
-			})
+			batch.drop(strippedVin, ErrCodeInvalidData, "Invalid delete data")
+			continue
 		}
 
 		validVins = append(validVins, validatedVinDeleteData.Vin)
 		validVinsDeleteData = append(validVinsDeleteData, *validatedVinDeleteData)
 	}
 
-	if len(validVins) != len(params.VinDeleteData) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid delete data provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs submitted to delete", len(validVins))
 
 	statuses := make([]VinStatus, 0, len(params.VinDeleteData))
@@ -196,35 +185,33 @@ func (v *VehicleController) SubmitDeleteDataForVins(c *fiber.Ctx) error {
 		dbVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicles")
+				return NewAPIError(ErrCodeNotFound, "Could not find Vehicles")
 			}
 
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
 		}
 
+		batch.known(validVins, dbVins)
+
 		indexedDbVins := make(map[string]*dbmodels.Vin)
-		for _, vin := range dbVins {
+		for _, vin := range batch.owned(c.UserContext(), walletAddress, dbVins) {
 			indexedDbVins[vin.Vin] = vin
 		}
 
 		for _, deleteVehicle := range validVinsDeleteData {
-			dbVin, ok := indexedDbVins[deleteVehicle.Vin]
-			if !ok {
-				dbVin = &dbmodels.Vin{
-					Vin:              deleteVehicle.Vin,
-					OnboardingStatus: onboarding.OnboardingStatusDeleteSubmitUnknown,
-				}
+			if batch.isDropped(deleteVehicle.Vin) {
+				continue
 			}
 
+			dbVin := indexedDbVins[deleteVehicle.Vin]
+
 			if v.canSubmitDeleteJob(dbVin) {
 				localLog.Debug().Str(logfields.VIN, deleteVehicle.Vin).Msg("Submitting deleteVehicle job")
 
 				op := deleteVehicle.UserOperation
 				op.Signature = deleteVehicle.Signature
 
-				_, err = v.riverClient.Insert(c.Context(), onboarding.DeleteArgs{
+				_, err = v.riverClient.Insert(c.UserContext(), onboarding.DeleteArgs{
 					VIN:           deleteVehicle.Vin,
 					UserOperation: deleteVehicle.UserOperation,
 				}, nil)
@@ -235,6 +222,7 @@ func (v *VehicleController) SubmitDeleteDataForVins(c *fiber.Ctx) error {
 						Vin:     deleteVehicle.Vin,
 						Status:  "Failure",
 						Details: onboarding.GetDetailedStatus(onboarding.OnboardingStatusDeleteSubmitFailure),
+						Reason:  ErrCodeSubmitFailed,
 					})
 				} else {
 					v.logger.Debug().Str(logfields.VIN, deleteVehicle.Vin).Msg("deleteVehicle job submitted")
@@ -250,22 +238,21 @@ func (v *VehicleController) SubmitDeleteDataForVins(c *fiber.Ctx) error {
 					Vin:     deleteVehicle.Vin,
 					Status:  onboarding.GetBurnStatus(dbVin.OnboardingStatus),
 					Details: onboarding.GetDetailedStatus(dbVin.OnboardingStatus),
+					Reason:  ErrCodeInvalidStatus,
 				})
 			}
 
 			err = v.vs.InsertOrUpdateVin(c.Context(), dbVin)
 
 			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": fmt.Sprintf("Failed to submit deleteVehicle: %v", deleteVehicle),
-				})
+				return NewAPIError(ErrCodeInternal, fmt.Sprintf("Failed to submit deleteVehicle: %v", deleteVehicle))
 			}
 			localLog.Debug().Str(logfields.VIN, deleteVehicle.Vin).Msg("Submitted deleteVehicle for VIN")
 		}
 	}
 
 	return c.JSON(StatusForVinsResponse{
-		Statuses: statuses,
+		Statuses: append(statuses, batch.rejected...),
 	})
 }
 
@@ -281,38 +268,43 @@ func (v *VehicleController) canSubmitDeleteJob(record *dbmodels.Vin) bool {
 	return (disconnected || failed) && !pending
 }
 
+// GetDeleteStatusForVins
+// @Summary Get delete status for each of the submitted VINs
+// @Description Invalid and repeated VINs are returned with status Rejected and a reason code
+// @Produce json
+// @Param vins query []string true "VINs"
+// @Success 200 {object} StatusForVinsResponse
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicle/delete/status [get]
 func (v *VehicleController) GetDeleteStatusForVins(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
 	params := new(VinsGetParams)
 	if err := c.QueryParser(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Failed to parse VINs",
-		})
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to parse VINs")
+	}
+
+	if err := v.checkBatchSize(len(params.Vins)); err != nil {
+		return err
 	}
 
 	localLog := v.logger.With().Str(logfields.FunctionName, "GetDeleteStatusForVins").Interface("validVins", params.Vins).Logger()
 	localLog.Debug().Interface("vins", params.Vins).Msg("Checking Delete Status for Vins")
 
+	batch := v.newVinBatch()
 	validVins := make([]string, 0, len(params.Vins))
 	for _, vin := range params.Vins {
-		strippedVin := strings.TrimSpace(vin)
-		if v.isValidVin(strippedVin) {
+		if strippedVin, ok := batch.accept(vin); ok {
 			validVins = append(validVins, strippedVin)
 		}
 	}
 
-	if len(validVins) != len(params.Vins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid VINs provided",
-		})
-	}
-
-	compactedVins := slices.Compact(validVins)
-	if len(validVins) != len(compactedVins) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Duplicated VINs",
-		})
-	}
-
 	localLog.Debug().Interface("validVins", validVins).Msgf("Got %d valid VINs", len(validVins))
 
 	statuses := make([]VinStatus, 0, len(validVins))
@@ -321,12 +313,14 @@ func (v *VehicleController) GetDeleteStatusForVins(c *fiber.Ctx) error {
 		dbVins, err := v.vs.GetVehiclesByVins(c.Context(), validVins)
 		if err != nil {
 			if errors.Is(err, service.ErrVehicleNotFound) {
-				return fiber.NewError(fiber.StatusNotFound, "Could not find Vehicles")
+				return NewAPIError(ErrCodeNotFound, "Could not find Vehicles")
 			}
 
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to load vehicles from Database",
-			})
+			return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
+		}
+
+		if err := v.checkVinsOwner(c.UserContext(), walletAddress, dbVins); err != nil {
+			return err
 		}
 
 		indexedVins := make(map[string]*dbmodels.Vin)
@@ -347,12 +341,13 @@ func (v *VehicleController) GetDeleteStatusForVins(c *fiber.Ctx) error {
 					Vin:     vin,
 					Status:  onboarding.GetBurnStatus(dbVin.OnboardingStatus),
 					Details: onboarding.GetDetailedStatus(dbVin.OnboardingStatus),
+					Reason:  failureErrCode(dbVin.OnboardingStatus),
 				})
 			}
 		}
 	}
 
 	return c.JSON(StatusForVinsResponse{
-		Statuses: statuses,
+		Statuses: append(statuses, batch.rejected...),
 	})
 }
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"time"
+)
+
+const vinEventsKeepAliveInterval = 15 * time.Second
+
+// VinStatusChange status of a VIN after a change of its onboarding or connection status
+type VinStatusChange struct {
+	VinStatus
+	ConnectionStatus    string                 `json:"connectionStatus,omitempty"`
+	DisconnectionStatus string                 `json:"disconnectionStatus,omitempty"`
+	OperationError      *models.OperationError `json:"operationError,omitempty"`
+}
+
+// StreamVinEvents
+// @Summary Stream onboarding and connection status changes of user's VINs
+// @Description Server-Sent Events stream, every onboarding or connection status change of a VIN owned by the wallet is sent as VinStatusChange in a "status" event.
+// @Description The stream ends when the server shuts down, clients reconnect.
+// @Produce text/event-stream
+// @Success 200
+// @Security BearerAuth
+// @Router /v1/vehicle/events [get]
+func (v *VehicleController) StreamVinEvents(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
+	events, unsubscribe := v.events.Subscribe(walletAddress)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		keepAlive := time.NewTicker(vinEventsKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		// flush headers right away, so the client knows the stream is open
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					// shutting down
+					return
+				}
+				data, err := json.Marshal(vinStatusChange(event))
+				if err != nil {
+					v.logger.Error().Err(err).Msg("Failed to marshal VIN status event")
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				if _, err := w.WriteString(": keep-alive\n\n"); err != nil {
+					return
+				}
+			}
+
+			// write errors only show up on flush once the client is gone
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return nil
+}
+
+func vinStatusChange(event service.VinStatusEvent) VinStatusChange {
+	change := VinStatusChange{
+		VinStatus: VinStatus{
+			Vin:     event.Vin,
+			Status:  onboarding.GetStatus(event.OnboardingStatus),
+			Details: onboarding.GetDetailedStatus(event.OnboardingStatus),
+		},
+		ConnectionStatus:    event.ConnectionStatus,
+		DisconnectionStatus: event.DisconnectionStatus,
+	}
+	if event.OperationErrorCode != "" || event.OperationErrorType != "" {
+		change.OperationError = &models.OperationError{
+			Code:        event.OperationErrorCode,
+			Type:        event.OperationErrorType,
+			Description: event.OperationErrorDescription,
+		}
+	}
+
+	return change
+}
@@ -0,0 +1,499 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/DIMO-Network/shared/pkg/logfields"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/friendsofgo/errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/volatiletech/null/v8"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcomes of the rows of an import, when it was submitted
+const (
+	importOutcomePending    = "Pending"
+	importOutcomeRejected   = vinStatusRejected
+	importOutcomeSubmitted  = "Submitted"
+	importOutcomeRegistered = "Registered"
+	importOutcomeSkipped    = "Skipped"
+	importOutcomeFailure    = "Failure"
+)
+
+const importFileField = "file"
+
+var errImportTooManyRows = errors.New("too many rows")
+
+// importColumns header names of the CSV columns, files without a header have the columns in this order
+var importColumns = map[string][]string{
+	"vin":          {"vin"},
+	"country_code": {"country_code", "countrycode", "country code", "country"},
+	"token_id":     {"token_id", "tokenid", "token id"},
+}
+
+// ImportRowStatus is the outcome of a row of an import and the verification status of its VIN now
+type ImportRowStatus struct {
+	Row         int    `json:"row"`
+	Vin         string `json:"vin"`
+	CountryCode string `json:"countryCode,omitempty"`
+	TokenID     int64  `json:"tokenId,omitempty"`
+	// Rejected and Failure rows were not imported, Submitted got a verify job, Registered were already minted and
+	// Skipped are VINs whose status doesn't allow verifying them again
+	Outcome string `json:"outcome"`
+	// Pending while the verify job runs, then Success or Failure
+	Status  string `json:"status"`
+	Details string `json:"details"`
+	Reason  string `json:"reason,omitempty"` // see the ErrCode* codes
+}
+
+// ImportBatchResponse is an import with the status of every row
+type ImportBatchResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	RowCount  int       `json:"rowCount"`
+	// number of rows by status
+	Summary map[string]int `json:"summary"`
+	// no row is pending anymore
+	Done bool              `json:"done"`
+	Rows []ImportRowStatus `json:"rows"`
+}
+
+// ImportVehicles
+// @Summary Imports a fleet from a CSV file
+// @Description Columns are the VIN, the country code and optionally the token ID of a vehicle minted already, a header
+// @Description row with vin, country_code and token_id allows any column order. Every row is validated on its own:
+// @Description vehicles with a token ID are registered, the others submitted for verification. The file is sent as
+// @Description the text/csv body, or as the file field of a multipart form.
+// @Accept csv
+// @Produce json
+// @Param file body string true "CSV file"
+// @Success 200 {object} ImportBatchResponse
+// @Failure 400 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicles/import [post]
+func (v *VehicleController) ImportVehicles(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
+	file, err := importFile(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInvalidRequest, "Failed to read the CSV file")
+	}
+	defer file.Close()
+
+	rows, err := parseImportCSV(file, v.isValidVin, v.settings.MaxImportRows)
+	if err != nil {
+		if errors.Is(err, errImportTooManyRows) {
+			return NewAPIError(ErrCodeBatchTooLarge, fmt.Sprintf("Too many rows, at most %d can be imported at once", v.settings.MaxImportRows))
+		}
+		return NewAPIError(ErrCodeInvalidRequest, fmt.Sprintf("Failed to parse the CSV file: %s", err))
+	}
+	if len(rows) == 0 {
+		return NewAPIError(ErrCodeInvalidRequest, "The CSV file has no rows")
+	}
+
+	localLog := v.logger.With().Str(logfields.FunctionName, "ImportVehicles").Logger()
+	localLog.Debug().Msgf("Importing %d rows", len(rows))
+
+	// the import is stored before any row is processed, so it can be looked up whatever happens while processing
+	batch, err := v.vs.CreateImportBatch(c.UserContext(), walletAddress, rows)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to store the import")
+	}
+
+	// rows which couldn't be processed fail, the response still has the import with every row
+	if err := v.importRows(c.UserContext(), walletAddress, rows); err != nil {
+		localLog.Error().Err(err).Msgf("Failed to process import %s", batch.ID)
+		failImportRows(rows, err)
+	}
+
+	if err := v.vs.UpdateImportRows(c.UserContext(), rows); err != nil {
+		localLog.Error().Err(err).Msgf("Failed to store the outcome of import %s", batch.ID)
+		return NewAPIError(ErrCodeInternal, "Failed to store the import")
+	}
+
+	response, err := v.importBatchResponse(c.UserContext(), batch, rows)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// GetImportBatch
+// @Summary Get the status of every row of an import
+// @Produce json
+// @Param id path string true "Import ID"
+// @Success 200 {object} ImportBatchResponse
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicles/import/{id} [get]
+func (v *VehicleController) GetImportBatch(c *fiber.Ctx) error {
+	batch, rows, err := v.getImportBatch(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := v.importBatchResponse(c.UserContext(), batch, rows)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// GetImportErrors
+// @Summary Download the failed rows of an import as CSV
+// @Description Rows which were rejected or whose verification failed, with the reason code and details
+// @Produce csv
+// @Param id path string true "Import ID"
+// @Success 200 {string} string "CSV file"
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicles/import/{id}/errors [get]
+func (v *VehicleController) GetImportErrors(c *fiber.Ctx) error {
+	batch, rows, err := v.getImportBatch(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := v.importBatchResponse(c.UserContext(), batch, rows)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"row", "vin", "country_code", "token_id", "status", "reason", "details"})
+	for _, row := range response.Rows {
+		if row.Status != importOutcomeRejected && row.Status != importOutcomeFailure {
+			continue
+		}
+
+		tokenID := ""
+		if row.TokenID != 0 {
+			tokenID = strconv.FormatInt(row.TokenID, 10)
+		}
+		_ = w.Write([]string{strconv.Itoa(row.Row), row.Vin, row.CountryCode, tokenID, row.Status, row.Reason, row.Details})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to write the error report")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Attachment(fmt.Sprintf("import-%s-errors.csv", batch.ID))
+	return c.Send(buf.Bytes())
+}
+
+func (v *VehicleController) getImportBatch(c *fiber.Ctx) (*dbmodels.ImportBatch, dbmodels.ImportRowSlice, error) {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return nil, nil, NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
+	batch, err := v.vs.GetImportBatch(c.UserContext(), walletAddress, c.Params("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrImportNotFound) {
+			return nil, nil, NewAPIError(ErrCodeNotFound, "Import not found")
+		}
+		return nil, nil, NewAPIError(ErrCodeInternal, "Failed to load import from Database")
+	}
+
+	rows, err := v.vs.GetImportRows(c.UserContext(), batch.ID)
+	if err != nil {
+		return nil, nil, NewAPIError(ErrCodeInternal, "Failed to load import from Database")
+	}
+
+	return batch, rows, nil
+}
+
+// importFile the file field of a multipart form, the body otherwise
+func importFile(c *fiber.Ctx) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return io.NopCloser(bytes.NewReader(c.Body())), nil
+	}
+
+	header, err := c.FormFile(importFileField)
+	if err != nil {
+		return nil, err
+	}
+
+	return header.Open()
+}
+
+// parseImportCSV reads the rows of the file, rows which are invalid on their own or repeat a VIN are rejected.
+// Row numbers are the lines of the file.
+func parseImportCSV(r io.Reader, isValidVin func(string) bool, maxRows int) (dbmodels.ImportRowSlice, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"vin": 0, "country_code": 1, "token_id": 2}
+	seen := make(map[string]struct{})
+	rows := make(dbmodels.ImportRowSlice, 0)
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if first {
+			if header, ok := importHeader(record); ok {
+				columns = header
+				continue
+			}
+		}
+
+		if maxRows > 0 && len(rows) >= maxRows {
+			return nil, errImportTooManyRows
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := &dbmodels.ImportRow{
+			RowNumber:   line,
+			Outcome:     importOutcomePending,
+			Vin:         importField(record, columns, "vin"),
+			CountryCode: importField(record, columns, "country_code"),
+		}
+		rows = append(rows, row)
+
+		if !isValidVin(row.Vin) {
+			rejectImportRow(row, ErrCodeInvalidVin, "Invalid VIN")
+			continue
+		}
+		if _, ok := seen[row.Vin]; ok {
+			rejectImportRow(row, ErrCodeDuplicateVin, "Duplicated VIN")
+			continue
+		}
+		seen[row.Vin] = struct{}{}
+
+		if rawTokenID := importField(record, columns, "token_id"); rawTokenID != "" {
+			tokenID, err := strconv.ParseInt(rawTokenID, 10, 64)
+			if err != nil || tokenID <= 0 {
+				rejectImportRow(row, ErrCodeInvalidData, "Invalid token ID")
+				continue
+			}
+			row.TokenID = null.Int64From(tokenID)
+		}
+	}
+
+	return rows, nil
+}
+
+// importHeader column indexes of a header record, which has a vin column
+func importHeader(record []string) (map[string]int, bool) {
+	columns := make(map[string]int)
+	for i, field := range record {
+		name := strings.ToLower(strings.TrimSpace(field))
+		for column, names := range importColumns {
+			for _, n := range names {
+				if name == n {
+					columns[column] = i
+				}
+			}
+		}
+	}
+
+	_, ok := columns["vin"]
+	return columns, ok
+}
+
+func importField(record []string, columns map[string]int, column string) string {
+	i, ok := columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[i])
+}
+
+func rejectImportRow(row *dbmodels.ImportRow, reason, details string) {
+	row.Outcome = importOutcomeRejected
+	row.Reason = null.StringFrom(reason)
+	row.Details = null.StringFrom(details)
+}
+
+// failImportRows marks the rows which weren't processed as failed, with the reason of the API error
+func failImportRows(rows dbmodels.ImportRowSlice, err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = NewAPIError(ErrCodeInternal, "Failed to process the row")
+	}
+
+	for _, row := range rows {
+		if row.Outcome == importOutcomePending {
+			row.Outcome = importOutcomeFailure
+			row.Reason = null.StringFrom(apiErr.Code)
+			row.Details = null.StringFrom(apiErr.Message)
+		}
+	}
+}
+
+// importRows registers the valid rows with a token ID and submits the others for verification, setting the outcome
+// of every pending row
+func (v *VehicleController) importRows(ctx context.Context, walletAddress common.Address, rows dbmodels.ImportRowSlice) error {
+	var identityVehicles map[int64]models.Vehicle
+	for _, row := range rows {
+		if row.Outcome == importOutcomePending && row.TokenID.Valid {
+			vehicles, err := v.identity.FetchVehiclesByWalletAddress(ctx, walletAddress.String())
+			if err != nil {
+				return NewAPIError(ErrCodeInternal, "Failed to load vehicles from Identity API")
+			}
+
+			identityVehicles = make(map[int64]models.Vehicle, len(vehicles))
+			for _, vehicle := range vehicles {
+				identityVehicles[vehicle.TokenID] = vehicle
+			}
+			break
+		}
+	}
+
+	batch := v.newVinBatch()
+	toVerify := make([]VinWithCountryCode, 0, len(rows))
+	for _, row := range rows {
+		if row.Outcome != importOutcomePending {
+			continue
+		}
+
+		if row.TokenID.Valid {
+			identityVehicle, ok := identityVehicles[row.TokenID.Int64]
+			if !ok {
+				rejectImportRow(row, ErrCodeNotOwned, "Vehicle not found or not owned by wallet")
+				continue
+			}
+
+			if _, err := v.storeRegisteredVin(ctx, walletAddress, row.Vin, &identityVehicle); err != nil {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					apiErr = NewAPIError(ErrCodeInternal, "Failed to register Vehicle")
+				}
+				rejectImportRow(row, apiErr.Code, apiErr.Message)
+				continue
+			}
+			row.Outcome = importOutcomeRegistered
+			continue
+		}
+
+		if _, ok := batch.accept(row.Vin); ok {
+			toVerify = append(toVerify, VinWithCountryCode{Vin: row.Vin, CountryCode: row.CountryCode})
+		}
+	}
+
+	statuses, err := v.submitVerification(ctx, walletAddress, batch, toVerify)
+	if err != nil {
+		return err
+	}
+
+	statusByVin := make(map[string]VinStatus, len(toVerify))
+	for _, status := range append(statuses, batch.rejected...) {
+		statusByVin[status.Vin] = status
+	}
+
+	for _, row := range rows {
+		if row.Outcome != importOutcomePending {
+			continue
+		}
+
+		status := statusByVin[row.Vin]
+		switch {
+		case status.Status == vinStatusRejected:
+			row.Outcome = importOutcomeRejected
+		case status.Reason == ErrCodeSubmitFailed:
+			row.Outcome = importOutcomeFailure
+		case status.Reason != "":
+			row.Outcome = importOutcomeSkipped
+		default:
+			row.Outcome = importOutcomeSubmitted
+		}
+		row.Reason = null.NewString(status.Reason, status.Reason != "")
+		row.Details = null.NewString(status.Details, status.Details != "")
+	}
+
+	return nil
+}
+
+// importBatchResponse builds the status of every row, from the onboarding status of the imported VINs now
+func (v *VehicleController) importBatchResponse(ctx context.Context, batch *dbmodels.ImportBatch, rows dbmodels.ImportRowSlice) (*ImportBatchResponse, error) {
+	vins := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Outcome != importOutcomeRejected && row.Outcome != importOutcomeFailure {
+			vins = append(vins, row.Vin)
+		}
+	}
+
+	dbVinsByVin := make(map[string]*dbmodels.Vin, len(vins))
+	if len(vins) > 0 {
+		dbVins, err := v.vs.GetVehiclesByVins(ctx, vins)
+		if err != nil && !errors.Is(err, service.ErrVehicleNotFound) {
+			return nil, NewAPIError(ErrCodeInternal, "Failed to load vehicles from Database")
+		}
+		for _, dbVin := range dbVins {
+			dbVinsByVin[dbVin.Vin] = dbVin
+		}
+	}
+
+	response := &ImportBatchResponse{
+		ID:        batch.ID,
+		CreatedAt: batch.CreatedAt,
+		RowCount:  batch.RowCount,
+		Summary:   make(map[string]int),
+		Done:      true,
+		Rows:      make([]ImportRowStatus, 0, len(rows)),
+	}
+	for _, row := range rows {
+		status := ImportRowStatus{
+			Row:         row.RowNumber,
+			Vin:         row.Vin,
+			CountryCode: row.CountryCode,
+			TokenID:     row.TokenID.Int64,
+			Outcome:     row.Outcome,
+			Status:      row.Outcome,
+			Details:     row.Details.String,
+			Reason:      row.Reason.String,
+		}
+
+		dbVin, ok := dbVinsByVin[row.Vin]
+		switch {
+		case row.Outcome == importOutcomeRejected || row.Outcome == importOutcomeFailure:
+		case row.Outcome == importOutcomeRegistered:
+			status.Status = "Success"
+			status.Details = importOutcomeRegistered
+		case !ok:
+			status.Status = "Unknown"
+			status.Details = "Unknown"
+		default:
+			status.Status = onboarding.GetVerificationStatus(dbVin.OnboardingStatus)
+			status.Details = onboarding.GetDetailedStatus(dbVin.OnboardingStatus)
+			if reason := failureErrCode(dbVin.OnboardingStatus); reason != "" {
+				status.Reason = reason
+			}
+		}
+
+		if status.Status == "Pending" {
+			response.Done = false
+		}
+		response.Summary[status.Status]++
+		response.Rows = append(response.Rows, status)
+	}
+
+	return response, nil
+}
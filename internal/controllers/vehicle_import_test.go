@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type VehicleImportTestSuite struct {
+	suite.Suite
+}
+
+func TestVehicleImportTestSuite(t *testing.T) {
+	suite.Run(t, new(VehicleImportTestSuite))
+}
+
+func isValidTestVin(vin string) bool {
+	return len(vin) == 17
+}
+
+func (s *VehicleImportTestSuite) TestParseImportCSV() {
+	file := strings.Join([]string{
+		"Token ID,VIN,Country Code",
+		"12,1HGCM82633A004352,USA",
+		",1HGCM82633A004353, DEU ",
+		",1HGCM82633A00435,USA",
+		",1HGCM82633A004353,USA",
+		"abc,1HGCM82633A004354,USA",
+		"",
+		`,"1HGCM82633A004355",`,
+	}, "\n")
+
+	rows, err := parseImportCSV(strings.NewReader(file), isValidTestVin, 0)
+	s.Require().NoError(err)
+	s.Require().Len(rows, 6)
+
+	s.Equal(2, rows[0].RowNumber)
+	s.Equal("1HGCM82633A004352", rows[0].Vin)
+	s.Equal("USA", rows[0].CountryCode)
+	s.Equal(int64(12), rows[0].TokenID.Int64)
+	s.Equal(importOutcomePending, rows[0].Outcome)
+
+	s.Equal("DEU", rows[1].CountryCode)
+	s.False(rows[1].TokenID.Valid)
+	s.Equal(importOutcomePending, rows[1].Outcome)
+
+	s.Equal(importOutcomeRejected, rows[2].Outcome)
+	s.Equal(ErrCodeInvalidVin, rows[2].Reason.String)
+
+	s.Equal(importOutcomeRejected, rows[3].Outcome)
+	s.Equal(ErrCodeDuplicateVin, rows[3].Reason.String)
+
+	s.Equal(importOutcomeRejected, rows[4].Outcome)
+	s.Equal(ErrCodeInvalidData, rows[4].Reason.String)
+
+	// empty lines are skipped, row numbers are lines of the file
+	s.Equal(8, rows[5].RowNumber)
+	s.Equal("1HGCM82633A004355", rows[5].Vin)
+	s.Empty(rows[5].CountryCode)
+	s.Equal(importOutcomePending, rows[5].Outcome)
+}
+
+func (s *VehicleImportTestSuite) TestParseImportCSVWithoutHeader() {
+	rows, err := parseImportCSV(strings.NewReader("1HGCM82633A004352,USA\n1HGCM82633A004353,CAN,7\n"), isValidTestVin, 0)
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+
+	s.Equal(1, rows[0].RowNumber)
+	s.Equal("USA", rows[0].CountryCode)
+	s.False(rows[0].TokenID.Valid)
+	s.Equal("CAN", rows[1].CountryCode)
+	s.Equal(int64(7), rows[1].TokenID.Int64)
+}
+
+func (s *VehicleImportTestSuite) TestParseImportCSVErrors() {
+	_, err := parseImportCSV(strings.NewReader("vin\n1HGCM82633A004352\n1HGCM82633A004353\n"), isValidTestVin, 1)
+	s.ErrorIs(err, errImportTooManyRows)
+
+	_, err = parseImportCSV(strings.NewReader("vin\n\"1HGCM82633A004352\n"), isValidTestVin, 0)
+	s.Error(err)
+
+	rows, err := parseImportCSV(strings.NewReader("vin,country_code\n"), isValidTestVin, 0)
+	s.Require().NoError(err)
+	s.Empty(rows)
+}
+
+func (s *VehicleImportTestSuite) TestFailImportRows() {
+	rows, err := parseImportCSV(strings.NewReader("vin\n1HGCM82633A004352\nshort\n"), isValidTestVin, 0)
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+
+	failImportRows(rows, NewAPIError(ErrCodeInternal, "Failed to load vehicles from Identity API"))
+	s.Equal(importOutcomeFailure, rows[0].Outcome)
+	s.Equal(ErrCodeInternal, rows[0].Reason.String)
+	s.Equal("Failed to load vehicles from Identity API", rows[0].Details.String)
+	s.Equal(importOutcomeRejected, rows[1].Outcome)
+
+	rows[0].Outcome = importOutcomePending
+	failImportRows(rows, errors.New("dial tcp 10.0.0.1:5432: connection refused"))
+	s.Equal("Failed to process the row", rows[0].Details.String)
+}
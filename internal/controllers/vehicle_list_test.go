@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/mocks"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/test"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"gotest.tools/v3/assert"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+func (s *VehicleControllerTestSuite) TestGetVehicles() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	identity := mocks.NewIdentityAPIMock([]models.Vehicle{
+		{TokenID: 1, Owner: testWalletAddress.Hex(), MintedAt: "2025-01-01T00:00:00Z", Definition: models.Definition{ID: "ford_f-150_2022", Make: "Ford", Model: "F-150", Year: 2022}},
+		// transferred to another wallet
+		{TokenID: 2, Owner: common.HexToAddress("0x2").Hex()},
+		// transferred to the wallet
+		{TokenID: 3, Owner: testWalletAddress.Hex()},
+		// minted before owners were stored
+		{TokenID: 4, Owner: testWalletAddress.Hex()},
+	}, []models.DeviceDefinition{})
+
+	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, identity, s.vs, s.river, nil, nil, nil)
+	app := fiber.New()
+	app.Get("/vehicles", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.GetVehicles)
+
+	vins := []dbmodels.Vin{
+		{Vin: "ABCDEFG1234567811", OnboardingStatus: onboarding.OnboardingStatusMintSuccess, VehicleTokenID: null.Int64From(1), ConnectionStatus: null.StringFrom("succeeded"),
+			DefinitionMake: null.StringFrom("Ford"), DefinitionModel: null.StringFrom("F-150"), DefinitionYear: null.IntFrom(2022)},
+		{Vin: "ABCDEFG1234567812", OnboardingStatus: onboarding.OnboardingStatusMintSuccess, VehicleTokenID: null.Int64From(2)},
+		{Vin: "ABCDEFG1234567813", OnboardingStatus: onboarding.OnboardingStatusVendorValidationFailure, OperationErrorCode: null.StringFrom("notCapable"),
+			DefinitionMake: null.StringFrom("Audi"), DefinitionModel: null.StringFrom("A4"), DefinitionYear: null.IntFrom(2020)},
+		{Vin: "ABCDEFG1234567814", OnboardingStatus: onboarding.OnboardingStatusDecodingPending},
+		{Vin: "ABCDEFG1234567815", OnboardingStatus: onboarding.OnboardingStatusDecodingPending},
+	}
+	for _, vin := range vins {
+		vin.OwnerAddress = null.StringFrom(testWalletAddress.Hex())
+		require.NoError(t, vin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+	}
+	for _, other := range []dbmodels.Vin{
+		// VIN of another wallet
+		{Vin: "ABCDEFG1234567816", OwnerAddress: null.StringFrom(common.HexToAddress("0x2").Hex())},
+		{Vin: "ABCDEFG1234567817", OnboardingStatus: onboarding.OnboardingStatusMintSuccess, VehicleTokenID: null.Int64From(3), OwnerAddress: null.StringFrom(common.HexToAddress("0x2").Hex())},
+		{Vin: "ABCDEFG1234567818", OnboardingStatus: onboarding.OnboardingStatusMintSuccess, VehicleTokenID: null.Int64From(4)},
+		// owner backfilled from an onboarding job, lowercase
+		{Vin: "ABCDEFG1234567819", OnboardingStatus: onboarding.OnboardingStatusDecodingPending, OwnerAddress: null.StringFrom(strings.ToLower(testWalletAddress.Hex()))},
+	} {
+		require.NoError(t, other.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+	}
+
+	getVehicles := func(query url.Values) (int, VehiclesResponse) {
+		response, _ := app.Test(test.BuildRequest("GET", "/vehicles?"+query.Encode(), ""))
+		body, _ := io.ReadAll(response.Body)
+
+		var result VehiclesResponse
+		_ = json.Unmarshal(body, &result)
+		return response.StatusCode, result
+	}
+
+	listedVins := func(vehicles []models.Vehicle) []string {
+		result := make([]string, 0, len(vehicles))
+		for _, vehicle := range vehicles {
+			result = append(result, vehicle.VIN)
+		}
+		return result
+	}
+
+	s.Run("Page through all vehicles", func() {
+		code, page := getVehicles(url.Values{"limit": {"2"}})
+		assert.Equal(t, fiber.StatusOK, code)
+		// the transferred vehicle is left out
+		assert.DeepEqual(t, []string{"ABCDEFG1234567811"}, listedVins(page.Vehicles))
+		assert.Assert(t, page.NextCursor != "")
+
+		minted := page.Vehicles[0]
+		assert.Equal(t, "2025-01-01T00:00:00Z", minted.MintedAt)
+		assert.Equal(t, "succeeded", minted.ConnectionStatus)
+		assert.Equal(t, "Success", minted.Onboarding.Status)
+
+		code, page = getVehicles(url.Values{"limit": {"2"}, "cursor": {page.NextCursor}})
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.DeepEqual(t, []string{"ABCDEFG1234567813", "ABCDEFG1234567814"}, listedVins(page.Vehicles))
+		assert.Equal(t, "Audi", page.Vehicles[0].Definition.Make)
+		assert.Equal(t, "VendorValidationFailure", page.Vehicles[0].Onboarding.Details)
+		assert.Equal(t, "notCapable", page.Vehicles[0].Onboarding.LastError.Code)
+
+		code, page = getVehicles(url.Values{"limit": {"2"}, "cursor": {page.NextCursor}})
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.DeepEqual(t, []string{"ABCDEFG1234567815", "ABCDEFG1234567819"}, listedVins(page.Vehicles))
+		assert.Equal(t, "", page.NextCursor)
+
+		// the new owner of the transferred vehicle is stored
+		transferred, err := s.vs.GetVehicleByVin(s.ctx, "ABCDEFG1234567812")
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToAddress("0x2").Hex(), transferred.OwnerAddress.String)
+	})
+
+	s.Run("Vehicles held on chain are listed once their owners are synced", func() {
+		worker := onboarding.NewVehicleOwnersSyncWorker(mockDeps.logger, identity, s.vs)
+		require.NoError(t, worker.Work(s.ctx, nil))
+
+		_, page := getVehicles(url.Values{})
+		assert.DeepEqual(t, []string{"ABCDEFG1234567811", "ABCDEFG1234567813", "ABCDEFG1234567814", "ABCDEFG1234567815", "ABCDEFG1234567817", "ABCDEFG1234567818", "ABCDEFG1234567819"}, listedVins(page.Vehicles))
+	})
+
+	s.Run("Filter vehicles", func() {
+		_, page := getVehicles(url.Values{"phase": {onboarding.OnboardingPhaseVerification}})
+		assert.DeepEqual(t, []string{"ABCDEFG1234567813", "ABCDEFG1234567814", "ABCDEFG1234567815", "ABCDEFG1234567819"}, listedVins(page.Vehicles))
+
+		_, page = getVehicles(url.Values{"hasError": {"true"}})
+		assert.DeepEqual(t, []string{"ABCDEFG1234567813"}, listedVins(page.Vehicles))
+
+		_, page = getVehicles(url.Values{"make": {"ford"}, "year": {"2022"}})
+		assert.DeepEqual(t, []string{"ABCDEFG1234567811"}, listedVins(page.Vehicles))
+
+		_, page = getVehicles(url.Values{"connectionStatus": {"succeeded"}})
+		assert.DeepEqual(t, []string{"ABCDEFG1234567811"}, listedVins(page.Vehicles))
+	})
+
+	s.Run("Sort vehicles", func() {
+		code, page := getVehicles(url.Values{"sort": {"-year"}, "limit": {"1"}})
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.DeepEqual(t, []string{"ABCDEFG1234567811"}, listedVins(page.Vehicles))
+
+		_, page = getVehicles(url.Values{"sort": {"-year"}, "limit": {"1"}, "cursor": {page.NextCursor}})
+		assert.DeepEqual(t, []string{"ABCDEFG1234567813"}, listedVins(page.Vehicles))
+
+		// cursor of another sort order
+		code, _ = getVehicles(url.Values{"sort": {"year"}, "cursor": {page.NextCursor}})
+		assert.Equal(t, fiber.StatusBadRequest, code)
+	})
+
+	s.Run("Minted vehicles missing in Identity API are left out", func() {
+		missing := dbmodels.Vin{Vin: "ABCDEFG1234567820", OnboardingStatus: onboarding.OnboardingStatusMintSuccess, VehicleTokenID: null.Int64From(5),
+			OwnerAddress: null.StringFrom(testWalletAddress.Hex())}
+		require.NoError(t, missing.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+		defer func() { _, _ = missing.Delete(s.ctx, s.pdb.DBS().Writer) }()
+
+		code, page := getVehicles(url.Values{})
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Assert(t, !slices.Contains(listedVins(page.Vehicles), missing.Vin))
+	})
+
+	s.Run("Identity API unavailable", func() {
+		identity.Err = errors.New("identity api unavailable")
+		defer func() { identity.Err = nil }()
+
+		code, page := getVehicles(url.Values{})
+		assert.Equal(t, fiber.StatusInternalServerError, code)
+		assert.Equal(t, 0, len(page.Vehicles))
+	})
+
+	s.Run("Invalid parameters", func() {
+		for _, query := range []url.Values{
+			{"limit": {"1000"}},
+			{"phase": {"unknown"}},
+			{"hasError": {"maybe"}},
+			{"sort": {"owner"}},
+			{"cursor": {"not a cursor"}},
+		} {
+			code, _ := getVehicles(query)
+			assert.Equal(t, fiber.StatusBadRequest, code, query.Encode())
+		}
+	})
+}
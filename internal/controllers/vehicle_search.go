@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"github.com/DIMO-Network/shared/pkg/logfields"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// parseVehicleIdentifier kind and value of the identifier in the query, exactly one of them must be set
+func parseVehicleIdentifier(c *fiber.Ctx) (string, string, *APIError) {
+	kind, value := "", ""
+	for _, identifier := range service.Identifiers {
+		if query := strings.TrimSpace(c.Query(identifier)); query != "" {
+			if kind != "" {
+				return "", "", NewAPIError(ErrCodeInvalidRequest, "Only one identifier can be searched at a time")
+			}
+			kind, value = identifier, query
+		}
+	}
+
+	if kind == "" {
+		return "", "", NewAPIError(ErrCodeInvalidRequest, "Missing identifier, one of "+strings.Join(service.Identifiers, ", "))
+	}
+
+	return kind, value, nil
+}
+
+// findVehicle resolves the identifier of the query to the VIN record
+func findVehicle(c *fiber.Ctx, vs *service.Vehicle, ws service.SDWalletsAPI) (*dbmodels.Vin, error) {
+	kind, value, apiErr := parseVehicleIdentifier(c)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	record, err := vs.FindVehicle(c.Context(), ws, kind, value)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidIdentifier) {
+			return nil, NewAPIError(ErrCodeInvalidRequest, "Invalid "+kind)
+		}
+		if errors.Is(err, service.ErrVehicleNotFound) {
+			return nil, NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
+		}
+		return nil, NewAPIError(ErrCodeInternal, "Failed to load vehicle from Database")
+	}
+
+	return record, nil
+}
+
+// SearchVehicle
+// @Summary Find user's vehicle by any identifier
+// @Description Finds the vehicle by VIN, vehicle token ID, synthetic device token ID, vendor external ID or synthetic
+// @Description device address, exactly one of them must be set. Returns the same record as the vehicle details.
+// @Description Vehicles of other wallets are not found, like unknown ones.
+// @Produce json
+// @Param vin query string false "VIN"
+// @Param tokenId query int false "vehicle token ID"
+// @Param syntheticTokenId query int false "synthetic device token ID"
+// @Param externalId query string false "vendor vehicle ID"
+// @Param syntheticDeviceAddress query string false "synthetic device address"
+// @Success 200 {object} VehicleResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/vehicles/search [get]
+func (v *VehicleController) SearchVehicle(c *fiber.Ctx) error {
+	walletAddress, err := getWalletAddress(c)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to get wallet address")
+	}
+
+	record, err := findVehicle(c, v.vs, v.ws)
+	if err != nil {
+		return err
+	}
+
+	// not owned vehicles look unknown, so identifiers of other wallets can't be probed
+	if !v.isVinOwner(c.UserContext(), walletAddress, record) {
+		return NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
+	}
+
+	vehicle := toVehicle(record)
+	if record.VehicleTokenID.Valid {
+		identityVehicle, err := v.getIdentityVehicle(c.UserContext(), record.VehicleTokenID.Int64)
+		if err != nil {
+			v.logger.Warn().Err(err).Str(logfields.VIN, record.Vin).Msg("Failed to load vehicle from Identity API, returning stored data")
+		} else if identityVehicle.TokenID != 0 {
+			// the DB owner isn't updated when the vehicle is transferred
+			if common.HexToAddress(identityVehicle.Owner) != walletAddress {
+				return NewAPIError(ErrCodeNotFound, "Could not find Vehicle")
+			}
+			vehicle = *identityVehicle
+		}
+	}
+
+	if err := v.completeVehicle(c.Context(), &vehicle, record); err != nil {
+		return err
+	}
+
+	return c.JSON(VehicleResponse{
+		Vehicle: vehicle,
+	})
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/mocks"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/test"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"gotest.tools/v3/assert"
+	"io"
+	"net/url"
+)
+
+const testSDWalletsSeed = "cabaabd8c7c7d27347349e48fb11319bc6656cb6cc1bdc717e94dae8db7e6bc2"
+
+func (s *VehicleControllerTestSuite) TestSearchVehicle() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	identity := mocks.NewIdentityAPIMock([]models.Vehicle{
+		{TokenID: 21, Owner: testWalletAddress.Hex(), Definition: models.Definition{ID: "ford_f-150_2022", Make: "Ford", Model: "F-150", Year: 2022}},
+		// transferred to another wallet
+		{TokenID: 22, Owner: common.HexToAddress("0x2").Hex()},
+	}, []models.DeviceDefinition{})
+	ws := service.NewSDWalletsService(context.Background(), mockDeps.logger, config.Settings{SDWalletsSeed: testSDWalletsSeed})
+
+	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, identity, s.vs, s.river, ws, nil, nil)
+	app := fiber.New()
+	app.Get("/vehicles/search", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.SearchVehicle)
+
+	vins := []dbmodels.Vin{
+		{Vin: "ABCDEFG1234567831", OnboardingStatus: onboarding.OnboardingStatusMintSuccess, VehicleTokenID: null.Int64From(21),
+			SyntheticTokenID: null.Int64From(31), WalletIndex: null.Int64From(7), ExternalID: null.StringFrom("vendor-31")},
+		{Vin: "ABCDEFG1234567832", OnboardingStatus: onboarding.OnboardingStatusMintSuccess, VehicleTokenID: null.Int64From(22)},
+		{Vin: "ABCDEFG1234567833", OnboardingStatus: onboarding.OnboardingStatusDecodingFailure},
+	}
+	for _, vin := range vins {
+		vin.OwnerAddress = null.StringFrom(testWalletAddress.Hex())
+		require.NoError(t, vin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+	}
+	// VIN of another wallet
+	other := dbmodels.Vin{Vin: "ABCDEFG1234567834", OwnerAddress: null.StringFrom(common.HexToAddress("0x2").Hex())}
+	require.NoError(t, other.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+	sdAddress, err := ws.GetAddress(7)
+	require.NoError(t, err)
+
+	search := func(query url.Values) (int, VehicleResponse) {
+		response, _ := app.Test(test.BuildRequest("GET", "/vehicles/search?"+query.Encode(), ""))
+		body, _ := io.ReadAll(response.Body)
+
+		var result VehicleResponse
+		_ = json.Unmarshal(body, &result)
+		return response.StatusCode, result
+	}
+
+	s.Run("Find minted vehicle by every identifier", func() {
+		for _, query := range []url.Values{
+			{"vin": {"abcdefg1234567831"}},
+			{"tokenId": {"21"}},
+			{"syntheticTokenId": {"31"}},
+			{"externalId": {"vendor-31"}},
+			{"syntheticDeviceAddress": {sdAddress.Hex()}},
+		} {
+			code, result := search(query)
+			assert.Equal(t, fiber.StatusOK, code, query.Encode())
+			assert.Equal(t, "ABCDEFG1234567831", result.Vehicle.VIN)
+			assert.Equal(t, int64(21), result.Vehicle.TokenID)
+			assert.Equal(t, "Ford", result.Vehicle.Definition.Make)
+			assert.Equal(t, sdAddress.Hex(), result.Vehicle.SyntheticDevice.Address)
+			assert.Equal(t, "Success", result.Vehicle.Onboarding.Status)
+		}
+	})
+
+	s.Run("Find vehicle not minted yet", func() {
+		code, result := search(url.Values{"vin": {"ABCDEFG1234567833"}})
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Equal(t, "DecodingFailure", result.Vehicle.Onboarding.Details)
+	})
+
+	s.Run("Synthetic device address is stored once searched", func() {
+		record, err := s.vs.GetVehicleByVin(s.ctx, "ABCDEFG1234567831")
+		assert.NilError(t, err)
+		assert.Equal(t, sdAddress.Hex(), record.SDAddress.String)
+	})
+
+	s.Run("Vehicles of other wallets are not found", func() {
+		code, _ := search(url.Values{"vin": {"ABCDEFG1234567834"}})
+		assert.Equal(t, fiber.StatusNotFound, code)
+
+		// transferred after it was minted
+		code, _ = search(url.Values{"tokenId": {"22"}})
+		assert.Equal(t, fiber.StatusNotFound, code)
+	})
+
+	s.Run("Owner isn't taken from the cache", func() {
+		// the cache still has the owner before the transfer
+		stale := mocks.NewIdentityAPIMock(identity.Vehicles, []models.DeviceDefinition{})
+		stale.Cached = []models.Vehicle{{TokenID: 22, Owner: testWalletAddress.Hex()}}
+
+		c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, stale, s.vs, s.river, ws, nil, nil)
+		app := fiber.New()
+		app.Get("/vehicles/search", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.SearchVehicle)
+
+		response, _ := app.Test(test.BuildRequest("GET", "/vehicles/search?tokenId=22", ""))
+		assert.Equal(t, fiber.StatusNotFound, response.StatusCode)
+	})
+
+	s.Run("Unknown identifier", func() {
+		code, _ := search(url.Values{"syntheticTokenId": {"999"}})
+		assert.Equal(t, fiber.StatusNotFound, code)
+
+		code, _ = search(url.Values{"syntheticDeviceAddress": {common.HexToAddress("0x3").Hex()}})
+		assert.Equal(t, fiber.StatusNotFound, code)
+	})
+
+	s.Run("Invalid search", func() {
+		for _, query := range []url.Values{
+			{},
+			{"vin": {"ABCDEFG1234567831"}, "tokenId": {"21"}},
+			{"tokenId": {"abc"}},
+			{"syntheticDeviceAddress": {"0x123"}},
+		} {
+			code, _ := search(query)
+			assert.Equal(t, fiber.StatusBadRequest, code, query.Encode())
+		}
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
 	"github.com/DIMO-Network/volteras-oracle/internal/service"
 	"github.com/DIMO-Network/volteras-oracle/internal/test"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"gotest.tools/v3/assert"
 	"io"
@@ -36,6 +38,7 @@ type VehicleControllerTestSuite struct {
 	container    testcontainers.Container
 	ctx          context.Context
 	river        *river.Client[pgx.Tx]
+	pool         *pgxpool.Pool
 	verifyWorker river.Worker[onboarding.VerifyArgs]
 	settings     config.Settings
 	vs           *service.Vehicle
@@ -44,6 +47,8 @@ type VehicleControllerTestSuite struct {
 
 const migrationsDirRelPath = "../db/migrations"
 
+var testWalletAddress = common.HexToAddress("0x1A2b3C4d5E6f7a8B9c0D1e2F3a4B5c6D7e8F9a0B")
+
 // SetupSuite starts container db
 func (s *VehicleControllerTestSuite) SetupSuite() {
 	s.ctx = context.Background()
@@ -69,6 +74,7 @@ func (s *VehicleControllerTestSuite) SetupSuite() {
 	})
 
 	s.river = riverClient
+	s.pool = dbPool
 
 	fmt.Println("Suite setup completed.")
 }
@@ -120,11 +126,11 @@ func (s *VehicleControllerTestSuite) TestGetVerificationStatusForVins() {
 	t := s.T()
 	mockDeps := createMockDependencies(t)
 
-	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, mockDeps.identity, s.vs, s.river, nil, nil)
+	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, mockDeps.identity, s.vs, s.river, nil, nil, nil)
 	app := fiber.New(fiber.Config{
 		EnableSplittingOnParsers: true,
 	})
-	app.Get("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", nil), c.GetVerificationStatusForVins)
+	app.Get("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.GetVerificationStatusForVins)
 
 	s.Run("Get verification status for empty VIN list", func() {
 		req, _ := http.NewRequest(
@@ -133,7 +139,16 @@ func (s *VehicleControllerTestSuite) TestGetVerificationStatusForVins() {
 			strings.NewReader(""),
 		)
 		response, _ := app.Test(req)
-		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		var statuses StatusForVinsResponse
+		assert.NilError(t, json.NewDecoder(response.Body).Decode(&statuses))
+		// every empty VIN is rejected
+		assert.Equal(t, 1, len(statuses.Statuses))
+		for _, status := range statuses.Statuses {
+			assert.Equal(t, vinStatusRejected, status.Status)
+			assert.Equal(t, ErrCodeInvalidVin, status.Reason)
+		}
 	})
 
 	s.Run("Get verification status for comma-separated list of empty VINs", func() {
@@ -143,7 +158,16 @@ func (s *VehicleControllerTestSuite) TestGetVerificationStatusForVins() {
 			strings.NewReader(""),
 		)
 		response, _ := app.Test(req)
-		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		var statuses StatusForVinsResponse
+		assert.NilError(t, json.NewDecoder(response.Body).Decode(&statuses))
+		// every empty VIN is rejected
+		assert.Equal(t, 4, len(statuses.Statuses))
+		for _, status := range statuses.Statuses {
+			assert.Equal(t, vinStatusRejected, status.Status)
+			assert.Equal(t, ErrCodeInvalidVin, status.Reason)
+		}
 	})
 
 	s.Run("Get verification status for list of empty VINs", func() {
@@ -153,17 +177,42 @@ func (s *VehicleControllerTestSuite) TestGetVerificationStatusForVins() {
 			strings.NewReader(""),
 		)
 		response, _ := app.Test(req)
-		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		var statuses StatusForVinsResponse
+		assert.NilError(t, json.NewDecoder(response.Body).Decode(&statuses))
+		// every empty VIN is rejected
+		assert.Equal(t, 2, len(statuses.Statuses))
+		for _, status := range statuses.Statuses {
+			assert.Equal(t, vinStatusRejected, status.Status)
+			assert.Equal(t, ErrCodeInvalidVin, status.Reason)
+		}
 	})
 
 	s.Run("Get verification status for list of invalid VINs", func() {
 		req, _ := http.NewRequest(
 			"GET",
-			"/vehicle/verify?vins=123,A345",
+			"/vehicle/verify?vins=123,A345,ABCDEFG1234567811",
 			strings.NewReader(""),
 		)
 		response, _ := app.Test(req)
-		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		body, _ := io.ReadAll(response.Body)
+
+		// every invalid VIN is rejected on its own, the valid ones are still answered
+		expected := StatusForVinsResponse{
+			Statuses: []VinStatus{
+				{Vin: "ABCDEFG1234567811", Status: "Unknown", Details: "Unknown"},
+				{Vin: "123", Status: vinStatusRejected, Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+				{Vin: "A345", Status: vinStatusRejected, Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+			},
+		}
+
+		expectedJSON, err := json.Marshal(expected)
+		assert.NilError(t, err)
+
+		assert.Equal(t, string(body), string(expectedJSON))
 	})
 
 	s.Run("Get verification status for a single valid, but unknown VIN", func() {
@@ -225,19 +274,35 @@ func (s *VehicleControllerTestSuite) TestGetVerificationStatusForVins() {
 		assert.Equal(t, string(body), string(expectedJSON))
 	})
 
-	s.Run("Fails for duplicates", func() {
+	s.Run("Rejects duplicates", func() {
 		req, _ := http.NewRequest(
 			"GET",
-			"/vehicle/verify?vins=ABCDEFG1234567811,ABCDEFG1234567811",
+			"/vehicle/verify?vins=ABCDEFG1234567811,ABCDEFG1234567812,ABCDEFG1234567811",
 			strings.NewReader(""),
 		)
 		response, _ := app.Test(req)
-		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		body, _ := io.ReadAll(response.Body)
+
+		expected := StatusForVinsResponse{
+			Statuses: []VinStatus{
+				{Vin: "ABCDEFG1234567811", Status: "Unknown", Details: "Unknown"},
+				{Vin: "ABCDEFG1234567812", Status: "Unknown", Details: "Unknown"},
+				{Vin: "ABCDEFG1234567811", Status: vinStatusRejected, Details: "Duplicated VIN", Reason: ErrCodeDuplicateVin},
+			},
+		}
+
+		expectedJSON, err := json.Marshal(expected)
+		assert.NilError(t, err)
+
+		assert.Equal(t, string(body), string(expectedJSON))
 	})
 
 	dbVin := dbmodels.Vin{
 		Vin:              "ABCDEFG1234567812",
 		OnboardingStatus: onboarding.OnboardingStatusMintSuccess,
+		OwnerAddress:     null.StringFrom(testWalletAddress.Hex()),
 	}
 
 	s.Run("Get verification status for a list of valid VINs, some known, some not", func() {
@@ -310,15 +375,128 @@ func (s *VehicleControllerTestSuite) TestGetVerificationStatusForVins() {
 	})
 }
 
+func (s *VehicleControllerTestSuite) TestGetVerificationStatusForVins_NotOwned() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, mockDeps.identity, s.vs, s.river, nil, nil, nil)
+	app := fiber.New(fiber.Config{
+		EnableSplittingOnParsers: true,
+	})
+	app.Get("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.GetVerificationStatusForVins)
+
+	s.Run("Fails for VIN owned by another wallet", func() {
+		dbVin := dbmodels.Vin{
+			Vin:              "ABCDEFG1234567812",
+			OnboardingStatus: onboarding.OnboardingStatusVendorValidationSuccess,
+			OwnerAddress:     null.StringFrom("0x0000000000000000000000000000000000000001"),
+		}
+		require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+		req, _ := http.NewRequest(
+			"GET",
+			"/vehicle/verify?vins=ABCDEFG1234567812",
+			strings.NewReader(""),
+		)
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusForbidden, response.StatusCode)
+
+		_, err := dbVin.Delete(s.ctx, s.pdb.DBS().Writer)
+		assert.NilError(t, err)
+	})
+
+	s.Run("Fails for VIN without owner", func() {
+		dbVin := dbmodels.Vin{
+			Vin:              "ABCDEFG1234567812",
+			OnboardingStatus: onboarding.OnboardingStatusVendorValidationSuccess,
+		}
+		require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+		req, _ := http.NewRequest(
+			"GET",
+			"/vehicle/verify?vins=ABCDEFG1234567812",
+			strings.NewReader(""),
+		)
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusForbidden, response.StatusCode)
+
+		_, err := dbVin.Delete(s.ctx, s.pdb.DBS().Writer)
+		assert.NilError(t, err)
+	})
+
+	s.Run("Succeeds for on-chain owner", func() {
+		identity := mocks.NewIdentityAPIMock([]models.Vehicle{{TokenID: 42, Owner: testWalletAddress.Hex()}}, []models.DeviceDefinition{})
+		c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, identity, s.vs, s.river, nil, nil, nil)
+		app := fiber.New(fiber.Config{
+			EnableSplittingOnParsers: true,
+		})
+		app.Get("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.GetVerificationStatusForVins)
+
+		dbVin := dbmodels.Vin{
+			Vin:              "ABCDEFG1234567812",
+			OnboardingStatus: onboarding.OnboardingStatusMintSuccess,
+			VehicleTokenID:   null.Int64From(42),
+			OwnerAddress:     null.StringFrom("0x0000000000000000000000000000000000000001"),
+		}
+		require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+		req, _ := http.NewRequest(
+			"GET",
+			"/vehicle/verify?vins=ABCDEFG1234567812",
+			strings.NewReader(""),
+		)
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		// the stored owner follows the on-chain one
+		require.NoError(t, dbVin.Reload(s.ctx, s.pdb.DBS().Reader))
+		assert.Equal(t, testWalletAddress.Hex(), dbVin.OwnerAddress.String)
+
+		_, err := dbVin.Delete(s.ctx, s.pdb.DBS().Writer)
+		assert.NilError(t, err)
+	})
+
+	s.Run("Fails for minted VIN transferred to another wallet", func() {
+		identity := mocks.NewIdentityAPIMock([]models.Vehicle{{TokenID: 42, Owner: "0x0000000000000000000000000000000000000001"}}, []models.DeviceDefinition{})
+		c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, identity, s.vs, s.river, nil, nil, nil)
+		app := fiber.New(fiber.Config{
+			EnableSplittingOnParsers: true,
+		})
+		app.Get("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.GetVerificationStatusForVins)
+
+		dbVin := dbmodels.Vin{
+			Vin:              "ABCDEFG1234567812",
+			OnboardingStatus: onboarding.OnboardingStatusMintSuccess,
+			VehicleTokenID:   null.Int64From(42),
+			OwnerAddress:     null.StringFrom(testWalletAddress.Hex()),
+		}
+		require.NoError(t, dbVin.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+		req, _ := http.NewRequest(
+			"GET",
+			"/vehicle/verify?vins=ABCDEFG1234567812",
+			strings.NewReader(""),
+		)
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusForbidden, response.StatusCode)
+
+		require.NoError(t, dbVin.Reload(s.ctx, s.pdb.DBS().Reader))
+		assert.Equal(t, "0x0000000000000000000000000000000000000001", dbVin.OwnerAddress.String)
+
+		_, err := dbVin.Delete(s.ctx, s.pdb.DBS().Writer)
+		assert.NilError(t, err)
+	})
+}
+
 func (s *VehicleControllerTestSuite) TestSubmitVerificationForVins_InvalidVins() {
 	t := s.T()
 	mockDeps := createMockDependencies(t)
 
-	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, mockDeps.identity, s.vs, s.river, nil, nil)
+	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, mockDeps.identity, s.vs, s.river, nil, nil, nil)
 	app := fiber.New(fiber.Config{
 		EnableSplittingOnParsers: true,
 	})
-	app.Post("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", nil), c.SubmitVerificationForVins)
+	app.Post("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.SubmitVerificationForVins)
 
 	s.Run("Submit empty VIN list", func() {
 		payload := SubmitVinVerificationParams{
@@ -366,7 +544,22 @@ func (s *VehicleControllerTestSuite) TestSubmitVerificationForVins_InvalidVins()
 			string(payloadJSON),
 		)
 		response, _ := app.Test(req)
-		assert.Equal(t, fiber.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		body, _ := io.ReadAll(response.Body)
+
+		expected := StatusForVinsResponse{
+			Statuses: []VinStatus{
+				{Vin: "", Status: "Rejected", Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+				{Vin: "", Status: "Rejected", Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+				{Vin: "", Status: "Rejected", Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+			},
+		}
+
+		expectedJSON, err := json.Marshal(expected)
+		assert.NilError(t, err)
+
+		assert.Equal(t, string(body), string(expectedJSON))
 	})
 
 	s.Run("Submit list with invalid VINs", func() {
@@ -381,6 +574,90 @@ func (s *VehicleControllerTestSuite) TestSubmitVerificationForVins_InvalidVins()
 		payloadJSON, err := json.Marshal(payload)
 		assert.NilError(t, err)
 
+		req := test.BuildRequest(
+			"POST",
+			"/vehicle/verify",
+			string(payloadJSON),
+		)
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		body, _ := io.ReadAll(response.Body)
+
+		expected := StatusForVinsResponse{
+			Statuses: []VinStatus{
+				{Vin: "123", Status: "Rejected", Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+				{Vin: "ABC", Status: "Rejected", Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+				{Vin: "FOOBAR321", Status: "Rejected", Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+			},
+		}
+
+		expectedJSON, err := json.Marshal(expected)
+		assert.NilError(t, err)
+
+		assert.Equal(t, string(body), string(expectedJSON))
+	})
+}
+
+func (s *VehicleControllerTestSuite) TestSubmitVerificationForVins_PartialSuccess() {
+	t := s.T()
+	mockDeps := createMockDependencies(t)
+
+	c := NewVehiclesController(&config.Settings{Port: "3000", MaxBatchSize: 3}, &mockDeps.logger, mockDeps.identity, s.vs, s.river, nil, nil, nil)
+	app := fiber.New(fiber.Config{
+		EnableSplittingOnParsers: true,
+	})
+	app.Post("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.SubmitVerificationForVins)
+
+	s.Run("Submit valid, invalid and duplicated VINs", func() {
+		payload := SubmitVinVerificationParams{
+			Vins: []VinWithCountryCode{
+				{Vin: "ABCDEFG1234567821", CountryCode: "USA"},
+				{Vin: "FOOBAR321", CountryCode: "USA"},
+				{Vin: "ABCDEFG1234567821", CountryCode: "USA"},
+			},
+		}
+
+		payloadJSON, err := json.Marshal(payload)
+		assert.NilError(t, err)
+
+		req := test.BuildRequest(
+			"POST",
+			"/vehicle/verify",
+			string(payloadJSON),
+		)
+		response, _ := app.Test(req)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+
+		body, _ := io.ReadAll(response.Body)
+
+		expected := StatusForVinsResponse{
+			Statuses: []VinStatus{
+				{Vin: "ABCDEFG1234567821", Status: "Pending", Details: "VerificationSubmitPending"},
+				{Vin: "FOOBAR321", Status: "Rejected", Details: "Invalid VIN", Reason: ErrCodeInvalidVin},
+				{Vin: "ABCDEFG1234567821", Status: "Rejected", Details: "Duplicated VIN", Reason: ErrCodeDuplicateVin},
+			},
+		}
+
+		expectedJSON, err := json.Marshal(expected)
+		assert.NilError(t, err)
+
+		assert.Equal(t, string(body), string(expectedJSON))
+	})
+
+	s.Run("Submit more VINs than allowed", func() {
+		payload := SubmitVinVerificationParams{
+			Vins: []VinWithCountryCode{
+				{Vin: "ABCDEFG1234567822", CountryCode: "USA"},
+				{Vin: "ABCDEFG1234567823", CountryCode: "USA"},
+				{Vin: "ABCDEFG1234567824", CountryCode: "USA"},
+				{Vin: "ABCDEFG1234567825", CountryCode: "USA"},
+			},
+		}
+
+		payloadJSON, err := json.Marshal(payload)
+		assert.NilError(t, err)
+
 		req := test.BuildRequest(
 			"POST",
 			"/vehicle/verify",
@@ -395,11 +672,11 @@ func (s *VehicleControllerTestSuite) TestSubmitVerificationForVins_ValidVins() {
 	t := s.T()
 	mockDeps := createMockDependencies(t)
 
-	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, mockDeps.identity, s.vs, s.river, nil, nil)
+	c := NewVehiclesController(&config.Settings{Port: "3000"}, &mockDeps.logger, mockDeps.identity, s.vs, s.river, nil, nil, nil)
 	app := fiber.New(fiber.Config{
 		EnableSplittingOnParsers: true,
 	})
-	app.Post("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", nil), c.SubmitVerificationForVins)
+	app.Post("/vehicle/verify", test.AuthInjectorTestHandler("testUserID", &testWalletAddress), c.SubmitVerificationForVins)
 
 	s.Run("Get verification status for list of valid, unknown VINs", func() {
 		payload := SubmitVinVerificationParams{
@@ -0,0 +1,329 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"github.com/riverqueue/river"
+	"github.com/rs/zerolog"
+	"github.com/volatiletech/null/v8"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultWebhookDeliveriesLimit = 50
+	maxWebhookDeliveriesLimit     = 500
+)
+
+type WebhooksController struct {
+	settings    *config.Settings
+	logger      *zerolog.Logger
+	webhooks    *service.Webhooks
+	riverClient *river.Client[pgx.Tx]
+}
+
+func NewWebhooksController(settings *config.Settings, logger *zerolog.Logger, webhooks *service.Webhooks, riverClient *river.Client[pgx.Tx]) *WebhooksController {
+	return &WebhooksController{
+		settings:    settings,
+		logger:      logger,
+		webhooks:    webhooks,
+		riverClient: riverClient,
+	}
+}
+
+// WebhookSubscriptionParams subscription to create
+type WebhookSubscriptionParams struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"` // empty subscribes to all events
+}
+
+// WebhookSubscription is an URL receiving the onboarding events of the VINs submitted through the developer client
+type WebhookSubscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"` // only returned on creation
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookSubscriptionsResponse subscriptions of the developer client
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+// WebhookDelivery is a delivery attempt of an event to a subscription
+type WebhookDelivery struct {
+	ID           string          `json:"id"`
+	Event        string          `json:"event"`
+	Vin          string          `json:"vin"`
+	Status       string          `json:"status"`
+	Attempts     int             `json:"attempts"`
+	ResponseCode null.Int        `json:"responseCode"`
+	LastError    null.String     `json:"lastError"`
+	Payload      json.RawMessage `json:"payload"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	DeliveredAt  null.Time       `json:"deliveredAt"`
+}
+
+// WebhookDeliveriesResponse deliveries of a subscription
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+func toWebhookSubscription(subscription *dbmodels.WebhookSubscription) WebhookSubscription {
+	return WebhookSubscription{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    service.WebhookSubscriptionEvents(subscription),
+		Active:    subscription.Active,
+		CreatedAt: subscription.CreatedAt,
+	}
+}
+
+func toWebhookDelivery(delivery *dbmodels.WebhookDelivery) WebhookDelivery {
+	return WebhookDelivery{
+		ID:           delivery.ID,
+		Event:        delivery.Event,
+		Vin:          delivery.Vin,
+		Status:       delivery.Status,
+		Attempts:     delivery.Attempts,
+		ResponseCode: delivery.ResponseCode,
+		LastError:    delivery.LastError,
+		Payload:      json.RawMessage(delivery.Payload.JSON),
+		CreatedAt:    delivery.CreatedAt,
+		DeliveredAt:  delivery.DeliveredAt,
+	}
+}
+
+// validateWebhookURL requires https, plain http is only accepted outside of production for local testing.
+// The host must resolve to public addresses only, deliveries check them again when connecting.
+func (w *WebhooksController) validateWebhookURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" || parsed.User != nil {
+		return NewAPIError(ErrCodeInvalidRequest, "Invalid webhook URL")
+	}
+
+	if parsed.Scheme != "https" && (w.settings.IsProduction() || parsed.Scheme != "http") {
+		return NewAPIError(ErrCodeInvalidRequest, "Webhook URL must use https")
+	}
+
+	if err := service.ValidateWebhookHost(ctx, parsed.Hostname()); err != nil {
+		if errors.Is(err, service.ErrWebhookAddressNotAllowed) {
+			return NewAPIError(ErrCodeInvalidRequest, "Webhook URL must point to a public address")
+		}
+		return NewAPIError(ErrCodeInvalidRequest, "Webhook URL host can't be resolved")
+	}
+
+	return nil
+}
+
+// getClientID returns the developer license the subscriptions of the request belong to, see DeveloperLicenseAuth
+func getClientID(c *fiber.Ctx) (string, error) {
+	clientID, ok := clientIDOf(c.UserContext())
+	if !ok {
+		return "", NewAPIError(ErrCodeUnauthorized, "JWT has no client ID")
+	}
+
+	return clientID, nil
+}
+
+func (w *WebhooksController) getSubscription(c *fiber.Ctx, clientID string) (*dbmodels.WebhookSubscription, error) {
+	subscription, err := w.webhooks.GetSubscription(c.Context(), clientID, c.Params("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			return nil, NewAPIError(ErrCodeNotFound, "Could not find webhook subscription")
+		}
+
+		return nil, NewAPIError(ErrCodeInternal, "Failed to load webhook subscription from Database")
+	}
+
+	return subscription, nil
+}
+
+// GetSubscriptions
+// @Summary Get the webhook subscriptions of the developer license
+// @Description Only available to JWTs issued to a developer license
+// @Produce json
+// @Success 200 {object} WebhookSubscriptionsResponse
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/webhooks [get]
+func (w *WebhooksController) GetSubscriptions(c *fiber.Ctx) error {
+	clientID, err := getClientID(c)
+	if err != nil {
+		return err
+	}
+
+	subscriptions, err := w.webhooks.GetSubscriptions(c.Context(), clientID)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load webhook subscriptions from Database")
+	}
+
+	response := make([]WebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, toWebhookSubscription(subscription))
+	}
+
+	return c.JSON(WebhookSubscriptionsResponse{
+		Subscriptions: response,
+	})
+}
+
+// CreateSubscription
+// @Summary Subscribe to onboarding lifecycle events of the VINs submitted through the developer license
+// @Description Only available to JWTs issued to a developer license, subscriptions belong to the license. A VIN's events go to the license it was last verified or registered through, until the vehicle is transferred. Events are posted as JSON, signed with HMAC-SHA256 of "timestamp.body" in the X-Webhook-Signature header (t=timestamp,v1=signature). The secret is only returned once.
+// @Accept json
+// @Produce json
+// @Param params body WebhookSubscriptionParams true "webhook URL and event filter"
+// @Success 201 {object} WebhookSubscription
+// @Failure 400 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/webhooks [post]
+func (w *WebhooksController) CreateSubscription(c *fiber.Ctx) error {
+	clientID, err := getClientID(c)
+	if err != nil {
+		return err
+	}
+
+	params := new(WebhookSubscriptionParams)
+	if err := c.BodyParser(params); err != nil {
+		return NewAPIError(ErrCodeInvalidRequest, "Invalid request body")
+	}
+
+	if err := w.validateWebhookURL(c.UserContext(), params.URL); err != nil {
+		return err
+	}
+
+	if err := service.ValidateWebhookEvents(params.Events); err != nil {
+		return NewAPIError(ErrCodeInvalidRequest, err.Error())
+	}
+
+	subscription, err := w.webhooks.CreateSubscription(c.Context(), clientID, params.URL, params.Events)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to store webhook subscription")
+	}
+
+	response := toWebhookSubscription(subscription)
+	response.Secret = subscription.Secret
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// DeleteSubscription
+// @Summary Delete a webhook subscription
+// @Param id path string true "subscription ID"
+// @Success 204
+// @Failure 404 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/webhooks/{id} [delete]
+func (w *WebhooksController) DeleteSubscription(c *fiber.Ctx) error {
+	clientID, err := getClientID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := w.webhooks.DeleteSubscription(c.Context(), clientID, c.Params("id")); err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			return NewAPIError(ErrCodeNotFound, "Could not find webhook subscription")
+		}
+
+		return NewAPIError(ErrCodeInternal, "Failed to delete webhook subscription")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetDeliveries
+// @Summary Get the delivery log of a webhook subscription
+// @Produce json
+// @Param id path string true "subscription ID"
+// @Param limit query int false "number of latest deliveries"
+// @Success 200 {object} WebhookDeliveriesResponse
+// @Failure 404 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/webhooks/{id}/deliveries [get]
+func (w *WebhooksController) GetDeliveries(c *fiber.Ctx) error {
+	clientID, err := getClientID(c)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := w.getSubscription(c, clientID)
+	if err != nil {
+		return err
+	}
+
+	limit := c.QueryInt("limit", defaultWebhookDeliveriesLimit)
+	if limit <= 0 || limit > maxWebhookDeliveriesLimit {
+		limit = defaultWebhookDeliveriesLimit
+	}
+
+	deliveries, err := w.webhooks.GetDeliveries(c.Context(), subscription.ID, limit)
+	if err != nil {
+		return NewAPIError(ErrCodeInternal, "Failed to load webhook deliveries from Database")
+	}
+
+	response := make([]WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, toWebhookDelivery(delivery))
+	}
+
+	return c.JSON(WebhookDeliveriesResponse{
+		Deliveries: response,
+	})
+}
+
+// Redeliver
+// @Summary Send a webhook delivery again
+// @Description Queues the stored payload of the delivery again, with a fresh signature
+// @Produce json
+// @Param id path string true "subscription ID"
+// @Param deliveryId path string true "delivery ID"
+// @Success 202 {object} WebhookDelivery
+// @Failure 404 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 500 {object} APIError
+// @Security BearerAuth
+// @Router /v1/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (w *WebhooksController) Redeliver(c *fiber.Ctx) error {
+	clientID, err := getClientID(c)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := w.getSubscription(c, clientID)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := w.webhooks.GetDelivery(c.Context(), subscription.ID, c.Params("deliveryId"))
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			return NewAPIError(ErrCodeNotFound, "Could not find webhook delivery")
+		}
+
+		return NewAPIError(ErrCodeInternal, "Failed to load webhook delivery from Database")
+	}
+
+	if err := w.webhooks.ResetDelivery(c.UserContext(), delivery, onboarding.QueueWebhookDeliveries(w.riverClient)); err != nil {
+		w.logger.Error().Err(err).Str("deliveryId", delivery.ID).Msg("Failed to submit webhook redelivery")
+		return NewAPIError(ErrCodeInternal, "Failed to submit webhook redelivery")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(toWebhookDelivery(delivery))
+}
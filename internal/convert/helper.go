@@ -1,7 +1,7 @@
 package convert
 
-const kilometersPerMile = 1.609344
+const KilometersPerMile = 1.609344
 
 func milesToKilometers(miles float64) float64 {
-	return kilometersPerMile * miles
+	return KilometersPerMile * miles
 }
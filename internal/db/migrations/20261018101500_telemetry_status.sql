@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.telemetry_status
+(
+    vin               varchar(17) not null
+        constraint telemetry_status_pk
+            primary key,
+    last_received_at  timestamptz,
+    last_forwarded_at timestamptz,
+    last_error        varchar(512),
+    last_error_at     timestamptz,
+    updated_at        timestamptz not null default now()
+);
+
+create index telemetry_status_last_received_at_idx
+    on oracle_example.telemetry_status (last_received_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table oracle_example.telemetry_status;
+
+-- +goose StatementEnd
@@ -0,0 +1,69 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.trips
+(
+    id              char(27)    not null
+        constraint trips_pk
+            primary key,
+    vin             varchar(17) not null,
+    start_time      timestamptz not null,
+    end_time        timestamptz,
+    start_latitude  double precision,
+    start_longitude double precision,
+    end_latitude    double precision,
+    end_longitude   double precision,
+    start_odometer  double precision,
+    end_odometer    double precision,
+    distance_km     double precision,
+    -- last time the vehicle moved during the open trip, so the trip ends there after a restart
+    last_moving_at  timestamptz,
+    -- CloudEvent header of the telemetry the trip started with, its event is sent with it until DIS accepts it
+    event_header    jsonb,
+    event_sent_at   timestamptz,
+    created_at      timestamptz not null default now(),
+    updated_at      timestamptz not null default now()
+);
+
+create index trips_vin_start_time_idx
+    on oracle_example.trips (vin, start_time);
+
+create index trips_event_pending_idx on oracle_example.trips (updated_at)
+    where end_time is not null and event_sent_at is null;
+
+create table oracle_example.charging_sessions
+(
+    id            char(27)    not null
+        constraint charging_sessions_pk
+            primary key,
+    vin           varchar(17) not null,
+    start_time    timestamptz not null,
+    end_time      timestamptz,
+    start_soc     double precision,
+    end_soc       double precision,
+    energy_kwh    double precision,
+    -- CloudEvent header of the telemetry the session started with, see trips
+    event_header  jsonb,
+    event_sent_at timestamptz,
+    created_at    timestamptz not null default now(),
+    updated_at    timestamptz not null default now()
+);
+
+create index charging_sessions_vin_start_time_idx
+    on oracle_example.charging_sessions (vin, start_time);
+
+create index charging_sessions_event_pending_idx on oracle_example.charging_sessions (updated_at)
+    where end_time is not null and event_sent_at is null;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table oracle_example.charging_sessions;
+
+drop table oracle_example.trips;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.odometer_anomalies
+(
+    id             char(27)         not null
+        constraint odometer_anomalies_pk
+            primary key,
+    vin            varchar(17)      not null,
+    anomaly_type   varchar(30)      not null,
+    value          double precision not null,
+    signal_time    timestamptz      not null,
+    previous_value double precision,
+    previous_time  timestamptz,
+    created_at     timestamptz      not null default now()
+);
+
+create index odometer_anomalies_vin_created_at_idx
+    on oracle_example.odometer_anomalies (vin, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table oracle_example.odometer_anomalies;
+
+-- +goose StatementEnd
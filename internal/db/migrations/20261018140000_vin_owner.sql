@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+alter table oracle_example.vins
+    add owner_address varchar(42);
+
+-- owners are compared case-insensitive
+create index vins_owner_address_idx on oracle_example.vins (lower(owner_address));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop index oracle_example.vins_owner_address_idx;
+
+alter table oracle_example.vins
+    drop column owner_address;
+
+-- +goose StatementEnd
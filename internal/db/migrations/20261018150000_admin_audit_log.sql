@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.admin_audit_log
+(
+    id         char(27)                 not null
+        constraint admin_audit_log_pk
+            primary key,
+    actor      varchar(100)             not null,
+    action     varchar(50)              not null,
+    vin        varchar,
+    details    jsonb,
+    created_at timestamp with time zone not null default now()
+);
+
+create index admin_audit_log_vin_created_at_idx on oracle_example.admin_audit_log (vin, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table oracle_example.admin_audit_log;
+
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- connection changes confirmed by the vendor are status changes too
+create or replace function oracle_example.notify_vin_status() returns trigger as
+$$
+begin
+    if tg_op = 'UPDATE'
+        and old.onboarding_status = new.onboarding_status
+        and old.connection_status is not distinct from new.connection_status
+        and old.disconnection_status is not distinct from new.disconnection_status
+        and old.operation_error_type is not distinct from new.operation_error_type
+        and old.operation_error_code is not distinct from new.operation_error_code
+        and old.operation_error_description is not distinct from new.operation_error_description then
+        return new;
+    end if;
+
+    perform pg_notify('vin_status', json_build_object(
+            'vin', new.vin,
+            'ownerAddress', new.owner_address,
+            'onboardingStatus', new.onboarding_status,
+            'connectionStatus', new.connection_status,
+            'disconnectionStatus', new.disconnection_status,
+            'operationErrorType', new.operation_error_type,
+            'operationErrorCode', new.operation_error_code,
+            'operationErrorDescription', new.operation_error_description
+        )::text);
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger vins_notify_status
+    after insert or update of onboarding_status, connection_status, disconnection_status, operation_error_type, operation_error_code, operation_error_description
+    on oracle_example.vins
+    for each row
+execute function oracle_example.notify_vin_status();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop trigger vins_notify_status on oracle_example.vins;
+
+drop function oracle_example.notify_vin_status();
+
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.webhook_subscriptions
+(
+    id            char(27)                 not null
+        constraint webhook_subscriptions_pk
+            primary key,
+    client_id     varchar(100)             not null,
+    url           varchar                  not null,
+    secret        varchar(64)              not null,
+    events        varchar                  not null default '',
+    active        boolean                  not null default true,
+    created_at    timestamp with time zone not null default now(),
+    updated_at    timestamp with time zone not null default now()
+);
+
+create index webhook_subscriptions_client_id_idx on oracle_example.webhook_subscriptions (client_id);
+
+create table oracle_example.webhook_deliveries
+(
+    id              char(27)                 not null
+        constraint webhook_deliveries_pk
+            primary key,
+    subscription_id char(27)                 not null,
+    event           varchar(50)              not null,
+    vin             varchar                  not null,
+    payload         jsonb,
+    status          varchar(20)              not null default 'pending',
+    attempts        integer                  not null default 0,
+    response_code   integer,
+    last_error      varchar(512),
+    created_at      timestamp with time zone not null default now(),
+    updated_at      timestamp with time zone not null default now(),
+    delivered_at    timestamp with time zone
+);
+
+create index webhook_deliveries_subscription_id_created_at_idx on oracle_example.webhook_deliveries (subscription_id, created_at);
+
+-- client ID of the developer the VIN was last submitted through, its subscriptions receive the events of the VIN
+alter table oracle_example.vins
+    add client_id varchar(100);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+alter table oracle_example.vins
+    drop column client_id;
+
+drop table oracle_example.webhook_deliveries;
+drop table oracle_example.webhook_subscriptions;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.idempotency_keys
+(
+    scope                 varchar(110)             not null,
+    key                   varchar(255)             not null,
+    request_hash          char(64)                 not null,
+    response_code         integer,
+    response_content_type varchar(255),
+    response_body         bytea,
+    created_at            timestamp with time zone not null default now(),
+    expires_at            timestamp with time zone not null,
+    -- lease of the request processing the key, keys of requests which died while in progress are taken over once it ends
+    locked_until          timestamp with time zone,
+    constraint idempotency_keys_pk
+        primary key (scope, key)
+);
+
+create index idempotency_keys_expires_at_idx on oracle_example.idempotency_keys (expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table oracle_example.idempotency_keys;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+alter table oracle_example.vins
+    add definition_make varchar(100);
+
+alter table oracle_example.vins
+    add definition_model varchar(100);
+
+alter table oracle_example.vins
+    add definition_year integer;
+
+create index vins_owner_address_vin_idx on oracle_example.vins (owner_address, vin);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop index oracle_example.vins_owner_address_vin_idx;
+
+alter table oracle_example.vins
+    drop column definition_year;
+
+alter table oracle_example.vins
+    drop column definition_model;
+
+alter table oracle_example.vins
+    drop column definition_make;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.telemetry_signals
+(
+    vin        varchar(17)  not null,
+    name       varchar(100) not null,
+    value      jsonb        not null,
+    timestamp  timestamptz  not null,
+    updated_at timestamptz  not null default now(),
+    constraint telemetry_signals_pk
+        primary key (vin, name)
+);
+
+alter table oracle_example.vins
+    add data_services text[];
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+alter table oracle_example.vins
+    drop column data_services;
+
+drop table oracle_example.telemetry_signals;
+
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.import_batches
+(
+    id            char(27)                 not null
+        constraint import_batches_pk
+            primary key,
+    owner_address varchar(42)              not null,
+    row_count     integer                  not null,
+    created_at    timestamp with time zone not null default now()
+);
+
+create index import_batches_owner_address_idx on oracle_example.import_batches (owner_address);
+
+create table oracle_example.import_rows
+(
+    batch_id     char(27)    not null,
+    row_number   integer     not null,
+    vin          varchar     not null,
+    country_code varchar     not null default '',
+    token_id     bigint,
+    outcome      varchar(20) not null,
+    reason       varchar(50),
+    details      varchar(512),
+    constraint import_rows_pk
+        primary key (batch_id, row_number)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table oracle_example.import_rows;
+drop table oracle_example.import_batches;
+
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table oracle_example.vin_status_history
+(
+    id                bigint generated always as identity
+        constraint vin_status_history_pk
+            primary key,
+    vin               varchar                  not null,
+    onboarding_status integer                  not null,
+    changed_at        timestamp with time zone not null default now()
+);
+
+create index vin_status_history_vin_changed_at_idx on oracle_example.vin_status_history (vin, changed_at);
+
+create or replace function oracle_example.record_vin_status() returns trigger as
+$$
+begin
+    if tg_op = 'UPDATE' and old.onboarding_status = new.onboarding_status then
+        return new;
+    end if;
+
+    insert into oracle_example.vin_status_history (vin, onboarding_status)
+    values (new.vin, new.onboarding_status);
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger vins_record_status
+    after insert or update of onboarding_status
+    on oracle_example.vins
+    for each row
+execute function oracle_example.record_vin_status();
+
+-- current status of the existing VINs, their earlier changes are not known
+insert into oracle_example.vin_status_history (vin, onboarding_status)
+select vin, onboarding_status
+from oracle_example.vins;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop trigger vins_record_status on oracle_example.vins;
+
+drop function oracle_example.record_vin_status();
+
+drop table oracle_example.vin_status_history;
+
+-- +goose StatementEnd
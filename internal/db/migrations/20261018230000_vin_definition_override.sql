@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- set when the device definition was picked manually instead of decoded, decoding leaves it alone
+alter table oracle_example.vins
+    add definition_overridden_by varchar(100);
+
+alter table oracle_example.vins
+    add definition_overridden_at timestamp with time zone;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+alter table oracle_example.vins
+    drop column definition_overridden_at;
+
+alter table oracle_example.vins
+    drop column definition_overridden_by;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- VINs submitted before owners were stored, the owner is known from their latest onboarding job
+update oracle_example.vins v
+set owner_address = j.owner
+from (select distinct on (args ->> 'vin') args ->> 'vin' as vin, args ->> 'owner' as owner
+      from river_job
+      where kind = 'onboard'
+        and args ->> 'owner' is not null
+      order by args ->> 'vin', id desc) j
+where v.owner_address is null
+  and v.vin = j.vin;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- address of the synthetic device, derived from wallet_index and stored when minted so it can be searched. Existing
+-- VINs are filled by the oracle on startup, the address can't be derived in SQL
+alter table oracle_example.vins
+    add sd_address varchar(42);
+
+create index vins_sd_address_idx on oracle_example.vins (sd_address);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop index oracle_example.vins_sd_address_idx;
+
+alter table oracle_example.vins
+    drop column sd_address;
+
+-- +goose StatementEnd
@@ -4,7 +4,31 @@
 package models
 
 var TableNames = struct {
-	Vins string
+	AdminAuditLog        string
+	ChargingSessions     string
+	IdempotencyKeys      string
+	ImportBatches        string
+	ImportRows           string
+	OdometerAnomalies    string
+	TelemetrySignals     string
+	TelemetryStatus      string
+	Trips                string
+	VinStatusHistory     string
+	Vins                 string
+	WebhookDeliveries    string
+	WebhookSubscriptions string
 }{
-	Vins: "vins",
+	AdminAuditLog:        "admin_audit_log",
+	ChargingSessions:     "charging_sessions",
+	IdempotencyKeys:      "idempotency_keys",
+	ImportBatches:        "import_batches",
+	ImportRows:           "import_rows",
+	OdometerAnomalies:    "odometer_anomalies",
+	TelemetrySignals:     "telemetry_signals",
+	TelemetryStatus:      "telemetry_status",
+	Trips:                "trips",
+	VinStatusHistory:     "vin_status_history",
+	Vins:                 "vins",
+	WebhookDeliveries:    "webhook_deliveries",
+	WebhookSubscriptions: "webhook_subscriptions",
 }
@@ -19,23 +19,33 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"github.com/volatiletech/strmangle"
 )
 
 // Vin is an object representing the database table.
 type Vin struct {
-	Vin                       string      `boil:"vin" json:"vin" toml:"vin" yaml:"vin"`
-	VehicleTokenID            null.Int64  `boil:"vehicle_token_id" json:"vehicle_token_id,omitempty" toml:"vehicle_token_id" yaml:"vehicle_token_id,omitempty"`
-	SyntheticTokenID          null.Int64  `boil:"synthetic_token_id" json:"synthetic_token_id,omitempty" toml:"synthetic_token_id" yaml:"synthetic_token_id,omitempty"`
-	ExternalID                null.String `boil:"external_id" json:"external_id,omitempty" toml:"external_id" yaml:"external_id,omitempty"`
-	ConnectionStatus          null.String `boil:"connection_status" json:"connection_status,omitempty" toml:"connection_status" yaml:"connection_status,omitempty"`
-	OnboardingStatus          int         `boil:"onboarding_status" json:"onboarding_status" toml:"onboarding_status" yaml:"onboarding_status"`
-	DeviceDefinitionID        null.String `boil:"device_definition_id" json:"device_definition_id,omitempty" toml:"device_definition_id" yaml:"device_definition_id,omitempty"`
-	WalletIndex               null.Int64  `boil:"wallet_index" json:"wallet_index,omitempty" toml:"wallet_index" yaml:"wallet_index,omitempty"`
-	DisconnectionStatus       null.String `boil:"disconnection_status" json:"disconnection_status,omitempty" toml:"disconnection_status" yaml:"disconnection_status,omitempty"`
-	OperationErrorCode        null.String `boil:"operation_error_code" json:"operation_error_code,omitempty" toml:"operation_error_code" yaml:"operation_error_code,omitempty"`
-	OperationErrorType        null.String `boil:"operation_error_type" json:"operation_error_type,omitempty" toml:"operation_error_type" yaml:"operation_error_type,omitempty"`
-	OperationErrorDescription null.String `boil:"operation_error_description" json:"operation_error_description,omitempty" toml:"operation_error_description" yaml:"operation_error_description,omitempty"`
+	Vin                       string            `boil:"vin" json:"vin" toml:"vin" yaml:"vin"`
+	VehicleTokenID            null.Int64        `boil:"vehicle_token_id" json:"vehicle_token_id,omitempty" toml:"vehicle_token_id" yaml:"vehicle_token_id,omitempty"`
+	SyntheticTokenID          null.Int64        `boil:"synthetic_token_id" json:"synthetic_token_id,omitempty" toml:"synthetic_token_id" yaml:"synthetic_token_id,omitempty"`
+	ExternalID                null.String       `boil:"external_id" json:"external_id,omitempty" toml:"external_id" yaml:"external_id,omitempty"`
+	ConnectionStatus          null.String       `boil:"connection_status" json:"connection_status,omitempty" toml:"connection_status" yaml:"connection_status,omitempty"`
+	OnboardingStatus          int               `boil:"onboarding_status" json:"onboarding_status" toml:"onboarding_status" yaml:"onboarding_status"`
+	DeviceDefinitionID        null.String       `boil:"device_definition_id" json:"device_definition_id,omitempty" toml:"device_definition_id" yaml:"device_definition_id,omitempty"`
+	WalletIndex               null.Int64        `boil:"wallet_index" json:"wallet_index,omitempty" toml:"wallet_index" yaml:"wallet_index,omitempty"`
+	DisconnectionStatus       null.String       `boil:"disconnection_status" json:"disconnection_status,omitempty" toml:"disconnection_status" yaml:"disconnection_status,omitempty"`
+	OperationErrorCode        null.String       `boil:"operation_error_code" json:"operation_error_code,omitempty" toml:"operation_error_code" yaml:"operation_error_code,omitempty"`
+	OperationErrorType        null.String       `boil:"operation_error_type" json:"operation_error_type,omitempty" toml:"operation_error_type" yaml:"operation_error_type,omitempty"`
+	OperationErrorDescription null.String       `boil:"operation_error_description" json:"operation_error_description,omitempty" toml:"operation_error_description" yaml:"operation_error_description,omitempty"`
+	OwnerAddress              null.String       `boil:"owner_address" json:"owner_address,omitempty" toml:"owner_address" yaml:"owner_address,omitempty"`
+	DefinitionMake            null.String       `boil:"definition_make" json:"definition_make,omitempty" toml:"definition_make" yaml:"definition_make,omitempty"`
+	DefinitionModel           null.String       `boil:"definition_model" json:"definition_model,omitempty" toml:"definition_model" yaml:"definition_model,omitempty"`
+	DefinitionYear            null.Int          `boil:"definition_year" json:"definition_year,omitempty" toml:"definition_year" yaml:"definition_year,omitempty"`
+	DataServices              types.StringArray `boil:"data_services" json:"data_services,omitempty" toml:"data_services" yaml:"data_services,omitempty"`
+	DefinitionOverriddenBy    null.String       `boil:"definition_overridden_by" json:"definition_overridden_by,omitempty" toml:"definition_overridden_by" yaml:"definition_overridden_by,omitempty"`
+	DefinitionOverriddenAt    null.Time         `boil:"definition_overridden_at" json:"definition_overridden_at,omitempty" toml:"definition_overridden_at" yaml:"definition_overridden_at,omitempty"`
+	SDAddress                 null.String       `boil:"sd_address" json:"sd_address,omitempty" toml:"sd_address" yaml:"sd_address,omitempty"`
+	ClientID                  null.String       `boil:"client_id" json:"client_id,omitempty" toml:"client_id" yaml:"client_id,omitempty"`
 
 	R *vinR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L vinL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -54,6 +64,15 @@ var VinColumns = struct {
 	OperationErrorCode        string
 	OperationErrorType        string
 	OperationErrorDescription string
+	OwnerAddress              string
+	DefinitionMake            string
+	DefinitionModel           string
+	DefinitionYear            string
+	DataServices              string
+	DefinitionOverriddenBy    string
+	DefinitionOverriddenAt    string
+	SDAddress                 string
+	ClientID                  string
 }{
 	Vin:                       "vin",
 	VehicleTokenID:            "vehicle_token_id",
@@ -67,6 +86,15 @@ var VinColumns = struct {
 	OperationErrorCode:        "operation_error_code",
 	OperationErrorType:        "operation_error_type",
 	OperationErrorDescription: "operation_error_description",
+	OwnerAddress:              "owner_address",
+	DefinitionMake:            "definition_make",
+	DefinitionModel:           "definition_model",
+	DefinitionYear:            "definition_year",
+	DataServices:              "data_services",
+	DefinitionOverriddenBy:    "definition_overridden_by",
+	DefinitionOverriddenAt:    "definition_overridden_at",
+	SDAddress:                 "sd_address",
+	ClientID:                  "client_id",
 }
 
 var VinTableColumns = struct {
@@ -82,6 +110,15 @@ var VinTableColumns = struct {
 	OperationErrorCode        string
 	OperationErrorType        string
 	OperationErrorDescription string
+	OwnerAddress              string
+	DefinitionMake            string
+	DefinitionModel           string
+	DefinitionYear            string
+	DataServices              string
+	DefinitionOverriddenBy    string
+	DefinitionOverriddenAt    string
+	SDAddress                 string
+	ClientID                  string
 }{
 	Vin:                       "vins.vin",
 	VehicleTokenID:            "vins.vehicle_token_id",
@@ -95,146 +132,43 @@ var VinTableColumns = struct {
 	OperationErrorCode:        "vins.operation_error_code",
 	OperationErrorType:        "vins.operation_error_type",
 	OperationErrorDescription: "vins.operation_error_description",
+	OwnerAddress:              "vins.owner_address",
+	DefinitionMake:            "vins.definition_make",
+	DefinitionModel:           "vins.definition_model",
+	DefinitionYear:            "vins.definition_year",
+	DataServices:              "vins.data_services",
+	DefinitionOverriddenBy:    "vins.definition_overridden_by",
+	DefinitionOverriddenAt:    "vins.definition_overridden_at",
+	SDAddress:                 "vins.sd_address",
+	ClientID:                  "vins.client_id",
 }
 
 // Generated where
 
-type whereHelperstring struct{ field string }
-
-func (w whereHelperstring) EQ(x string) qm.QueryMod     { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperstring) NEQ(x string) qm.QueryMod    { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperstring) LT(x string) qm.QueryMod     { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperstring) LTE(x string) qm.QueryMod    { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperstring) GT(x string) qm.QueryMod     { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperstring) GTE(x string) qm.QueryMod    { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperstring) LIKE(x string) qm.QueryMod   { return qm.Where(w.field+" LIKE ?", x) }
-func (w whereHelperstring) NLIKE(x string) qm.QueryMod  { return qm.Where(w.field+" NOT LIKE ?", x) }
-func (w whereHelperstring) ILIKE(x string) qm.QueryMod  { return qm.Where(w.field+" ILIKE ?", x) }
-func (w whereHelperstring) NILIKE(x string) qm.QueryMod { return qm.Where(w.field+" NOT ILIKE ?", x) }
-func (w whereHelperstring) IN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperstring) NIN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
-type whereHelpernull_Int64 struct{ field string }
-
-func (w whereHelpernull_Int64) EQ(x null.Int64) qm.QueryMod {
-	return qmhelper.WhereNullEQ(w.field, false, x)
-}
-func (w whereHelpernull_Int64) NEQ(x null.Int64) qm.QueryMod {
-	return qmhelper.WhereNullEQ(w.field, true, x)
-}
-func (w whereHelpernull_Int64) LT(x null.Int64) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LT, x)
-}
-func (w whereHelpernull_Int64) LTE(x null.Int64) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LTE, x)
-}
-func (w whereHelpernull_Int64) GT(x null.Int64) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GT, x)
-}
-func (w whereHelpernull_Int64) GTE(x null.Int64) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GTE, x)
-}
-func (w whereHelpernull_Int64) IN(slice []int64) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelpernull_Int64) NIN(slice []int64) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
-func (w whereHelpernull_Int64) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
-func (w whereHelpernull_Int64) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
-
-type whereHelpernull_String struct{ field string }
+type whereHelpertypes_StringArray struct{ field string }
 
-func (w whereHelpernull_String) EQ(x null.String) qm.QueryMod {
+func (w whereHelpertypes_StringArray) EQ(x types.StringArray) qm.QueryMod {
 	return qmhelper.WhereNullEQ(w.field, false, x)
 }
-func (w whereHelpernull_String) NEQ(x null.String) qm.QueryMod {
+func (w whereHelpertypes_StringArray) NEQ(x types.StringArray) qm.QueryMod {
 	return qmhelper.WhereNullEQ(w.field, true, x)
 }
-func (w whereHelpernull_String) LT(x null.String) qm.QueryMod {
+func (w whereHelpertypes_StringArray) LT(x types.StringArray) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.LT, x)
 }
-func (w whereHelpernull_String) LTE(x null.String) qm.QueryMod {
+func (w whereHelpertypes_StringArray) LTE(x types.StringArray) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.LTE, x)
 }
-func (w whereHelpernull_String) GT(x null.String) qm.QueryMod {
+func (w whereHelpertypes_StringArray) GT(x types.StringArray) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.GT, x)
 }
-func (w whereHelpernull_String) GTE(x null.String) qm.QueryMod {
+func (w whereHelpertypes_StringArray) GTE(x types.StringArray) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.GTE, x)
 }
-func (w whereHelpernull_String) LIKE(x null.String) qm.QueryMod {
-	return qm.Where(w.field+" LIKE ?", x)
-}
-func (w whereHelpernull_String) NLIKE(x null.String) qm.QueryMod {
-	return qm.Where(w.field+" NOT LIKE ?", x)
-}
-func (w whereHelpernull_String) ILIKE(x null.String) qm.QueryMod {
-	return qm.Where(w.field+" ILIKE ?", x)
-}
-func (w whereHelpernull_String) NILIKE(x null.String) qm.QueryMod {
-	return qm.Where(w.field+" NOT ILIKE ?", x)
-}
-func (w whereHelpernull_String) IN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelpernull_String) NIN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
-func (w whereHelpernull_String) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
-func (w whereHelpernull_String) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
 
-type whereHelperint struct{ field string }
-
-func (w whereHelperint) EQ(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperint) NEQ(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperint) LT(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperint) LTE(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperint) GT(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperint) GTE(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperint) IN(slice []int) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperint) NIN(slice []int) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+func (w whereHelpertypes_StringArray) IsNull() qm.QueryMod { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpertypes_StringArray) IsNotNull() qm.QueryMod {
+	return qmhelper.WhereIsNotNull(w.field)
 }
 
 var VinWhere = struct {
@@ -250,6 +184,15 @@ var VinWhere = struct {
 	OperationErrorCode        whereHelpernull_String
 	OperationErrorType        whereHelpernull_String
 	OperationErrorDescription whereHelpernull_String
+	OwnerAddress              whereHelpernull_String
+	DefinitionMake            whereHelpernull_String
+	DefinitionModel           whereHelpernull_String
+	DefinitionYear            whereHelpernull_Int
+	DataServices              whereHelpertypes_StringArray
+	DefinitionOverriddenBy    whereHelpernull_String
+	DefinitionOverriddenAt    whereHelpernull_Time
+	SDAddress                 whereHelpernull_String
+	ClientID                  whereHelpernull_String
 }{
 	Vin:                       whereHelperstring{field: "\"oracle_example\".\"vins\".\"vin\""},
 	VehicleTokenID:            whereHelpernull_Int64{field: "\"oracle_example\".\"vins\".\"vehicle_token_id\""},
@@ -263,6 +206,15 @@ var VinWhere = struct {
 	OperationErrorCode:        whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"operation_error_code\""},
 	OperationErrorType:        whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"operation_error_type\""},
 	OperationErrorDescription: whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"operation_error_description\""},
+	OwnerAddress:              whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"owner_address\""},
+	DefinitionMake:            whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"definition_make\""},
+	DefinitionModel:           whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"definition_model\""},
+	DefinitionYear:            whereHelpernull_Int{field: "\"oracle_example\".\"vins\".\"definition_year\""},
+	DataServices:              whereHelpertypes_StringArray{field: "\"oracle_example\".\"vins\".\"data_services\""},
+	DefinitionOverriddenBy:    whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"definition_overridden_by\""},
+	DefinitionOverriddenAt:    whereHelpernull_Time{field: "\"oracle_example\".\"vins\".\"definition_overridden_at\""},
+	SDAddress:                 whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"sd_address\""},
+	ClientID:                  whereHelpernull_String{field: "\"oracle_example\".\"vins\".\"client_id\""},
 }
 
 // VinRels is where relationship names are stored.
@@ -282,9 +234,9 @@ func (*vinR) NewStruct() *vinR {
 type vinL struct{}
 
 var (
-	vinAllColumns            = []string{"vin", "vehicle_token_id", "synthetic_token_id", "external_id", "connection_status", "onboarding_status", "device_definition_id", "wallet_index", "disconnection_status", "operation_error_code", "operation_error_type", "operation_error_description"}
+	vinAllColumns            = []string{"vin", "vehicle_token_id", "synthetic_token_id", "external_id", "connection_status", "onboarding_status", "device_definition_id", "wallet_index", "disconnection_status", "operation_error_code", "operation_error_type", "operation_error_description", "owner_address", "definition_make", "definition_model", "definition_year", "data_services", "definition_overridden_by", "definition_overridden_at", "sd_address", "client_id"}
 	vinColumnsWithoutDefault = []string{"vin"}
-	vinColumnsWithDefault    = []string{"vehicle_token_id", "synthetic_token_id", "external_id", "connection_status", "onboarding_status", "device_definition_id", "wallet_index", "disconnection_status", "operation_error_code", "operation_error_type", "operation_error_description"}
+	vinColumnsWithDefault    = []string{"vehicle_token_id", "synthetic_token_id", "external_id", "connection_status", "onboarding_status", "device_definition_id", "wallet_index", "disconnection_status", "operation_error_code", "operation_error_type", "operation_error_description", "owner_address", "definition_make", "definition_model", "definition_year", "data_services", "definition_overridden_by", "definition_overridden_at", "sd_address", "client_id"}
 	vinPrimaryKeyColumns     = []string{"vin"}
 	vinGeneratedColumns      = []string{}
 )
@@ -0,0 +1,32 @@
+// Package docs serves the OpenAPI document of the HTTP API, generated from the annotations of the handlers.
+package docs
+
+import (
+	_ "embed"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run ./gen -out openapi.json ../app ../controllers ../models
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed index.html
+var index []byte
+
+// Spec returns the OpenAPI document
+func Spec() []byte {
+	return spec
+}
+
+// SpecHandler serves the OpenAPI document
+func SpecHandler(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	return c.Send(spec)
+}
+
+// UIHandler serves the API docs UI, rendering the OpenAPI document
+func UIHandler(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(index)
+}
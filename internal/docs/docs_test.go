@@ -0,0 +1,40 @@
+package docs
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type DocsTestSuite struct {
+	suite.Suite
+}
+
+func TestDocsTestSuite(t *testing.T) {
+	suite.Run(t, new(DocsTestSuite))
+}
+
+// TestSpecUpToDate fails when the annotations changed without running `make docs`
+func (s *DocsTestSuite) TestSpecUpToDate() {
+	generated, err := Generate("../app", "../controllers", "../models")
+	s.Require().NoError(err)
+	s.Equal(string(generated), string(Spec()), "openapi.json is outdated, run `make docs`")
+}
+
+func (s *DocsTestSuite) TestSpecSchemas() {
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]struct {
+				Description string `json:"description"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	s.Require().NoError(json.Unmarshal(Spec(), &doc))
+
+	s.Equal("3.0.3", doc.OpenAPI)
+	for _, name := range []string{"controllers.MintDataForVins", "controllers.VinUserOperationData", "controllers.APIError"} {
+		s.Contains(doc.Components.Schemas, name)
+		s.NotEmpty(doc.Components.Schemas[name].Description, name)
+	}
+}
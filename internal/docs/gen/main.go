@@ -0,0 +1,23 @@
+// Command gen writes the OpenAPI document generated from the handler annotations of the package directories.
+package main
+
+import (
+	"flag"
+	"github.com/DIMO-Network/volteras-oracle/internal/docs"
+	"log"
+	"os"
+)
+
+func main() {
+	out := flag.String("out", "openapi.json", "output file")
+	flag.Parse()
+
+	spec, err := docs.Generate(flag.Args()...)
+	if err != nil {
+		log.Fatalf("failed to generate OpenAPI document: %v", err)
+	}
+
+	if err := os.WriteFile(*out, spec, 0o644); err != nil {
+		log.Fatalf("failed to write OpenAPI document: %v", err)
+	}
+}
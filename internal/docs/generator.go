@@ -0,0 +1,627 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Generate builds the OpenAPI 3 document from the swag-style annotations (@Summary, @Param, @Success, @Router, ...)
+// of the handlers in the package directories. Types referenced by the annotations are resolved from the same
+// packages, the general API info (@title, @version, @securityDefinitions.apikey) is read from any doc comment.
+func Generate(dirs ...string) ([]byte, error) {
+	g := &generator{
+		types:   make(map[string]*ast.TypeSpec),
+		schemas: make(map[string]*schema),
+		spec: &openAPI{
+			OpenAPI: "3.0.3",
+			Paths:   make(map[string]map[string]*operation),
+		},
+	}
+
+	for _, dir := range dirs {
+		if err := g.parseDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, fn := range g.handlers {
+		if err := g.addOperation(fn.pkg, fn.decl); err != nil {
+			return nil, fmt.Errorf("%s: %w", fn.decl.Name.Name, err)
+		}
+	}
+
+	g.spec.Components.Schemas = g.schemas
+	spec, err := json.MarshalIndent(g.spec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(spec, '\n'), nil
+}
+
+type openAPI struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       info                             `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components components                       `json:"components"`
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type components struct {
+	Schemas         map[string]*schema         `json:"schemas"`
+	SecuritySchemes map[string]*securityScheme `json:"securitySchemes,omitempty"`
+}
+
+type securityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*parameter          `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description,omitempty"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required"`
+	Content     map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+type handler struct {
+	pkg  string
+	decl *ast.FuncDecl
+}
+
+type generator struct {
+	spec     *openAPI
+	types    map[string]*ast.TypeSpec // by package qualified name
+	schemas  map[string]*schema
+	handlers []handler
+}
+
+var routerPattern = regexp.MustCompile(`^(\S+)\s+\[(\w+)]$`)
+
+// wellKnownTypes schemas of types from outside the parsed packages
+var wellKnownTypes = map[string]*schema{
+	"time.Time":             {Type: "string", Format: "date-time"},
+	"time.Duration":         {Type: "integer", Format: "int64"},
+	"json.RawMessage":       {},
+	"common.Address":        {Type: "string", Description: "hex encoded address"},
+	"common.Hash":           {Type: "string", Description: "hex encoded hash"},
+	"hexutil.Bytes":         {Type: "string", Description: "hex encoded bytes"},
+	"null.String":           {Type: "string", Nullable: true},
+	"null.Int":              {Type: "integer", Nullable: true},
+	"null.Int64":            {Type: "integer", Format: "int64", Nullable: true},
+	"null.Bool":             {Type: "boolean", Nullable: true},
+	"null.Time":             {Type: "string", Format: "date-time", Nullable: true},
+	"null.JSON":             {Nullable: true},
+	"types.StringArray":     {Type: "array", Items: &schema{Type: "string"}},
+	"pq.StringArray":        {Type: "array", Items: &schema{Type: "string"}},
+	"big.Int":               {Type: "integer"},
+	"decimal.Decimal":       {Type: "string"},
+	"signer.TypedData":      {Type: "object", Description: "EIP-712 typed data to be signed"},
+	"zerodev.UserOperation": {Type: "object", Description: "ERC-4337 user operation to be signed"},
+}
+
+func (g *generator) parseDir(dir string) error {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		pkg := file.Name.Name
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+						if typeSpec.Doc == nil && len(d.Specs) == 1 {
+							typeSpec.Doc = d.Doc
+						}
+						g.types[pkg+"."+typeSpec.Name.Name] = typeSpec
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Doc == nil {
+					continue
+				}
+				if strings.Contains(d.Doc.Text(), "@title") {
+					g.parseGeneralInfo(d.Doc.Text())
+				}
+				if strings.Contains(d.Doc.Text(), "@Router") {
+					g.handlers = append(g.handlers, handler{pkg: pkg, decl: d})
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseGeneralInfo reads the API info and security schemes from the doc comment with the @title
+func (g *generator) parseGeneralInfo(doc string) {
+	var scheme *securityScheme
+	for _, line := range strings.Split(doc, "\n") {
+		attribute, value := splitAnnotation(line)
+		switch attribute {
+		case "@title":
+			g.spec.Info.Title = value
+		case "@version":
+			g.spec.Info.Version = value
+		case "@description":
+			g.spec.Info.Description = strings.TrimSpace(g.spec.Info.Description + " " + value)
+		case "@securitydefinitions.apikey":
+			if g.spec.Components.SecuritySchemes == nil {
+				g.spec.Components.SecuritySchemes = make(map[string]*securityScheme)
+			}
+			scheme = &securityScheme{Type: "apiKey"}
+			g.spec.Components.SecuritySchemes[value] = scheme
+		case "@in":
+			if scheme != nil {
+				scheme.In = value
+			}
+		case "@name":
+			if scheme != nil {
+				scheme.Name = value
+			}
+		case "@securitydescription":
+			if scheme != nil {
+				scheme.Description = value
+			}
+		}
+	}
+}
+
+func splitAnnotation(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "@") {
+		return "", ""
+	}
+
+	attribute, value, _ := strings.Cut(line, " ")
+	return strings.ToLower(attribute), strings.TrimSpace(value)
+}
+
+func (g *generator) addOperation(pkg string, decl *ast.FuncDecl) error {
+	op := &operation{
+		OperationID: decl.Name.Name,
+		Responses:   make(map[string]*response),
+	}
+	if decl.Recv != nil && len(decl.Recv.List) == 1 {
+		op.Tags = []string{receiverTag(decl.Recv.List[0].Type)}
+	}
+
+	var path, method string
+	accept, produce := "application/json", "application/json"
+	var bodyParam *parameter
+
+	for _, line := range strings.Split(decl.Doc.Text(), "\n") {
+		attribute, value := splitAnnotation(line)
+		switch attribute {
+		case "@summary":
+			op.Summary = value
+		case "@description":
+			op.Description = strings.TrimSpace(op.Description + " " + value)
+		case "@accept":
+			accept = mimeType(value)
+		case "@produce":
+			produce = mimeType(value)
+		case "@security":
+			op.Security = append(op.Security, map[string][]string{value: {}})
+		case "@param":
+			param, err := g.parseParam(pkg, value)
+			if err != nil {
+				return err
+			}
+			if param.In == "body" {
+				bodyParam = param
+				continue
+			}
+			op.Parameters = append(op.Parameters, param)
+		case "@success", "@failure":
+			code, resp, err := g.parseResponse(pkg, value, produce)
+			if err != nil {
+				return err
+			}
+			op.Responses[code] = resp
+		case "@router":
+			match := routerPattern.FindStringSubmatch(value)
+			if match == nil {
+				return fmt.Errorf("invalid @Router %q", value)
+			}
+			path, method = match[1], strings.ToLower(match[2])
+		}
+	}
+
+	if path == "" {
+		return fmt.Errorf("missing @Router")
+	}
+
+	if bodyParam != nil {
+		op.RequestBody = &requestBody{
+			Description: bodyParam.Description,
+			Required:    bodyParam.Required,
+			Content:     map[string]*mediaType{accept: {Schema: bodyParam.Schema}},
+		}
+	}
+
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = &response{Description: "OK"}
+	}
+
+	if _, ok := g.spec.Paths[path]; !ok {
+		g.spec.Paths[path] = make(map[string]*operation)
+	}
+	if _, ok := g.spec.Paths[path][method]; ok {
+		return fmt.Errorf("duplicated route %s %s", method, path)
+	}
+	g.spec.Paths[path][method] = op
+
+	return nil
+}
+
+// parseParam parses `name in type required "description"`
+func (g *generator) parseParam(pkg, value string) (*parameter, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid @Param %q", value)
+	}
+
+	required, err := strconv.ParseBool(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid @Param %q: %w", value, err)
+	}
+
+	s, err := g.typeSchema(pkg, fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return &parameter{
+		Name:        fields[0],
+		In:          fields[1],
+		Required:    required || fields[1] == "path",
+		Description: quoted(value),
+		Schema:      s,
+	}, nil
+}
+
+// parseResponse parses `code {object|array} type "description"`, the type is optional
+func (g *generator) parseResponse(pkg, value, produce string) (string, *response, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("invalid response %q", value)
+	}
+
+	resp := &response{Description: quoted(value)}
+	if resp.Description == "" {
+		resp.Description = httpStatusText(fields[0])
+	}
+
+	if len(fields) >= 3 && strings.HasPrefix(fields[1], "{") {
+		s, err := g.typeSchema(pkg, fields[2])
+		if err != nil {
+			return "", nil, err
+		}
+		if fields[1] == "{array}" {
+			s = &schema{Type: "array", Items: s}
+		}
+		resp.Content = map[string]*mediaType{produce: {Schema: s}}
+	}
+
+	return fields[0], resp, nil
+}
+
+// typeSchema resolves a type named in an annotation
+func (g *generator) typeSchema(pkg, name string) (*schema, error) {
+	if item, ok := strings.CutPrefix(name, "[]"); ok {
+		s, err := g.typeSchema(pkg, item)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Type: "array", Items: s}, nil
+	}
+
+	if s := primitiveSchema(name); s != nil {
+		return s, nil
+	}
+
+	qualified := name
+	if !strings.Contains(name, ".") {
+		qualified = pkg + "." + name
+	}
+
+	if _, ok := g.types[qualified]; !ok {
+		return nil, fmt.Errorf("unknown type %s", name)
+	}
+
+	return g.refSchema(qualified), nil
+}
+
+// refSchema adds the named type to the components, once
+func (g *generator) refSchema(qualified string) *schema {
+	ref := &schema{Ref: "#/components/schemas/" + qualified}
+	if _, ok := g.schemas[qualified]; ok {
+		return ref
+	}
+
+	typeSpec := g.types[qualified]
+	pkg, _, _ := strings.Cut(qualified, ".")
+
+	// placeholder against recursive types
+	g.schemas[qualified] = &schema{}
+	s := g.exprSchema(pkg, typeSpec.Type)
+	if typeSpec.Doc != nil {
+		s.Description = strings.TrimSpace(strings.ReplaceAll(typeSpec.Doc.Text(), "\n", " "))
+	}
+	g.schemas[qualified] = s
+
+	return ref
+}
+
+func (g *generator) exprSchema(pkg string, expr ast.Expr) *schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if s := primitiveSchema(t.Name); s != nil {
+			return s
+		}
+		if _, ok := g.types[pkg+"."+t.Name]; ok {
+			return g.refSchema(pkg + "." + t.Name)
+		}
+		return &schema{}
+	case *ast.StarExpr:
+		return g.exprSchema(pkg, t.X)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: g.exprSchema(pkg, t.Elt)}
+	case *ast.MapType:
+		return &schema{Type: "object", AdditionalProperties: g.exprSchema(pkg, t.Value)}
+	case *ast.SelectorExpr:
+		name := fmt.Sprintf("%s.%s", t.X, t.Sel.Name)
+		if s, ok := wellKnownTypes[name]; ok {
+			copied := *s
+			return &copied
+		}
+		if _, ok := g.types[name]; ok {
+			return g.refSchema(name)
+		}
+		return &schema{Type: "object"}
+	case *ast.StructType:
+		return g.structSchema(pkg, t)
+	case *ast.InterfaceType:
+		return &schema{}
+	}
+
+	return &schema{}
+}
+
+func (g *generator) structSchema(pkg string, st *ast.StructType) *schema {
+	s := &schema{Type: "object", Properties: make(map[string]*schema)}
+
+	for _, field := range st.Fields.List {
+		name, omitEmpty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+
+		// embedded structs are flattened by encoding/json
+		if len(field.Names) == 0 && name == "" {
+			embedded := g.exprSchema(pkg, field.Type)
+			if embedded.Ref != "" {
+				embedded = g.schemas[strings.TrimPrefix(embedded.Ref, "#/components/schemas/")]
+			}
+			for property, propertySchema := range embedded.Properties {
+				s.Properties[property] = propertySchema
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		names := []string{name}
+		if name == "" {
+			names = names[:0]
+			for _, ident := range field.Names {
+				if ident.IsExported() {
+					names = append(names, ident.Name)
+				}
+			}
+		}
+
+		for _, property := range names {
+			propertySchema := g.exprSchema(pkg, field.Type)
+			// siblings of $ref are ignored in OpenAPI 3.0, referenced types carry their own description
+			if description := fieldDescription(field); description != "" && propertySchema.Ref == "" {
+				propertySchema.Description = description
+			}
+			s.Properties[property] = propertySchema
+			if !omitEmpty {
+				s.Required = append(s.Required, property)
+			}
+		}
+	}
+
+	sort.Strings(s.Required)
+	return s
+}
+
+func jsonField(field *ast.Field) (string, bool, bool) {
+	if field.Tag == nil {
+		if len(field.Names) > 0 && !field.Names[0].IsExported() {
+			return "", false, true
+		}
+		return "", false, false
+	}
+
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false, false
+	}
+
+	jsonTag, ok := reflect.StructTag(tag).Lookup("json")
+	if !ok {
+		if len(field.Names) > 0 && !field.Names[0].IsExported() {
+			return "", false, true
+		}
+		return "", false, false
+	}
+	if jsonTag == "-" {
+		return "", false, true
+	}
+
+	name, options, _ := strings.Cut(jsonTag, ",")
+	return name, strings.Contains(options, "omitempty"), false
+}
+
+func fieldDescription(field *ast.Field) string {
+	if field.Doc != nil {
+		return strings.TrimSpace(strings.ReplaceAll(field.Doc.Text(), "\n", " "))
+	}
+	if field.Comment != nil {
+		return strings.TrimSpace(strings.ReplaceAll(field.Comment.Text(), "\n", " "))
+	}
+	return ""
+}
+
+func primitiveSchema(name string) *schema {
+	switch name {
+	case "string":
+		return &schema{Type: "string"}
+	case "bool":
+		return &schema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return &schema{Type: "integer"}
+	case "int64", "uint64":
+		return &schema{Type: "integer", Format: "int64"}
+	case "float32", "float64", "number":
+		return &schema{Type: "number"}
+	case "integer":
+		return &schema{Type: "integer"}
+	case "boolean":
+		return &schema{Type: "boolean"}
+	case "object":
+		return &schema{Type: "object"}
+	case "interface{}", "any":
+		return &schema{}
+	}
+
+	return nil
+}
+
+func receiverTag(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSuffix(ident.Name, "Controller"))
+}
+
+func mimeType(value string) string {
+	switch value {
+	case "json":
+		return "application/json"
+	case "plain":
+		return "text/plain"
+	case "event-stream":
+		return "text/event-stream"
+	case "csv":
+		return "text/csv"
+	}
+
+	return value
+}
+
+func quoted(value string) string {
+	start := strings.Index(value, `"`)
+	end := strings.LastIndex(value, `"`)
+	if start < 0 || end <= start {
+		return ""
+	}
+
+	return value[start+1 : end]
+}
+
+func httpStatusText(code string) string {
+	status, err := strconv.Atoi(code)
+	if err != nil || http.StatusText(status) == "" {
+		return code
+	}
+
+	return http.StatusText(status)
+}
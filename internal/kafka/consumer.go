@@ -4,9 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/onboarding"
 	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/IBM/sarama"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+	"strconv"
 	"time"
 )
 
@@ -26,11 +33,14 @@ func (h MessageHandlerUnbuffered) Cleanup(_ sarama.ConsumerGroupSession) error {
 	return nil
 }
 
-// MessageHandlerOperations is a Kafka consumer group handler for operations messages.
+// MessageHandlerOperations is a Kafka consumer group handler for operations messages. Connections and disconnections
+// confirmed by the vendor are sent to the webhooks, deliveries are queued with QueueWebhooks.
 type MessageHandlerOperations struct {
 	Logger            *zerolog.Logger
 	OracleService     *service.OracleService
 	EnrollmentChannel chan models.OperationMessage
+	Webhooks          *service.Webhooks
+	QueueWebhooks     service.QueueWebhookDeliveries
 }
 
 func (h MessageHandlerOperations) Setup(_ sarama.ConsumerGroupSession) error {
@@ -50,7 +60,7 @@ func SetupKafkaConsumer(
 	topic string,
 	consumerGroupID string,
 	handler sarama.ConsumerGroupHandler,
-) error {
+) (*ConsumerStatus, error) {
 	logger.Info().
 		Strs("brokers", brokerList).
 		Str("topic", topic).
@@ -61,24 +71,45 @@ func SetupKafkaConsumer(
 	consumer, err := sarama.NewConsumerGroup(brokerList, consumerGroupID, getSaramaConfig())
 	if err != nil {
 		logger.Error().Err(err).Msgf("Failed to create Sarama consumer group")
-		return err
+		return nil, err
 	}
 
 	logger.Info().Msgf("Sarama consumer group %s created successfully.", consumerGroupID)
 
+	status := &ConsumerStatus{}
+	handler = statusHandler{ConsumerGroupHandler: handler, status: status}
+
 	// Context for consumer
 	go func() {
+		backoff := consumeBackoffInitial
 		for {
 			err := consumer.Consume(ctx, []string{topic}, handler)
-			if err != nil {
-				logger.Error().Err(err).Msg("Error consuming messages from Kafka")
+			if ctx.Err() != nil {
+				// Exit loop if context is canceled
 				return
 			}
 
-			if ctx.Err() != nil {
-				// Exit loop if context is canceled
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				logger.Error().Err(err).Msg("Kafka consumer group closed")
+				status.setStopped(err)
 				return
 			}
+
+			if err == nil {
+				backoff = consumeBackoffInitial
+				continue
+			}
+
+			// brokers can be unreachable for a while, the consumer recovers once they are back
+			logger.Error().Err(err).Dur("backoff", backoff).Msg("Error consuming messages from Kafka, retrying")
+			status.setFailed(err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, consumeBackoffMax)
 		}
 	}()
 
@@ -91,7 +122,33 @@ func SetupKafkaConsumer(
 		}
 	}()
 
-	return nil
+	return status, nil
+}
+
+const (
+	// first delay before consuming again after an error, doubled on every error in a row
+	consumeBackoffInitial = time.Second
+	consumeBackoffMax     = time.Minute
+)
+
+// statusHandler marks the consumer as consuming again when a session starts
+type statusHandler struct {
+	sarama.ConsumerGroupHandler
+	status *ConsumerStatus
+}
+
+func (h statusHandler) Setup(s sarama.ConsumerGroupSession) error {
+	h.status.setConsuming()
+	return h.ConsumerGroupHandler.Setup(s)
+}
+
+// observeLag sets the lag of the partition, messages behind the newest one when the message is consumed
+func observeLag(claim sarama.ConsumerGroupClaim, msg *sarama.ConsumerMessage) {
+	lag := claim.HighWaterMarkOffset() - msg.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+	consumerLag.WithLabelValues(msg.Topic, strconv.Itoa(int(msg.Partition))).Set(float64(lag))
 }
 
 func getSaramaConfig() *sarama.Config {
@@ -110,9 +167,12 @@ func (h MessageHandlerUnbuffered) ConsumeClaim(s sarama.ConsumerGroupSession, c
 			Str("key", string(msg.Key)).
 			Str("value", string(msg.Value)).
 			Msg("Received Kafka message for UnbufferedTelemetryTopic")
+		observeLag(c, msg)
 
 		// Process the message using OracleService
-		err := h.OracleService.HandleDeviceByVIN(msg.Value)
+		ctx, span := startMessageSpan(s.Context(), msg)
+		err := h.OracleService.HandleDeviceByVIN(ctx, msg.Value)
+		tracing.End(span, err)
 		if err != nil {
 			h.Logger.Error().Err(err).Msg("Failed to process Kafka message")
 			continue
@@ -147,56 +207,97 @@ func (h MessageHandlerOperations) ConsumeClaim(s sarama.ConsumerGroupSession, c
 			Str("key", string(msg.Key)).
 			Str("value", string(msg.Value)).
 			Msg("Received Kafka message for OperationsTopic")
+		observeLag(c, msg)
 
-		// Parse the Kafka message into OperationMessage
-		var operation models.OperationMessage
-		if err := json.Unmarshal(msg.Value, &operation); err != nil {
-			h.Logger.Error().Err(err).Msg("Failed to parse Kafka message into OperationMessage")
+		ctx, span := startMessageSpan(s.Context(), msg)
+		err := h.handleOperation(ctx, msg)
+		tracing.End(span, err)
+		if err != nil {
 			continue
 		}
 
-		h.Logger.Debug().Interface("operation", operation).Msg("Received Kafka message for OperationsTopic")
+		// Mark the message as processed
+		s.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+func (h MessageHandlerOperations) handleOperation(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	// Parse the Kafka message into OperationMessage
+	var operation models.OperationMessage
+	if err := json.Unmarshal(msg.Value, &operation); err != nil {
+		h.Logger.Error().Err(err).Msg("Failed to parse Kafka message into OperationMessage")
+		return err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(tracing.VIN(operation.VIN))
 
-		if operation.Type == OperationTypeEnrollment {
-			var operationError *models.OperationError
+	h.Logger.Debug().Interface("operation", operation).Msg("Received Kafka message for OperationsTopic")
 
-			if operation.Action == ActionEnroll {
-				if operation.Status == OperationStatusFailed {
-					operationError = &operation.Error
-				}
+	if operation.Type == OperationTypeEnrollment {
+		var operationError *models.OperationError
 
-				var vehicleId string
-				vehicleId = operation.ID // We let external_id be operation.ID until we get the vehicleId from the motorq
-				// When operation succeeds, update the database with VIN, Status, and vehicle_Id
-				if operation.Status == OperationStatusSucceeded {
-					vehicleId = operation.Data.VehicleID
-				}
+		if operation.Action == ActionEnroll {
+			if operation.Status == OperationStatusFailed {
+				operationError = &operation.Error
+			}
 
-				h.EnrollmentChannel <- operation
+			var vehicleId string
+			vehicleId = operation.ID // We let external_id be operation.ID until we get the vehicleId from the motorq
+			// When operation succeeds, update the database with VIN, Status, and vehicle_Id
+			if operation.Status == OperationStatusSucceeded {
+				vehicleId = operation.Data.VehicleID
+			}
 
-				// Update the database with VIN, Status, and vehicleId
-				if err := h.OracleService.Db.UpdateEnrollmentStatus(h.OracleService.Ctx, operation.VIN, operation.Status, vehicleId, operationError); err != nil {
-					h.Logger.Error().Err(err).Msgf("Failed to update database for VIN: %s", operation.VIN)
-					continue
-				}
+			h.EnrollmentChannel <- operation
 
-				h.Logger.Debug().Msgf("Successfully updated database for VIN: %s with Status: %s and externalId: %s", operation.VIN, operation.Status, vehicleId)
-			} else if operation.Action == ActionUnenroll {
-				h.EnrollmentChannel <- operation
+			// Update the database with VIN, Status, and vehicleId
+			if err := h.OracleService.Db.UpdateEnrollmentStatus(h.OracleService.Ctx, operation.VIN, operation.Status, vehicleId, operation.Data.DataServices, operationError); err != nil {
+				h.Logger.Error().Err(err).Msgf("Failed to update database for VIN: %s", operation.VIN)
+				return err
+			}
 
-				// Update the database with VIN, Status
-				if err := h.OracleService.Db.UpdateUnenrollmentStatus(h.OracleService.Ctx, operation.VIN, operation.Status, operationError); err != nil {
-					h.Logger.Error().Err(err).Msgf("Failed to update database for VIN: %s", operation.VIN)
-					continue
-				}
+			h.Logger.Debug().Msgf("Successfully updated database for VIN: %s with Status: %s and externalId: %s", operation.VIN, operation.Status, vehicleId)
 
-				h.Logger.Debug().Msgf("Successfully updated database for VIN: %s with Status: %s and externalId: %s", operation.VIN, operation.Status, operation.ID)
+			if operation.Status == OperationStatusSucceeded {
+				h.fireWebhookEvent(ctx, service.WebhookEventVehicleConnected, operation.VIN)
+			}
+		} else if operation.Action == ActionUnenroll {
+			h.EnrollmentChannel <- operation
 
+			// Update the database with VIN, Status
+			if err := h.OracleService.Db.UpdateUnenrollmentStatus(h.OracleService.Ctx, operation.VIN, operation.Status, operationError); err != nil {
+				h.Logger.Error().Err(err).Msgf("Failed to update database for VIN: %s", operation.VIN)
+				return err
 			}
 
+			h.Logger.Debug().Msgf("Successfully updated database for VIN: %s with Status: %s and externalId: %s", operation.VIN, operation.Status, operation.ID)
+
+			if operation.Status == OperationStatusSucceeded {
+				h.fireWebhookEvent(ctx, service.WebhookEventVehicleDisconnected, operation.VIN)
+			}
 		}
-		// Mark the message as processed
-		s.MarkMessage(msg, "")
+
 	}
+
 	return nil
 }
+
+// fireWebhookEvent sends the event of the VIN to the webhooks, failures are only logged, the operation is stored
+func (h MessageHandlerOperations) fireWebhookEvent(ctx context.Context, event, vin string) {
+	if h.Webhooks == nil {
+		return
+	}
+
+	record, err := h.OracleService.Db.GetVehicleByVin(ctx, vin)
+	if err != nil {
+		h.Logger.Error().Err(err).Msgf("Failed to load VIN %s for the %s webhook event", vin, event)
+		return
+	}
+
+	onboarding.FireWebhookEvent(ctx, *h.Logger, h.Webhooks, h.QueueWebhooks, event, record)
+}
+
+var consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "dimo_oracle_kafka_consumer_lag",
+	Help: "Messages of the partition not consumed yet",
+}, []string{"topic", "partition"})
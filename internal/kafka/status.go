@@ -0,0 +1,61 @@
+package kafka
+
+import (
+	"context"
+	"github.com/friendsofgo/errors"
+	"sync"
+)
+
+// ConsumerStatus tracks whether a consumer group is consuming. Errors of the consume loop are retried, the consumer
+// is failing until a new session starts. It only stops for good when the consumer group was closed.
+type ConsumerStatus struct {
+	mu      sync.RWMutex
+	err     error
+	stopped bool
+}
+
+// HealthCheck fails while the consume loop retries after an error, it recovers on its own
+func (s *ConsumerStatus) HealthCheck(_ context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.err != nil {
+		return errors.Wrap(s.err, "consumer failing")
+	}
+
+	return nil
+}
+
+// StoppedHealthCheck fails once the consume loop stopped for good, which only a restart recovers
+func (s *ConsumerStatus) StoppedHealthCheck(_ context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.stopped {
+		return errors.Wrap(s.err, "consumer stopped")
+	}
+
+	return nil
+}
+
+func (s *ConsumerStatus) setFailed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+func (s *ConsumerStatus) setStopped(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+	s.stopped = true
+}
+
+func (s *ConsumerStatus) setConsuming() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = nil
+}
@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"context"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headerCarrier reads the trace context from the headers of a message
+type headerCarrier []*sarama.RecordHeader
+
+func (c headerCarrier) Get(key string) string {
+	for _, header := range c {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set is not used, the oracle doesn't produce messages
+func (c headerCarrier) Set(_, _ string) {}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for _, header := range c {
+		if header != nil {
+			keys = append(keys, string(header.Key))
+		}
+	}
+
+	return keys
+}
+
+// startMessageSpan starts the span of processing a message, continuing the trace of the producer when its headers have one
+func startMessageSpan(ctx context.Context, msg *sarama.ConsumerMessage) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(msg.Headers))
+
+	return tracing.Tracer().Start(ctx, "kafka.consume "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.Int("messaging.kafka.destination.partition", int(msg.Partition)),
+			attribute.Int64("messaging.kafka.message.offset", msg.Offset),
+			attribute.String("messaging.kafka.message.key", string(msg.Key)),
+		),
+	)
+}
@@ -1,13 +1,21 @@
 package mocks
 
 import (
+	"context"
 	"errors"
 	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"slices"
 )
 
 type IdentityAPIMock struct {
 	Vehicles          []models.Vehicle
 	DeviceDefinitions []models.DeviceDefinition
+	// returned by the vehicle lookups when set
+	Err error
+	// returned by the device definition lookup when set
+	DefinitionErr error
+	// cached vehicles, eg. with the owner before a transfer, the cache holds Vehicles when nil
+	Cached []models.Vehicle
 }
 
 func NewIdentityAPIMock(v []models.Vehicle, dd []models.DeviceDefinition) *IdentityAPIMock {
@@ -28,7 +36,12 @@ func (m *IdentityAPIMock) GetVehicleByTokenID(tokenID int64) (*models.Vehicle, e
 }
 
 func (m *IdentityAPIMock) GetCachedVehicleByTokenID(tokenID int64) (*models.Vehicle, error) {
-	for _, vehicle := range m.Vehicles {
+	cached := m.Vehicles
+	if m.Cached != nil {
+		cached = m.Cached
+	}
+
+	for _, vehicle := range cached {
 		if vehicle.TokenID == tokenID {
 			return &vehicle, nil
 		}
@@ -37,7 +50,7 @@ func (m *IdentityAPIMock) GetCachedVehicleByTokenID(tokenID int64) (*models.Vehi
 	return nil, errors.New("vehicle not found")
 }
 
-func (m *IdentityAPIMock) FetchVehicleByTokenID(tokenID int64) (*models.Vehicle, error) {
+func (m *IdentityAPIMock) FetchVehicleByTokenID(_ context.Context, tokenID int64) (*models.Vehicle, error) {
 	for _, vehicle := range m.Vehicles {
 		if vehicle.TokenID == tokenID {
 			return &vehicle, nil
@@ -47,7 +60,11 @@ func (m *IdentityAPIMock) FetchVehicleByTokenID(tokenID int64) (*models.Vehicle,
 	return nil, errors.New("vehicle not found")
 }
 
-func (m *IdentityAPIMock) FetchVehiclesByWalletAddress(address string) ([]models.Vehicle, error) {
+func (m *IdentityAPIMock) FetchVehiclesByWalletAddress(_ context.Context, address string) ([]models.Vehicle, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+
 	returnVal := []models.Vehicle{}
 	for _, vehicle := range m.Vehicles {
 		if vehicle.Owner == address {
@@ -57,14 +74,33 @@ func (m *IdentityAPIMock) FetchVehiclesByWalletAddress(address string) ([]models
 	return returnVal, nil
 }
 
-func (m *IdentityAPIMock) GetDeviceDefinitionByID(id string) (*models.DeviceDefinition, error) {
+func (m *IdentityAPIMock) FetchVehiclesByTokenIDs(_ context.Context, tokenIDs []int64) (map[int64]models.Vehicle, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+
+	returnVal := make(map[int64]models.Vehicle)
+	for _, vehicle := range m.Vehicles {
+		if slices.Contains(tokenIDs, vehicle.TokenID) {
+			returnVal[vehicle.TokenID] = vehicle
+		}
+	}
+	return returnVal, nil
+}
+
+func (m *IdentityAPIMock) GetDeviceDefinitionByID(_ context.Context, id string) (*models.DeviceDefinition, error) {
+	if m.DefinitionErr != nil {
+		return nil, m.DefinitionErr
+	}
+
 	for _, definition := range m.DeviceDefinitions {
 		if definition.DeviceDefinitionID == id {
 			return &definition, nil
 		}
 	}
 
-	return nil, errors.New("device definition not found")
+	// identity-api answers unknown definitions with an empty one
+	return &models.DeviceDefinition{}, nil
 }
 
 func (m *IdentityAPIMock) GetCachedDeviceDefinitionByID(id string) (*models.DeviceDefinition, error) {
@@ -77,7 +113,7 @@ func (m *IdentityAPIMock) GetCachedDeviceDefinitionByID(id string) (*models.Devi
 	return nil, errors.New("device definition not found")
 }
 
-func (m *IdentityAPIMock) FetchDeviceDefinitionByID(id string) (*models.DeviceDefinition, error) {
+func (m *IdentityAPIMock) FetchDeviceDefinitionByID(_ context.Context, id string) (*models.DeviceDefinition, error) {
 	for _, definition := range m.DeviceDefinitions {
 		if definition.DeviceDefinitionID == id {
 			return &definition, nil
@@ -1,25 +1,54 @@
 package models
 
+import "time"
+
 type GraphQLRequest struct {
 	Query string `json:"query"`
 }
 
 type Vehicle struct {
-	VIN                 string          `json:"vin"`
-	ID                  string          `json:"id"`
-	TokenID             int64           `json:"tokenId"`
-	MintedAt            string          `json:"mintedAt"`
-	Owner               string          `json:"owner"`
-	Definition          Definition      `json:"definition"`
-	SyntheticDevice     SyntheticDevice `json:"syntheticDevice"`
-	ConnectionStatus    string          `json:"connectionStatus"`
-	DisconnectionStatus string          `json:"disconnectionStatus"`
+	VIN                 string           `json:"vin"`
+	ID                  string           `json:"id"`
+	TokenID             int64            `json:"tokenId"`
+	MintedAt            string           `json:"mintedAt"`
+	Owner               string           `json:"owner"`
+	Definition          Definition       `json:"definition"`
+	SyntheticDevice     SyntheticDevice  `json:"syntheticDevice"`
+	ConnectionStatus    string           `json:"connectionStatus"`
+	DisconnectionStatus string           `json:"disconnectionStatus"`
+	DataServices        []string         `json:"dataServices,omitempty"`
+	Telemetry           *TelemetryStatus `json:"telemetry,omitempty"`
+	Onboarding          *OnboardingState `json:"onboarding,omitempty"`
+}
+
+// OnboardingState is the onboarding status of the vehicle, with the last error of the vendor if any
+type OnboardingState struct {
+	Status    string          `json:"status"`
+	Details   string          `json:"details"`
+	LastError *OperationError `json:"lastError,omitempty"`
+}
+
+// TelemetryStatus tells when the oracle last received and forwarded data for the vehicle
+type TelemetryStatus struct {
+	LastReceivedAt  *time.Time `json:"lastReceivedAt,omitempty"`
+	LastForwardedAt *time.Time `json:"lastForwardedAt,omitempty"`
+	LastError       string     `json:"lastError,omitempty"`
+	LastErrorAt     *time.Time `json:"lastErrorAt,omitempty"`
+	// last forwarded value of every signal, by signal name
+	Signals map[string]SignalValue `json:"signals,omitempty"`
+}
+
+// SignalValue is the last forwarded value of a signal
+type SignalValue struct {
+	Value     any       `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 type SyntheticDevice struct {
 	ID       string `json:"id"`
 	TokenID  int64  `json:"tokenId"`
 	MintedAt string `json:"mintedAt"`
+	Address  string `json:"address,omitempty"`
 }
 
 type Definition struct {
@@ -90,6 +119,19 @@ type Signal struct {
 	Units      string  `json:"units"`
 }
 
+// EventsData is the data of a DIMO event CloudEvent
+type EventsData struct {
+	Events []Event `json:"events"`
+}
+
+// Event is a single vehicle event, eg. a finished trip or charging session
+type Event struct {
+	Name       string    `json:"name"`
+	Timestamp  time.Time `json:"timestamp"`
+	DurationNs int64     `json:"durationNs"`
+	Metadata   string    `json:"metadata,omitempty"`
+}
+
 type SubStatus struct {
 	SourceTelemetryFuel struct {
 		Status string `json:"status"`
@@ -11,11 +11,13 @@ import (
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
 	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
 	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/friendsofgo/errors"
 	"github.com/riverqueue/river"
 	"github.com/rs/zerolog"
 	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"go.opentelemetry.io/otel/trace"
 	"sync"
 	"time"
 )
@@ -46,11 +48,12 @@ type DeleteWorker struct {
 	ws       service.SDWalletsAPI
 	m        sync.RWMutex
 	vendor   VendorOnboardingAPI
+	webhooks *service.Webhooks
 
 	river.WorkerDefaults[DeleteArgs]
 }
 
-func NewDeleteWorker(settings *config.Settings, logger zerolog.Logger, identity service.IdentityAPI, dbs *db.Store, tr *transactions.Client, ws service.SDWalletsAPI, vendor VendorOnboardingAPI) *DeleteWorker {
+func NewDeleteWorker(settings *config.Settings, logger zerolog.Logger, identity service.IdentityAPI, dbs *db.Store, tr *transactions.Client, ws service.SDWalletsAPI, vendor VendorOnboardingAPI, webhooks *service.Webhooks) *DeleteWorker {
 	return &DeleteWorker{
 		settings: settings,
 		logger:   logger,
@@ -59,6 +62,7 @@ func NewDeleteWorker(settings *config.Settings, logger zerolog.Logger, identity
 		tr:       tr,
 		ws:       ws,
 		vendor:   vendor,
+		webhooks: webhooks,
 	}
 }
 
@@ -66,6 +70,7 @@ func (w *DeleteWorker) Timeout(*river.Job[DeleteArgs]) time.Duration { return 30
 
 func (w *DeleteWorker) Work(ctx context.Context, job *river.Job[DeleteArgs]) error {
 	w.logger.Debug().Str(logfields.VIN, job.Args.VIN).Msg("Delete VIN")
+	trace.SpanFromContext(ctx).SetAttributes(tracing.VIN(job.Args.VIN))
 
 	// Check if the VIN record exists
 	record, err := w.GetVinRecord(ctx, job.Args.VIN)
@@ -95,6 +100,8 @@ func (w *DeleteWorker) Work(ctx context.Context, job *river.Job[DeleteArgs]) err
 		if err != nil {
 			return err
 		}
+
+		fireWebhookEvent(ctx, w.logger, w.webhooks, service.WebhookEventVehicleDeleted, record)
 	}
 
 	return nil
@@ -119,7 +126,9 @@ func (w *DeleteWorker) BurnVehicleAndUpdate(ctx context.Context, record *dbmodel
 	w.logger.Debug().Str(logfields.VIN, args.VIN).Msg("Burning Vehicle")
 
 	w.m.Lock()
+	done := startCall(ctx, tracing.PeerChain, "burnVehicle", args.VIN)
 	opResult, err := w.tr.SendSignedUserOperation(args.UserOperation, true)
+	done(err)
 	if err != nil {
 		w.m.Unlock()
 		w.logger.Error().Err(err).Msg("Failed to Burn Vehicle")
@@ -11,10 +11,12 @@ import (
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
 	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
 	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/riverqueue/river"
 	"github.com/rs/zerolog"
 	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"go.opentelemetry.io/otel/trace"
 	"sync"
 	"time"
 )
@@ -45,11 +47,12 @@ type DisconnectWorker struct {
 	ws       service.SDWalletsAPI
 	m        sync.RWMutex
 	vendor   VendorOnboardingAPI
+	webhooks *service.Webhooks
 
 	river.WorkerDefaults[DisconnectArgs]
 }
 
-func NewDisconnectWorker(settings *config.Settings, logger zerolog.Logger, identity service.IdentityAPI, dbs *db.Store, tr *transactions.Client, ws service.SDWalletsAPI, vendor VendorOnboardingAPI) *DisconnectWorker {
+func NewDisconnectWorker(settings *config.Settings, logger zerolog.Logger, identity service.IdentityAPI, dbs *db.Store, tr *transactions.Client, ws service.SDWalletsAPI, vendor VendorOnboardingAPI, webhooks *service.Webhooks) *DisconnectWorker {
 	return &DisconnectWorker{
 		settings: settings,
 		logger:   logger,
@@ -58,6 +61,7 @@ func NewDisconnectWorker(settings *config.Settings, logger zerolog.Logger, ident
 		tr:       tr,
 		ws:       ws,
 		vendor:   vendor,
+		webhooks: webhooks,
 	}
 }
 
@@ -65,6 +69,7 @@ func (w *DisconnectWorker) Timeout(*river.Job[DisconnectArgs]) time.Duration { r
 
 func (w *DisconnectWorker) Work(ctx context.Context, job *river.Job[DisconnectArgs]) error {
 	w.logger.Debug().Str(logfields.VIN, job.Args.VIN).Msg("Disconnection VIN")
+	trace.SpanFromContext(ctx).SetAttributes(tracing.VIN(job.Args.VIN))
 
 	// Check if the VIN record exists
 	record, err := w.GetVinRecord(ctx, job.Args.VIN)
@@ -87,6 +92,11 @@ func (w *DisconnectWorker) Work(ctx context.Context, job *river.Job[DisconnectAr
 		return err
 	}
 
+	// the vendor confirms the disconnection later, the operations consumer fires the event then
+	if !w.settings.EnableVendorConnection {
+		fireWebhookEvent(ctx, w.logger, w.webhooks, service.WebhookEventVehicleDisconnected, record)
+	}
+
 	// If SD is valid - burn
 	if record.SyntheticTokenID.Valid {
 		w.logger.Debug().Str(logfields.VIN, job.Args.VIN).Msg("Burning SD")
@@ -118,7 +128,9 @@ func (w *DisconnectWorker) DisconnectFromVendorAndUpdate(ctx context.Context, re
 	record.OnboardingStatus = OnboardingStatusDisconnectUnknown
 
 	if w.settings.EnableVendorConnection {
+		done := startCall(ctx, tracing.PeerVendor, "disconnect", args.VIN)
 		connection, err := w.vendor.Disconnect([]string{args.VIN})
+		done(err)
 		if err != nil {
 			w.logger.Error().Err(err).Msg("Failed to disconnect from vendor")
 			record.OnboardingStatus = OnboardingStatusDisconnectFailure
@@ -155,13 +167,15 @@ func (w *DisconnectWorker) DisconnectFromVendorAndUpdate(ctx context.Context, re
 func (w *DisconnectWorker) BurnSDAndUpdate(ctx context.Context, record *dbmodels.Vin, args DisconnectArgs) (*dbmodels.Vin, error) {
 	// make sure we save status update (and possible new DD)
 	defer (func() {
-		_ = w.update(ctx, record, args, boil.Whitelist(dbmodels.VinColumns.OnboardingStatus, dbmodels.VinColumns.SyntheticTokenID, dbmodels.VinColumns.WalletIndex))
+		_ = w.update(ctx, record, args, boil.Whitelist(dbmodels.VinColumns.OnboardingStatus, dbmodels.VinColumns.SyntheticTokenID, dbmodels.VinColumns.WalletIndex, dbmodels.VinColumns.SDAddress))
 	})()
 
 	w.logger.Debug().Str(logfields.VIN, args.VIN).Msg("Burning SD")
 
 	w.m.Lock()
+	done := startCall(ctx, tracing.PeerChain, "burnSd", args.VIN)
 	opResult, err := w.tr.SendSignedUserOperation(args.UserOperation, true)
+	done(err)
 	if err != nil {
 		w.m.Unlock()
 		w.logger.Error().Err(err).Msg("Failed to Burn SD")
@@ -179,6 +193,7 @@ func (w *DisconnectWorker) BurnSDAndUpdate(ctx context.Context, record *dbmodels
 	w.m.Unlock()
 
 	record.WalletIndex = null.NewInt64(0, false)
+	record.SDAddress = null.NewString("", false)
 	record.SyntheticTokenID = null.NewInt64(0, false)
 	record.OnboardingStatus = OnboardingStatusBurnSDSuccess
 
@@ -0,0 +1,42 @@
+package onboarding
+
+import (
+	"context"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/riverqueue/river"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// IdempotencyKeysCleanupInterval how often expired idempotency keys are removed
+const IdempotencyKeysCleanupInterval = time.Hour
+
+type IdempotencyKeysCleanupArgs struct{}
+
+func (a IdempotencyKeysCleanupArgs) Kind() string {
+	return "idempotency_keys_cleanup"
+}
+
+type IdempotencyKeysCleanupWorker struct {
+	logger          zerolog.Logger
+	idempotencyKeys *service.IdempotencyKeys
+
+	river.WorkerDefaults[IdempotencyKeysCleanupArgs]
+}
+
+func NewIdempotencyKeysCleanupWorker(logger zerolog.Logger, idempotencyKeys *service.IdempotencyKeys) *IdempotencyKeysCleanupWorker {
+	return &IdempotencyKeysCleanupWorker{
+		logger:          logger,
+		idempotencyKeys: idempotencyKeys,
+	}
+}
+
+func (w *IdempotencyKeysCleanupWorker) Work(ctx context.Context, _ *river.Job[IdempotencyKeysCleanupArgs]) error {
+	deleted, err := w.idempotencyKeys.DeleteExpired(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.logger.Debug().Int64("deleted", deleted).Msg("Deleted expired idempotency keys")
+	return nil
+}
@@ -0,0 +1,145 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"github.com/DIMO-Network/shared/pkg/db"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/rs/zerolog"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"time"
+)
+
+// Outcomes of jobs and calls in the metrics
+const (
+	outcomeSuccess   = "success"
+	outcomeError     = "error"
+	outcomeSnoozed   = "snoozed"
+	outcomeCancelled = "cancelled"
+	outcomeDiscarded = "discarded"
+
+	vinStatusQueryTimeout = 5 * time.Second
+)
+
+// JobMetricsMiddleware records the duration and outcome of every worked job
+type JobMetricsMiddleware struct {
+	river.MiddlewareDefaults
+}
+
+func (m *JobMetricsMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(context.Context) error) error {
+	start := time.Now()
+	err := doInner(ctx)
+	jobDuration.WithLabelValues(job.Kind, jobOutcome(job, err)).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+func jobOutcome(job *rivertype.JobRow, err error) string {
+	var snoozeErr *rivertype.JobSnoozeError
+	var cancelErr *rivertype.JobCancelError
+
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case errors.As(err, &snoozeErr):
+		return outcomeSnoozed
+	case errors.As(err, &cancelErr):
+		return outcomeCancelled
+	case job.Attempt >= job.MaxAttempts:
+		return outcomeDiscarded
+	default:
+		return outcomeError
+	}
+}
+
+// startCall starts the span of a call to the vendor or the chain, the returned function ends it and records its
+// duration and outcome.
+func startCall(ctx context.Context, peer, operation, vin string) func(error) {
+	_, span := tracing.StartClientSpan(ctx, peer, operation, tracing.VIN(vin))
+	start := time.Now()
+
+	return func(err error) {
+		tracing.End(span, err)
+
+		outcome := outcomeSuccess
+		if err != nil {
+			outcome = outcomeError
+		}
+
+		histogram := chainOperationDuration
+		if peer == tracing.PeerVendor {
+			histogram = vendorRequestDuration
+		}
+		histogram.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+	}
+}
+
+// VinStatusCollector reports the number of VINs in every onboarding status, counted when scraped
+type VinStatusCollector struct {
+	dbs    *db.Store
+	logger zerolog.Logger
+}
+
+func NewVinStatusCollector(dbs *db.Store, logger zerolog.Logger) *VinStatusCollector {
+	return &VinStatusCollector{
+		dbs:    dbs,
+		logger: logger,
+	}
+}
+
+var vinsDesc = prometheus.NewDesc("dimo_oracle_vins", "VINs by onboarding status", []string{"status"}, nil)
+
+func (c *VinStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- vinsDesc
+}
+
+func (c *VinStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), vinStatusQueryTimeout)
+	defer cancel()
+
+	var statusCounts []struct {
+		OnboardingStatus int   `boil:"onboarding_status"`
+		Count            int64 `boil:"count"`
+	}
+	err := dbmodels.Vins(
+		qm.Select(dbmodels.VinColumns.OnboardingStatus, "count(*) as count"),
+		qm.GroupBy(dbmodels.VinColumns.OnboardingStatus),
+	).Bind(ctx, c.dbs.DBS().Reader, &statusCounts)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to count VINs by onboarding status")
+		return
+	}
+
+	// statuses without a name are reported together as Unknown
+	counts := make(map[string]float64)
+	for _, statusCount := range statusCounts {
+		counts[GetDetailedStatus(statusCount.OnboardingStatus)] += float64(statusCount.Count)
+	}
+
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(vinsDesc, prometheus.GaugeValue, count, status)
+	}
+}
+
+var jobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "dimo_oracle_job_duration_seconds",
+	Help:    "Duration of worked River jobs by kind and outcome",
+	Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+}, []string{"kind", "outcome"})
+
+var vendorRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "dimo_oracle_vendor_request_duration_seconds",
+	Help:    "Duration of vendor validate, connect and disconnect calls by outcome",
+	Buckets: prometheus.DefBuckets,
+}, []string{"operation", "outcome"})
+
+var chainOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "dimo_oracle_chain_operation_duration_seconds",
+	Help:    "Duration of mints and burns sent through the bundler, until the receipt, by outcome",
+	Buckets: []float64{1, 2, 5, 10, 20, 30, 60, 120, 300},
+}, []string{"operation", "outcome"})
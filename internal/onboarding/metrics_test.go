@@ -0,0 +1,70 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
+	"github.com/friendsofgo/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
+
+func (s *MetricsTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	jobDuration.Reset()
+	vendorRequestDuration.Reset()
+	chainOperationDuration.Reset()
+}
+
+func (s *MetricsTestSuite) TestJobOutcome() {
+	job := &rivertype.JobRow{Kind: "verify", Attempt: 1, MaxAttempts: 3}
+	lastAttempt := &rivertype.JobRow{Kind: "verify", Attempt: 3, MaxAttempts: 3}
+
+	s.Equal(outcomeSuccess, jobOutcome(job, nil))
+	s.Equal(outcomeError, jobOutcome(job, errors.New("decoding failed")))
+	s.Equal(outcomeDiscarded, jobOutcome(lastAttempt, errors.New("decoding failed")))
+	s.Equal(outcomeSnoozed, jobOutcome(lastAttempt, river.JobSnooze(time.Minute)))
+	s.Equal(outcomeCancelled, jobOutcome(job, fmt.Errorf("wrapped: %w", river.JobCancel(errors.New("vin deleted")))))
+}
+
+func (s *MetricsTestSuite) TestJobMetricsMiddleware() {
+	middleware := &JobMetricsMiddleware{}
+	job := &rivertype.JobRow{Kind: "onboarding", Attempt: 1, MaxAttempts: 3}
+
+	err := middleware.Work(s.ctx, job, func(context.Context) error {
+		return nil
+	})
+	s.Require().NoError(err)
+	err = middleware.Work(s.ctx, job, func(context.Context) error {
+		return errors.New("mint failed")
+	})
+	s.Error(err)
+
+	s.Equal(2, testutil.CollectAndCount(jobDuration))
+	s.Equal(1, testutil.CollectAndCount(jobDuration.WithLabelValues("onboarding", outcomeSuccess).(prometheus.Histogram)))
+	s.Equal(1, testutil.CollectAndCount(jobDuration.WithLabelValues("onboarding", outcomeError).(prometheus.Histogram)))
+}
+
+func (s *MetricsTestSuite) TestStartCall() {
+	startCall(s.ctx, tracing.PeerVendor, "validate", "1HGCM82633A004352")(nil)
+	startCall(s.ctx, tracing.PeerChain, "mint", "1HGCM82633A004352")(errors.New("bundler unavailable"))
+
+	s.Equal(1, testutil.CollectAndCount(vendorRequestDuration))
+	s.Equal(1, testutil.CollectAndCount(vendorRequestDuration.WithLabelValues("validate", outcomeSuccess).(prometheus.Histogram)))
+	s.Equal(1, testutil.CollectAndCount(chainOperationDuration))
+	s.Equal(1, testutil.CollectAndCount(chainOperationDuration.WithLabelValues("mint", outcomeError).(prometheus.Histogram)))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
 	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
 	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -21,6 +22,7 @@ import (
 	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
+	"go.opentelemetry.io/otel/trace"
 	"math/big"
 	"strconv"
 	"sync"
@@ -113,11 +115,12 @@ type OnboardingWorker struct {
 	ws       service.SDWalletsAPI
 	m        sync.RWMutex
 	vendor   VendorOnboardingAPI
+	webhooks *service.Webhooks
 
 	river.WorkerDefaults[OnboardingArgs]
 }
 
-func NewOnboardingWorker(settings *config.Settings, logger zerolog.Logger, identity service.IdentityAPI, dbs *db.Store, tr *transactions.Client, ws service.SDWalletsAPI, vendor VendorOnboardingAPI) *OnboardingWorker {
+func NewOnboardingWorker(settings *config.Settings, logger zerolog.Logger, identity service.IdentityAPI, dbs *db.Store, tr *transactions.Client, ws service.SDWalletsAPI, vendor VendorOnboardingAPI, webhooks *service.Webhooks) *OnboardingWorker {
 	return &OnboardingWorker{
 		settings: settings,
 		logger:   logger,
@@ -126,6 +129,7 @@ func NewOnboardingWorker(settings *config.Settings, logger zerolog.Logger, ident
 		tr:       tr,
 		ws:       ws,
 		vendor:   vendor,
+		webhooks: webhooks,
 	}
 }
 
@@ -133,6 +137,7 @@ func (w *OnboardingWorker) Timeout(*river.Job[OnboardingArgs]) time.Duration { r
 
 func (w *OnboardingWorker) Work(ctx context.Context, job *river.Job[OnboardingArgs]) error {
 	w.logger.Debug().Str(logfields.VIN, job.Args.VIN).Msg("Minting VIN")
+	trace.SpanFromContext(ctx).SetAttributes(tracing.VIN(job.Args.VIN))
 
 	// Check if the VIN record exists
 	record, err := w.GetVinRecord(ctx, job.Args.VIN)
@@ -155,6 +160,11 @@ func (w *OnboardingWorker) Work(ctx context.Context, job *river.Job[OnboardingAr
 		return err
 	}
 
+	// the vendor confirms the connection later, the operations consumer fires the event then
+	if !w.settings.EnableVendorConnection {
+		fireWebhookEvent(ctx, w.logger, w.webhooks, service.WebhookEventVehicleConnected, record)
+	}
+
 	// If there's no Vehicle Token ID, mint all
 	if !record.VehicleTokenID.Valid {
 		w.logger.Debug().Str(logfields.VIN, job.Args.VIN).Msg("No Vehicle Token ID, minting all")
@@ -179,6 +189,8 @@ func (w *OnboardingWorker) Work(ctx context.Context, job *river.Job[OnboardingAr
 		}
 	}
 
+	fireWebhookEvent(ctx, w.logger, w.webhooks, service.WebhookEventVehicleMinted, record)
+
 	return nil
 }
 
@@ -195,17 +207,20 @@ func (w *OnboardingWorker) GetVinRecord(ctx context.Context, vinToSearch string)
 func (w *OnboardingWorker) MintVehicleWithSDAndUpdate(ctx context.Context, record *dbmodels.Vin, args OnboardingArgs) (*dbmodels.Vin, error) {
 	// make sure we save status update (and possible new DD)
 	defer (func() {
-		_ = w.update(ctx, record, args, boil.Whitelist(dbmodels.VinColumns.OnboardingStatus, dbmodels.VinColumns.VehicleTokenID, dbmodels.VinColumns.SyntheticTokenID, dbmodels.VinColumns.WalletIndex))
+		_ = w.update(ctx, record, args, boil.Whitelist(dbmodels.VinColumns.OnboardingStatus, dbmodels.VinColumns.VehicleTokenID, dbmodels.VinColumns.SyntheticTokenID, dbmodels.VinColumns.WalletIndex,
+			dbmodels.VinColumns.SDAddress, dbmodels.VinColumns.DefinitionMake, dbmodels.VinColumns.DefinitionModel, dbmodels.VinColumns.DefinitionYear))
 	})()
 
 	w.logger.Debug().Str(logfields.VIN, args.VIN).Msg("Minting Vehicle with SD")
 
-	deviceDefinition, err := w.identity.FetchDeviceDefinitionByID(record.DeviceDefinitionID.String)
+	deviceDefinition, err := w.identity.FetchDeviceDefinitionByID(ctx, record.DeviceDefinitionID.String)
 	if err != nil {
 		w.logger.Error().Err(err).Msg("Failed to fetch device definition")
 		record.OnboardingStatus = OnboardingStatusMintFailure
 		return nil, err
 	}
+	// the definition may have been overridden since the VIN was decoded
+	setDefinition(record, deviceDefinition)
 
 	sdIndex, err := w.GetNextSDWalletIndex(ctx)
 	if err != nil {
@@ -275,7 +290,9 @@ func (w *OnboardingWorker) MintVehicleWithSDAndUpdate(ctx context.Context, recor
 
 	if args.Sacd == nil {
 		w.m.Lock()
+		done := startCall(ctx, tracing.PeerChain, "mintVehicleAndSdWithDd", args.VIN)
 		_, result, err := w.tr.MintVehicleAndSDWithDD(&mintInput, true, true)
+		done(err)
 		if err != nil {
 			w.m.Unlock()
 			w.logger.Error().Err(err).Msg("Failed to mint vehicle and SD")
@@ -285,6 +302,7 @@ func (w *OnboardingWorker) MintVehicleWithSDAndUpdate(ctx context.Context, recor
 		w.m.Unlock()
 
 		record.WalletIndex = null.Int64From(int64(sdIndex.NextVal))
+		record.SDAddress = null.StringFrom(sdAddress.Hex())
 		record.VehicleTokenID = null.Int64From(result.VehicleId.Int64())
 		record.SyntheticTokenID = null.Int64From(result.SyntheticDeviceNode.Int64())
 		record.OnboardingStatus = OnboardingStatusMintSuccess
@@ -300,7 +318,9 @@ func (w *OnboardingWorker) MintVehicleWithSDAndUpdate(ctx context.Context, recor
 
 		w.m.Lock()
 
+		done := startCall(ctx, tracing.PeerChain, "mintVehicleAndSdWithDdAndSacd", args.VIN)
 		_, result, err := w.tr.MintVehicleAndSDWithDDAndSACD(&mintInput, sacdInput, true, true)
+		done(err)
 		if err != nil {
 			w.m.Unlock()
 			w.logger.Error().Err(err).Msg("Failed to mint vehicle and SD and SACD")
@@ -310,6 +330,7 @@ func (w *OnboardingWorker) MintVehicleWithSDAndUpdate(ctx context.Context, recor
 		w.m.Unlock()
 
 		record.WalletIndex = null.Int64From(int64(sdIndex.NextVal))
+		record.SDAddress = null.StringFrom(sdAddress.Hex())
 		record.VehicleTokenID = null.Int64From(result.VehicleId.Int64())
 		record.SyntheticTokenID = null.Int64From(result.SyntheticDeviceNode.Int64())
 		record.OnboardingStatus = OnboardingStatusMintSuccess
@@ -324,7 +345,7 @@ func (w *OnboardingWorker) MintVehicleWithSDAndUpdate(ctx context.Context, recor
 func (w *OnboardingWorker) MintSDAndUpdate(ctx context.Context, record *dbmodels.Vin, args OnboardingArgs) (vinRecord *dbmodels.Vin, err error) {
 	// make sure we save status update (and possible new DD)
 	defer (func() {
-		_ = w.update(ctx, record, args, boil.Whitelist(dbmodels.VinColumns.OnboardingStatus, dbmodels.VinColumns.SyntheticTokenID, dbmodels.VinColumns.WalletIndex))
+		_ = w.update(ctx, record, args, boil.Whitelist(dbmodels.VinColumns.OnboardingStatus, dbmodels.VinColumns.SyntheticTokenID, dbmodels.VinColumns.WalletIndex, dbmodels.VinColumns.SDAddress))
 	})()
 
 	w.logger.Debug().Str(logfields.VIN, args.VIN).Msg("Minting SD")
@@ -378,7 +399,9 @@ func (w *OnboardingWorker) MintSDAndUpdate(ctx context.Context, record *dbmodels
 	}
 
 	w.m.Lock()
+	done := startCall(ctx, tracing.PeerChain, "mintSd", args.VIN)
 	_, result, err := w.tr.MintSD(&mintInput, true, true)
+	done(err)
 	if err != nil {
 		w.m.Unlock()
 		w.logger.Error().Err(err).Msg("Failed to mint SD")
@@ -388,6 +411,7 @@ func (w *OnboardingWorker) MintSDAndUpdate(ctx context.Context, record *dbmodels
 	w.m.Unlock()
 
 	record.WalletIndex = null.Int64From(int64(sdIndex.NextVal))
+	record.SDAddress = null.StringFrom(sdAddress.Hex())
 	record.SyntheticTokenID = null.Int64From(result.SyntheticDeviceNode.Int64())
 	record.OnboardingStatus = OnboardingStatusMintSuccess
 
@@ -405,7 +429,9 @@ func (w *OnboardingWorker) ConnectToVendorAndUpdate(ctx context.Context, record
 	record.OnboardingStatus = OnboardingStatusConnectUnknown
 
 	if w.settings.EnableVendorConnection {
+		done := startCall(ctx, tracing.PeerVendor, "connect", args.VIN)
 		connection, err := w.vendor.Connect([]string{args.VIN})
+		done(err)
 		if err != nil {
 			w.logger.Error().Err(err).Msg("Failed to connect to vendor")
 			record.OnboardingStatus = OnboardingStatusConnectFailure
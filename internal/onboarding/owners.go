@@ -0,0 +1,89 @@
+package onboarding
+
+import (
+	"context"
+	"github.com/DIMO-Network/shared/pkg/logfields"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/riverqueue/river"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+const (
+	// VehicleOwnersSyncInterval how often the stored owners of minted VINs are compared with the on-chain owners
+	VehicleOwnersSyncInterval = 15 * time.Minute
+	// vehicleOwnersSyncPageSize minted VINs looked up in Identity API at once
+	vehicleOwnersSyncPageSize = 100
+)
+
+type VehicleOwnersSyncArgs struct{}
+
+func (a VehicleOwnersSyncArgs) Kind() string {
+	return "vehicle_owners_sync"
+}
+
+// VehicleOwnersSyncWorker stores the on-chain owner of minted VINs, so vehicles transferred to another wallet are
+// listed for it
+type VehicleOwnersSyncWorker struct {
+	logger   zerolog.Logger
+	identity service.IdentityAPI
+	vs       *service.Vehicle
+
+	river.WorkerDefaults[VehicleOwnersSyncArgs]
+}
+
+func NewVehicleOwnersSyncWorker(logger zerolog.Logger, identity service.IdentityAPI, vs *service.Vehicle) *VehicleOwnersSyncWorker {
+	return &VehicleOwnersSyncWorker{
+		logger:   logger,
+		identity: identity,
+		vs:       vs,
+	}
+}
+
+func (w *VehicleOwnersSyncWorker) Work(ctx context.Context, _ *river.Job[VehicleOwnersSyncArgs]) error {
+	var afterTokenID int64
+	updated := 0
+	for {
+		vins, err := w.vs.GetMintedVehiclesPage(ctx, afterTokenID, vehicleOwnersSyncPageSize)
+		if err != nil {
+			return err
+		}
+		if len(vins) == 0 {
+			break
+		}
+
+		tokenIDs := make([]int64, 0, len(vins))
+		for _, vin := range vins {
+			tokenIDs = append(tokenIDs, vin.VehicleTokenID.Int64)
+		}
+
+		identityVehicles, err := w.identity.FetchVehiclesByTokenIDs(ctx, tokenIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, vin := range vins {
+			identityVehicle, ok := identityVehicles[vin.VehicleTokenID.Int64]
+			if !ok || !common.IsHexAddress(identityVehicle.Owner) {
+				continue
+			}
+
+			owner := common.HexToAddress(identityVehicle.Owner)
+			if vin.OwnerAddress.Valid && common.HexToAddress(vin.OwnerAddress.String) == owner {
+				continue
+			}
+
+			if err := w.vs.SetVinOwner(ctx, vin.Vin, owner.Hex()); err != nil {
+				return err
+			}
+			w.logger.Debug().Str(logfields.VIN, vin.Vin).Str("owner", owner.Hex()).Msg("Stored on-chain owner of VIN")
+			updated++
+		}
+
+		afterTokenID = vins[len(vins)-1].VehicleTokenID.Int64
+	}
+
+	w.logger.Debug().Int("updated", updated).Msg("Synced owners of minted VINs")
+	return nil
+}
@@ -0,0 +1,34 @@
+package onboarding
+
+import (
+	"context"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/riverqueue/river"
+	"time"
+)
+
+// SessionEventsInterval how often sessions of VINs which stopped reporting are closed and session events DIS didn't
+// accept are sent again
+const SessionEventsInterval = time.Minute
+
+type SessionEventsArgs struct{}
+
+func (a SessionEventsArgs) Kind() string {
+	return "session_events"
+}
+
+type SessionEventsWorker struct {
+	oracle *service.OracleService
+
+	river.WorkerDefaults[SessionEventsArgs]
+}
+
+func NewSessionEventsWorker(oracle *service.OracleService) *SessionEventsWorker {
+	return &SessionEventsWorker{
+		oracle: oracle,
+	}
+}
+
+func (w *SessionEventsWorker) Work(ctx context.Context, _ *river.Job[SessionEventsArgs]) error {
+	return w.oracle.SendSessionEvents(ctx)
+}
@@ -12,6 +12,8 @@ const (
 	OnboardingStatusDecodingPending = 11
 	OnboardingStatusDecodingFailure = 12
 	OnboardingStatusDecodingSuccess = 13
+	// decoded, waiting for the device definition to show up in identity-api
+	OnboardingStatusDecodingDefinitionPending = 14
 
 	// 20-29 validation in external vendor system
 	OnboardingStatusVendorValidationUnknown = 20
@@ -67,50 +69,51 @@ const (
 )
 
 var statusToString = map[int]string{
-	OnboardingStatusSubmitUnknown:           "VerificationSubmitUnknown",
-	OnboardingStatusSubmitPending:           "VerificationSubmitPending",
-	OnboardingStatusSubmitFailure:           "VerificationSubmitFailure",
-	OnboardingStatusSubmitSuccess:           "VerificationSubmitSuccess",
-	OnboardingStatusDecodingUnknown:         "DecodingUnknown",
-	OnboardingStatusDecodingPending:         "DecodingPending",
-	OnboardingStatusDecodingFailure:         "DecodingFailure",
-	OnboardingStatusDecodingSuccess:         "DecodingSuccess",
-	OnboardingStatusVendorValidationUnknown: "VendorValidationUnknown",
-	OnboardingStatusVendorValidationPending: "VendorValidationPending",
-	OnboardingStatusVendorValidationFailure: "VendorValidationFailure",
-	OnboardingStatusVendorValidationSuccess: "VendorValidationSuccess",
-	OnboardingStatusMintSubmitUnknown:       "MintSubmitUnknown",
-	OnboardingStatusMintSubmitPending:       "MintSubmitPending",
-	OnboardingStatusMintSubmitFailure:       "MintSubmitFailure",
-	OnboardingStatusMintSubmitSuccess:       "MintSubmitSuccess",
-	OnboardingStatusConnectUnknown:          "ConnectUnknown",
-	OnboardingStatusConnectPending:          "ConnectPending",
-	OnboardingStatusConnectFailure:          "ConnectFailure",
-	OnboardingStatusConnectSuccess:          "ConnectSuccess",
-	OnboardingStatusMintUnknown:             "MintUnknown",
-	OnboardingStatusMintPending:             "MintPending",
-	OnboardingStatusMintFailure:             "MintFailure",
-	OnboardingStatusMintSuccess:             "MintSuccess",
-	OnboardingStatusDisconnectSubmitUnknown: "DisconnectSubmitUnknown",
-	OnboardingStatusDisconnectSubmitPending: "DisconnectSubmitPending",
-	OnboardingStatusDisconnectSubmitFailure: "DisconnectSubmitFailure",
-	OnboardingStatusDisconnectSubmitSuccess: "DisconnectSubmitSuccess",
-	OnboardingStatusDisconnectUnknown:       "DisconnectUnknown",
-	OnboardingStatusDisconnectPending:       "DisconnectPending",
-	OnboardingStatusDisconnectFailure:       "DisconnectFailure",
-	OnboardingStatusDisconnectSuccess:       "DisconnectSuccess",
-	OnboardingStatusBurnSDUnknown:           "BurnSDUnknown",
-	OnboardingStatusBurnSDPending:           "BurnSDPending",
-	OnboardingStatusBurnSDFailure:           "BurnSDFailure",
-	OnboardingStatusBurnSDSuccess:           "BurnSDSuccess",
-	OnboardingStatusDeleteSubmitUnknown:     "DeleteSubmitUnknown",
-	OnboardingStatusDeleteSubmitPending:     "DeleteSubmitPending",
-	OnboardingStatusDeleteSubmitFailure:     "DeleteSubmitFailure",
-	OnboardingStatusDeleteSubmitSuccess:     "DeleteSubmitSuccess",
-	OnboardingStatusBurnVehicleUnknown:      "BurnVehicleUnknown",
-	OnboardingStatusBurnVehiclePending:      "BurnVehiclePending",
-	OnboardingStatusBurnVehicleFailure:      "BurnVehicleFailure",
-	OnboardingStatusBurnVehicleSuccess:      "BurnVehicleSuccess",
+	OnboardingStatusSubmitUnknown:             "VerificationSubmitUnknown",
+	OnboardingStatusSubmitPending:             "VerificationSubmitPending",
+	OnboardingStatusSubmitFailure:             "VerificationSubmitFailure",
+	OnboardingStatusSubmitSuccess:             "VerificationSubmitSuccess",
+	OnboardingStatusDecodingUnknown:           "DecodingUnknown",
+	OnboardingStatusDecodingPending:           "DecodingPending",
+	OnboardingStatusDecodingFailure:           "DecodingFailure",
+	OnboardingStatusDecodingSuccess:           "DecodingSuccess",
+	OnboardingStatusDecodingDefinitionPending: "DecodingDefinitionPending",
+	OnboardingStatusVendorValidationUnknown:   "VendorValidationUnknown",
+	OnboardingStatusVendorValidationPending:   "VendorValidationPending",
+	OnboardingStatusVendorValidationFailure:   "VendorValidationFailure",
+	OnboardingStatusVendorValidationSuccess:   "VendorValidationSuccess",
+	OnboardingStatusMintSubmitUnknown:         "MintSubmitUnknown",
+	OnboardingStatusMintSubmitPending:         "MintSubmitPending",
+	OnboardingStatusMintSubmitFailure:         "MintSubmitFailure",
+	OnboardingStatusMintSubmitSuccess:         "MintSubmitSuccess",
+	OnboardingStatusConnectUnknown:            "ConnectUnknown",
+	OnboardingStatusConnectPending:            "ConnectPending",
+	OnboardingStatusConnectFailure:            "ConnectFailure",
+	OnboardingStatusConnectSuccess:            "ConnectSuccess",
+	OnboardingStatusMintUnknown:               "MintUnknown",
+	OnboardingStatusMintPending:               "MintPending",
+	OnboardingStatusMintFailure:               "MintFailure",
+	OnboardingStatusMintSuccess:               "MintSuccess",
+	OnboardingStatusDisconnectSubmitUnknown:   "DisconnectSubmitUnknown",
+	OnboardingStatusDisconnectSubmitPending:   "DisconnectSubmitPending",
+	OnboardingStatusDisconnectSubmitFailure:   "DisconnectSubmitFailure",
+	OnboardingStatusDisconnectSubmitSuccess:   "DisconnectSubmitSuccess",
+	OnboardingStatusDisconnectUnknown:         "DisconnectUnknown",
+	OnboardingStatusDisconnectPending:         "DisconnectPending",
+	OnboardingStatusDisconnectFailure:         "DisconnectFailure",
+	OnboardingStatusDisconnectSuccess:         "DisconnectSuccess",
+	OnboardingStatusBurnSDUnknown:             "BurnSDUnknown",
+	OnboardingStatusBurnSDPending:             "BurnSDPending",
+	OnboardingStatusBurnSDFailure:             "BurnSDFailure",
+	OnboardingStatusBurnSDSuccess:             "BurnSDSuccess",
+	OnboardingStatusDeleteSubmitUnknown:       "DeleteSubmitUnknown",
+	OnboardingStatusDeleteSubmitPending:       "DeleteSubmitPending",
+	OnboardingStatusDeleteSubmitFailure:       "DeleteSubmitFailure",
+	OnboardingStatusDeleteSubmitSuccess:       "DeleteSubmitSuccess",
+	OnboardingStatusBurnVehicleUnknown:        "BurnVehicleUnknown",
+	OnboardingStatusBurnVehiclePending:        "BurnVehiclePending",
+	OnboardingStatusBurnVehicleFailure:        "BurnVehicleFailure",
+	OnboardingStatusBurnVehicleSuccess:        "BurnVehicleSuccess",
 }
 
 func IsVerified(status int) bool {
@@ -129,6 +132,11 @@ func IsFailure(status int) bool {
 	return status%10 == 2
 }
 
+// IsVerificationFailure tells whether submitting, decoding or validating the VIN with the vendor failed
+func IsVerificationFailure(status int) bool {
+	return IsFailure(status) && status < OnboardingStatusMintSubmitUnknown
+}
+
 func IsPending(status int) bool {
 	return status > 0 && status < OnboardingStatusMintSuccess
 }
@@ -221,3 +229,61 @@ func GetDetailedStatus(status int) string {
 
 	return detailedStatus
 }
+
+// GetResetStatus returns the failure status of the stage the status belongs to, from which the stage can be submitted again.
+// Completed stages are not reset.
+func GetResetStatus(status int) (int, bool) {
+	switch {
+	case status == OnboardingStatusVendorValidationSuccess,
+		status == OnboardingStatusMintSuccess,
+		status == OnboardingStatusBurnSDSuccess,
+		status == OnboardingStatusBurnVehicleSuccess:
+		return status, false
+	case status < OnboardingStatusVendorValidationSuccess:
+		return OnboardingStatusSubmitFailure, true
+	case status < OnboardingStatusMintSuccess:
+		return OnboardingStatusMintFailure, true
+	case status < OnboardingStatusBurnSDSuccess:
+		return OnboardingStatusBurnSDFailure, true
+	default:
+		return OnboardingStatusBurnVehicleFailure, true
+	}
+}
+
+// GetStatus returns the Success / Failure / Pending / Unknown status of the stage the status belongs to
+func GetStatus(status int) string {
+	switch {
+	case status < OnboardingStatusMintSubmitUnknown:
+		return GetVerificationStatus(status)
+	case status < OnboardingStatusDisconnectSubmitUnknown:
+		return GetMintStatus(status)
+	case status < OnboardingStatusDeleteSubmitUnknown:
+		return GetDisconnectStatus(status)
+	default:
+		return GetBurnStatus(status)
+	}
+}
+
+// Onboarding phases, each one covers the statuses of its stages
+const (
+	OnboardingPhaseVerification = "verification"
+	OnboardingPhaseMint         = "mint"
+	OnboardingPhaseDisconnect   = "disconnect"
+	OnboardingPhaseDelete       = "delete"
+)
+
+// GetPhaseStatusRange returns the statuses of the phase, from minStatus up to but excluding maxStatus
+func GetPhaseStatusRange(phase string) (minStatus int, maxStatus int, ok bool) {
+	switch phase {
+	case OnboardingPhaseVerification:
+		return OnboardingStatusSubmitUnknown, OnboardingStatusMintSubmitUnknown, true
+	case OnboardingPhaseMint:
+		return OnboardingStatusMintSubmitUnknown, OnboardingStatusDisconnectSubmitUnknown, true
+	case OnboardingPhaseDisconnect:
+		return OnboardingStatusDisconnectSubmitUnknown, OnboardingStatusDeleteSubmitUnknown, true
+	case OnboardingPhaseDelete:
+		return OnboardingStatusDeleteSubmitUnknown, OnboardingStatusBurnVehicleSuccess + 1, true
+	default:
+		return 0, 0, false
+	}
+}
@@ -10,12 +10,19 @@ import (
 	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
 	"github.com/DIMO-Network/volteras-oracle/internal/models"
 	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/riverqueue/river"
 	"github.com/rs/zerolog"
+	"github.com/tidwall/gjson"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
+// ErrDefinitionLocked the device definition of the VIN can't change anymore, see CanOverrideDefinition
+var ErrDefinitionLocked = errors.New("device definition can't change once minting started")
+
 type VerifyArgs struct {
 	VIN         string `json:"vin"`
 	CountryCode string `json:"countryCode"`
@@ -41,11 +48,12 @@ type VerifyWorker struct {
 	dd       service.DeviceDefinitionsAPI
 	dbs      *db.Store
 	vendor   VendorOnboardingAPI
+	webhooks *service.Webhooks
 
 	river.WorkerDefaults[VerifyArgs]
 }
 
-func NewVerifyWorker(settings *config.Settings, logger zerolog.Logger, identity service.IdentityAPI, dd service.DeviceDefinitionsAPI, os *service.OracleService, dbs *db.Store, vendor VendorOnboardingAPI) *VerifyWorker {
+func NewVerifyWorker(settings *config.Settings, logger zerolog.Logger, identity service.IdentityAPI, dd service.DeviceDefinitionsAPI, os *service.OracleService, dbs *db.Store, vendor VendorOnboardingAPI, webhooks *service.Webhooks) *VerifyWorker {
 	return &VerifyWorker{
 		settings: settings,
 		logger:   logger,
@@ -54,11 +62,13 @@ func NewVerifyWorker(settings *config.Settings, logger zerolog.Logger, identity
 		dd:       dd,
 		dbs:      dbs,
 		vendor:   vendor,
+		webhooks: webhooks,
 	}
 }
 
 func (w *VerifyWorker) Work(ctx context.Context, job *river.Job[VerifyArgs]) error {
 	w.logger.Debug().Str(logfields.VIN, job.Args.VIN).Str(logfields.CountryCode, job.Args.CountryCode).Msg("Verifying VIN")
+	trace.SpanFromContext(ctx).SetAttributes(tracing.VIN(job.Args.VIN))
 
 	// Check if the VIN already exists, create the record if not
 	record, err := w.GetOrCreateVinRecord(ctx, job.Args)
@@ -72,17 +82,19 @@ func (w *VerifyWorker) Work(ctx context.Context, job *river.Job[VerifyArgs]) err
 		return nil
 	}
 
-	err = w.DecodeVinAndUpdate(record, job.Args)
+	err = w.DecodeVinAndUpdate(ctx, record, job.Args, jobSnoozes(job.Metadata))
 	if err != nil {
 		return err
 	}
 
 	// Validate with external Vendor
-	err = w.ValidateWithExternalVendorAndUpdate(record, job.Args)
+	err = w.ValidateWithExternalVendorAndUpdate(ctx, record, job.Args)
 	if err != nil {
 		return err
 	}
 
+	fireWebhookEvent(ctx, w.logger, w.webhooks, service.WebhookEventVehicleVerified, record)
+
 	return nil
 }
 
@@ -113,30 +125,35 @@ func (w *VerifyWorker) GetOrCreateVinRecord(ctx context.Context, args VerifyArgs
 	return vin, nil
 }
 
-func (w *VerifyWorker) DecodeVinAndUpdate(record *dbmodels.Vin, args VerifyArgs) error {
+// DecodeVinAndUpdate decodes the VIN and stores its device definition. Definitions of new vehicles take a while to
+// show up in identity-api, the job is snoozed until then, snoozes is the number of times it has been snoozed already.
+func (w *VerifyWorker) DecodeVinAndUpdate(ctx context.Context, record *dbmodels.Vin, args VerifyArgs, snoozes int) error {
 	// make sure we save status update (and possible new DD)
 	defer (func() { _ = w.update(record, args) })()
 
-	if record.OnboardingStatus < OnboardingStatusDecodingSuccess || record.DeviceDefinitionID.IsZero() || len(record.DeviceDefinitionID.String) == 0 {
+	if record.DefinitionOverriddenAt.Valid && record.DeviceDefinitionID.String != "" {
+		w.logger.Debug().Str(logfields.VIN, args.VIN).Str(logfields.DefinitionID, record.DeviceDefinitionID.String).Str("overriddenBy", record.DefinitionOverriddenBy.String).Msg("Device definition set manually, skipping decoding")
+	} else if record.OnboardingStatus == OnboardingStatusDecodingDefinitionPending && record.DeviceDefinitionID.String != "" {
+		// decoded by an earlier run of the job
+		if err := w.waitForDeviceDefinition(ctx, record, snoozes); err != nil {
+			return err
+		}
+	} else if record.OnboardingStatus < OnboardingStatusDecodingSuccess || record.DeviceDefinitionID.IsZero() || len(record.DeviceDefinitionID.String) == 0 {
 		w.logger.Debug().Str(logfields.VIN, args.VIN).Str(logfields.CountryCode, args.CountryCode).Msg("Decoding VIN")
 		record.OnboardingStatus = OnboardingStatusDecodingPending
 		_ = w.update(record, args)
 
-		decoded, err := w.dd.DecodeVin(args.VIN, args.CountryCode)
+		decoded, err := w.dd.DecodeVin(ctx, args.VIN, args.CountryCode)
 		if err != nil {
 			record.OnboardingStatus = OnboardingStatusDecodingFailure
 			return err
 		}
 		w.logger.Debug().Str(logfields.VIN, args.VIN).Str(logfields.CountryCode, args.CountryCode).Str(logfields.DefinitionID, decoded.DeviceDefinitionID).Msg("VIN decoded")
 
-		dd, err := w.getOrWaitForDeviceDefinition(decoded.DeviceDefinitionID)
-		if err != nil {
-			record.OnboardingStatus = OnboardingStatusDecodingFailure
+		record.DeviceDefinitionID = null.StringFrom(decoded.DeviceDefinitionID)
+		if err := w.waitForDeviceDefinition(ctx, record, snoozes); err != nil {
 			return err
 		}
-		w.logger.Debug().Str(logfields.VIN, args.VIN).Str(logfields.CountryCode, args.CountryCode).Str(logfields.DefinitionID, dd.DeviceDefinitionID).Interface("dd", dd).Msg("DD fetched from identity")
-
-		record.DeviceDefinitionID.SetValid(dd.DeviceDefinitionID)
 	} else {
 		w.logger.Debug().Str(logfields.VIN, args.VIN).Str(logfields.CountryCode, args.CountryCode).Str(logfields.DefinitionID, record.DeviceDefinitionID.String).Msg("VIN already decoded")
 	}
@@ -146,7 +163,7 @@ func (w *VerifyWorker) DecodeVinAndUpdate(record *dbmodels.Vin, args VerifyArgs)
 	return nil
 }
 
-func (w *VerifyWorker) ValidateWithExternalVendorAndUpdate(record *dbmodels.Vin, args VerifyArgs) error {
+func (w *VerifyWorker) ValidateWithExternalVendorAndUpdate(ctx context.Context, record *dbmodels.Vin, args VerifyArgs) error {
 	// make sure we save status update (and possible new DD)
 	defer (func() { _ = w.update(record, args) })()
 
@@ -155,7 +172,9 @@ func (w *VerifyWorker) ValidateWithExternalVendorAndUpdate(record *dbmodels.Vin,
 	record.OnboardingStatus = OnboardingStatusVendorValidationUnknown
 
 	if w.settings.EnableVendorCapabilityCheck {
+		done := startCall(ctx, tracing.PeerVendor, "validate", args.VIN)
 		validation, err := w.vendor.Validate([]string{args.VIN})
+		done(err)
 		if err != nil {
 			w.logger.Error().Err(err).Msg("Failed to validate VIN")
 			record.OnboardingStatus = OnboardingStatusVendorValidationFailure
@@ -181,19 +200,101 @@ func (w *VerifyWorker) ValidateWithExternalVendorAndUpdate(record *dbmodels.Vin,
 	return nil
 }
 
-func (w *VerifyWorker) getOrWaitForDeviceDefinition(deviceDefinitionID string) (*models.DeviceDefinition, error) {
-	w.logger.Debug().Str(logfields.DefinitionID, deviceDefinitionID).Msg("Waiting for device definition")
-	for i := 0; i < 12; i++ {
-		definition, err := w.identity.FetchDeviceDefinitionByID(deviceDefinitionID)
-		if err != nil || definition == nil || definition.DeviceDefinitionID == "" {
-			time.Sleep(5 * time.Second)
-			w.logger.Debug().Str(logfields.DefinitionID, deviceDefinitionID).Msgf("Still waiting, retry %d", i+1)
-			continue
-		}
-		return definition, nil
+// waitForDeviceDefinition sets the decoded device definition of the VIN once identity-api knows it. Until then the
+// job is snoozed with a growing delay, so it doesn't hold a worker, decoding fails when the wait deadline is reached.
+func (w *VerifyWorker) waitForDeviceDefinition(ctx context.Context, record *dbmodels.Vin, snoozes int) error {
+	definitionID := record.DeviceDefinitionID.String
+
+	dd, err := w.identity.FetchDeviceDefinitionByID(ctx, definitionID)
+	if err == nil && dd != nil && dd.DeviceDefinitionID != "" {
+		w.logger.Debug().Str(logfields.VIN, record.Vin).Str(logfields.DefinitionID, dd.DeviceDefinitionID).Interface("dd", dd).Msg("DD fetched from identity")
+		setDefinition(record, dd)
+		return nil
+	}
+
+	backoff, ok := definitionWaitBackoff(w.definitionWaitInitial(), w.definitionWaitDeadline(), snoozes)
+	if !ok {
+		w.logger.Error().Err(err).Str(logfields.VIN, record.Vin).Str(logfields.DefinitionID, definitionID).Int("snoozes", snoozes).Msg("Device definition not found in time")
+		record.OnboardingStatus = OnboardingStatusDecodingFailure
+		return errors.New("device definition not found")
+	}
+
+	w.logger.Debug().Str(logfields.VIN, record.Vin).Str(logfields.DefinitionID, definitionID).Int("snoozes", snoozes).Dur("backoff", backoff).Msg("Waiting for device definition")
+	record.OnboardingStatus = OnboardingStatusDecodingDefinitionPending
+	return river.JobSnooze(backoff)
+}
+
+func (w *VerifyWorker) definitionWaitDeadline() time.Duration {
+	if w.settings.DefinitionWaitSeconds <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(w.settings.DefinitionWaitSeconds) * time.Second
+}
+
+func (w *VerifyWorker) definitionWaitInitial() time.Duration {
+	if w.settings.DefinitionWaitBackoffSeconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(w.settings.DefinitionWaitBackoffSeconds) * time.Second
+}
+
+// definitionWaitBackoff delay before the next check for the device definition, after the given number of snoozes.
+// The delay doubles every time and the last one is cut to the deadline, false is returned once it is reached.
+func definitionWaitBackoff(initial, deadline time.Duration, snoozes int) (time.Duration, bool) {
+	waited, backoff := time.Duration(0), initial
+	for i := 0; i < snoozes; i++ {
+		waited += backoff
+		backoff *= 2
+	}
+
+	if waited >= deadline {
+		return 0, false
 	}
 
-	return nil, errors.New("device definition not found")
+	return min(backoff, deadline-waited), true
+}
+
+// jobSnoozes number of times River has snoozed the job, kept in its metadata
+func jobSnoozes(metadata []byte) int {
+	return int(gjson.GetBytes(metadata, "snoozes").Int())
+}
+
+// setDefinition stores the definition of the VIN, vehicles are filtered and sorted by its make, model and year
+func setDefinition(record *dbmodels.Vin, dd *models.DeviceDefinition) {
+	record.DeviceDefinitionID = null.StringFrom(dd.DeviceDefinitionID)
+	record.DefinitionMake = null.StringFrom(dd.Manufacturer.Name)
+	record.DefinitionModel = null.StringFrom(dd.Model)
+	record.DefinitionYear = null.IntFrom(dd.Year)
+}
+
+// CanOverrideDefinition tells whether the device definition of the VIN can still change, it's fixed once minting
+// started since the vehicle NFT carries it
+func CanOverrideDefinition(record *dbmodels.Vin) bool {
+	return !record.VehicleTokenID.Valid && record.OnboardingStatus < OnboardingStatusMintSubmitUnknown
+}
+
+// OverrideDefinition sets the device definition picked by the owner or an admin, later decoding doesn't replace it.
+// VINs which failed verification resume at vendor validation, true is returned when they need a new verify job.
+// Records past CanOverrideDefinition are left unchanged and ErrDefinitionLocked is returned.
+func OverrideDefinition(record *dbmodels.Vin, dd *models.DeviceDefinition, by string) (bool, error) {
+	if !CanOverrideDefinition(record) {
+		return false, ErrDefinitionLocked
+	}
+
+	record.DeviceDefinitionID = null.StringFrom(dd.DeviceDefinitionID)
+	// missing ones are stored when the vehicle is minted
+	record.DefinitionMake = null.NewString(dd.Manufacturer.Name, dd.Manufacturer.Name != "")
+	record.DefinitionModel = null.NewString(dd.Model, dd.Model != "")
+	record.DefinitionYear = null.NewInt(dd.Year, dd.Year != 0)
+	record.DefinitionOverriddenBy = null.StringFrom(by)
+	record.DefinitionOverriddenAt = null.TimeFrom(time.Now())
+
+	if !IsVerificationFailure(record.OnboardingStatus) {
+		return false, nil
+	}
+
+	record.OnboardingStatus = OnboardingStatusDecodingSuccess
+	return true, nil
 }
 
 func (w *VerifyWorker) update(record *dbmodels.Vin, args VerifyArgs) error {
@@ -0,0 +1,91 @@
+package onboarding
+
+import (
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/stretchr/testify/suite"
+	"github.com/volatiletech/null/v8"
+	"testing"
+	"time"
+)
+
+type VerifyTestSuite struct {
+	suite.Suite
+}
+
+func TestVerifyTestSuite(t *testing.T) {
+	suite.Run(t, new(VerifyTestSuite))
+}
+
+func (s *VerifyTestSuite) TestOverrideDefinition() {
+	dd := &models.DeviceDefinition{DeviceDefinitionID: "ford_f-150_2022", Manufacturer: models.Manufacturer{Name: "Ford"}, Model: "F-150", Year: 2022}
+
+	for _, status := range []int{OnboardingStatusSubmitFailure, OnboardingStatusDecodingFailure, OnboardingStatusVendorValidationFailure} {
+		record := &dbmodels.Vin{Vin: "1HGCM82633A004352", OnboardingStatus: status}
+		resume, err := OverrideDefinition(record, dd, "support@example.com")
+		s.Require().NoError(err)
+		s.True(resume, status)
+		s.Equal(OnboardingStatusDecodingSuccess, record.OnboardingStatus)
+		s.Equal("ford_f-150_2022", record.DeviceDefinitionID.String)
+		s.Equal("Ford", record.DefinitionMake.String)
+		s.Equal(2022, record.DefinitionYear.Int)
+		s.Equal("support@example.com", record.DefinitionOverriddenBy.String)
+		s.True(record.DefinitionOverriddenAt.Valid)
+	}
+
+	// running stages keep their status
+	for _, status := range []int{OnboardingStatusDecodingPending, OnboardingStatusVendorValidationSuccess} {
+		record := &dbmodels.Vin{Vin: "1HGCM82633A004352", OnboardingStatus: status}
+		resume, err := OverrideDefinition(record, dd, "support@example.com")
+		s.Require().NoError(err)
+		s.False(resume, status)
+		s.Equal(status, record.OnboardingStatus)
+		s.Equal("ford_f-150_2022", record.DeviceDefinitionID.String)
+	}
+
+	// the definition is fixed once minting started, records are left unchanged
+	for _, record := range []*dbmodels.Vin{
+		{Vin: "1HGCM82633A004352", OnboardingStatus: OnboardingStatusMintSubmitUnknown},
+		{Vin: "1HGCM82633A004352", OnboardingStatus: OnboardingStatusMintFailure},
+		{Vin: "1HGCM82633A004352", OnboardingStatus: OnboardingStatusDecodingFailure, VehicleTokenID: null.Int64From(7)},
+	} {
+		status := record.OnboardingStatus
+		resume, err := OverrideDefinition(record, dd, "support@example.com")
+		s.ErrorIs(err, ErrDefinitionLocked)
+		s.False(resume)
+		s.Equal(status, record.OnboardingStatus)
+		s.False(record.DeviceDefinitionID.Valid)
+		s.False(record.DefinitionOverriddenBy.Valid)
+	}
+
+	// definitions set by ID only leave make, model and year to minting
+	record := &dbmodels.Vin{Vin: "1HGCM82633A004352", OnboardingStatus: OnboardingStatusDecodingFailure}
+	_, err := OverrideDefinition(record, &models.DeviceDefinition{DeviceDefinitionID: "ford_f-150_2022"}, "support@example.com")
+	s.Require().NoError(err)
+	s.False(record.DefinitionMake.Valid)
+	s.False(record.DefinitionModel.Valid)
+	s.False(record.DefinitionYear.Valid)
+}
+
+func (s *VerifyTestSuite) TestDefinitionWaitBackoff() {
+	var backoffs []time.Duration
+	for snoozes := 0; ; snoozes++ {
+		backoff, ok := definitionWaitBackoff(5*time.Second, 60*time.Second, snoozes)
+		if !ok {
+			break
+		}
+		backoffs = append(backoffs, backoff)
+	}
+
+	// the last one is cut to the deadline
+	s.Equal([]time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 25 * time.Second}, backoffs)
+
+	_, ok := definitionWaitBackoff(5*time.Second, 5*time.Second, 1)
+	s.False(ok)
+}
+
+func (s *VerifyTestSuite) TestJobSnoozes() {
+	s.Equal(0, jobSnoozes(nil))
+	s.Equal(0, jobSnoozes([]byte(`{}`)))
+	s.Equal(3, jobSnoozes([]byte(`{"snoozes": 3}`)))
+}
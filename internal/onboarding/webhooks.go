@@ -0,0 +1,143 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DIMO-Network/shared/pkg/logfields"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/service"
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+const (
+	webhookDeliveryMaxAttempts = 10
+	webhookRetryBaseDelay      = 30 * time.Second
+	webhookRetryMaxDelay       = 6 * time.Hour
+)
+
+type WebhookDeliveryArgs struct {
+	DeliveryID string `json:"deliveryId"`
+}
+
+func (a WebhookDeliveryArgs) Kind() string {
+	return "webhook_delivery"
+}
+
+func (WebhookDeliveryArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		MaxAttempts: webhookDeliveryMaxAttempts,
+	}
+}
+
+// WebhookVehicleData is the data of every vehicle lifecycle event
+type WebhookVehicleData struct {
+	Vin              string `json:"vin"`
+	Status           string `json:"status"`
+	Details          string `json:"details"`
+	VehicleTokenID   *int64 `json:"vehicleTokenId,omitempty"`
+	SyntheticTokenID *int64 `json:"syntheticTokenId,omitempty"`
+}
+
+type WebhookDeliveryWorker struct {
+	logger   zerolog.Logger
+	webhooks *service.Webhooks
+
+	river.WorkerDefaults[WebhookDeliveryArgs]
+}
+
+func NewWebhookDeliveryWorker(logger zerolog.Logger, webhooks *service.Webhooks) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		logger:   logger,
+		webhooks: webhooks,
+	}
+}
+
+func (w *WebhookDeliveryWorker) Timeout(*river.Job[WebhookDeliveryArgs]) time.Duration {
+	return time.Minute
+}
+
+// NextRetry backs off exponentially from 30s, capped at 6h, so a receiver can be down for about a day
+func (w *WebhookDeliveryWorker) NextRetry(job *river.Job[WebhookDeliveryArgs]) time.Time {
+	return time.Now().Add(webhookRetryDelay(job.Attempt))
+}
+
+func (w *WebhookDeliveryWorker) Work(ctx context.Context, job *river.Job[WebhookDeliveryArgs]) error {
+	err := w.webhooks.Deliver(ctx, job.Args.DeliveryID)
+	if err != nil {
+		w.logger.Warn().Err(err).Str("deliveryId", job.Args.DeliveryID).Int("attempt", job.Attempt).Msg("Webhook delivery failed")
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			// delivery or subscription is gone, no point in retrying
+			return river.JobCancel(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempt && delay < webhookRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookRetryMaxDelay)
+}
+
+// fireWebhookEvent queues deliveries of the event to the webhook subscriptions of the developer client the VIN was
+// submitted through. Failures are only logged, webhooks must never fail the onboarding step itself.
+func fireWebhookEvent(ctx context.Context, logger zerolog.Logger, webhooks *service.Webhooks, event string, record *dbmodels.Vin) {
+	if webhooks == nil || !record.ClientID.Valid {
+		return
+	}
+
+	client, err := river.ClientFromContextSafely[pgx.Tx](ctx)
+	if err != nil {
+		logger.Error().Err(err).Str(logfields.VIN, record.Vin).Msgf("Failed to queue %s webhook deliveries", event)
+		return
+	}
+
+	FireWebhookEvent(ctx, logger, webhooks, QueueWebhookDeliveries(client), event, record)
+}
+
+// FireWebhookEvent queues deliveries of the event with the queue, for events outside of river workers, see
+// fireWebhookEvent
+func FireWebhookEvent(ctx context.Context, logger zerolog.Logger, webhooks *service.Webhooks, queue service.QueueWebhookDeliveries, event string, record *dbmodels.Vin) {
+	if webhooks == nil || !record.ClientID.Valid {
+		return
+	}
+
+	data := WebhookVehicleData{
+		Vin:     record.Vin,
+		Status:  GetStatus(record.OnboardingStatus),
+		Details: GetDetailedStatus(record.OnboardingStatus),
+	}
+	if record.VehicleTokenID.Valid {
+		data.VehicleTokenID = &record.VehicleTokenID.Int64
+	}
+	if record.SyntheticTokenID.Valid {
+		data.SyntheticTokenID = &record.SyntheticTokenID.Int64
+	}
+
+	if _, err := webhooks.CreateDeliveries(ctx, record.ClientID.String, event, record.Vin, data, queue); err != nil {
+		logger.Error().Err(err).Str(logfields.VIN, record.Vin).Msgf("Failed to create %s webhook deliveries", event)
+	}
+}
+
+// QueueWebhookDeliveries inserts the delivery jobs in the transaction storing the deliveries
+func QueueWebhookDeliveries(client *river.Client[pgx.Tx]) service.QueueWebhookDeliveries {
+	return func(ctx context.Context, tx pgx.Tx, deliveries dbmodels.WebhookDeliverySlice) error {
+		params := make([]river.InsertManyParams, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			params = append(params, river.InsertManyParams{Args: WebhookDeliveryArgs{DeliveryID: delivery.ID}})
+		}
+
+		if _, err := client.InsertManyTx(ctx, tx, params); err != nil {
+			return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+		}
+		return nil
+	}
+}
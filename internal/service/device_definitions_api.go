@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	shttp "github.com/DIMO-Network/shared/pkg/http"
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/rs/zerolog"
 	"io"
 	"net/url"
@@ -13,7 +15,7 @@ import (
 )
 
 type DeviceDefinitionsAPI interface {
-	DecodeVin(vin, countryCode string) (*DecodeVinResponse, error)
+	DecodeVin(ctx context.Context, vin, countryCode string) (*DecodeVinResponse, error)
 }
 
 type DeviceDefinitionsAPIService struct {
@@ -46,7 +48,7 @@ type DecodeVinResponse struct {
 	NewTransactionHash string `json:"newTransactionHash"`
 }
 
-func (d *DeviceDefinitionsAPIService) DecodeVin(vin, countryCode string) (*DecodeVinResponse, error) {
+func (d *DeviceDefinitionsAPIService) DecodeVin(ctx context.Context, vin, countryCode string) (*DecodeVinResponse, error) {
 	token := d.auth.GetToken()
 	if token == nil {
 		d.logger.Error().Msg("Failed to get token")
@@ -67,7 +69,9 @@ func (d *DeviceDefinitionsAPIService) DecodeVin(vin, countryCode string) (*Decod
 		return nil, err
 	}
 
+	_, span := tracing.StartClientSpan(ctx, tracing.PeerDeviceDefinitionsAPI, "decodeVin", tracing.VIN(vin))
 	resp, err := hcw.ExecuteRequest("/device-definitions/decode-vin", "POST", payloadBytes)
+	tracing.End(span, err)
 	if err != nil {
 		d.logger.Err(err).Msg("Failed to send DecodeVIN POST request")
 		return nil, err
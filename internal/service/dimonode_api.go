@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -8,15 +9,20 @@ import (
 	"github.com/DIMO-Network/cloudevent"
 	shttp "github.com/DIMO-Network/shared/pkg/http"
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/time/rate"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 type DimoNodeAPI interface {
-	SendToDimoNode(event *cloudevent.CloudEvent[json.RawMessage]) (interface{}, error)
+	SendToDimoNode(ctx context.Context, event *cloudevent.CloudEvent[json.RawMessage]) (interface{}, error)
 }
 
 type dimoNodeService struct {
@@ -70,8 +76,21 @@ func initTLSClient(logger zerolog.Logger, settings config.Settings) (*http.Trans
 	return transport, nil
 }
 
+// DimoNodeHealthCheck completes a mutual TLS handshake with the Dimo Node, failing when it is unreachable or rejects
+// the client certificate.
+func DimoNodeHealthCheck(logger zerolog.Logger, settings config.Settings) HealthCheck {
+	transport, err := initTLSClient(logger, settings)
+	if err != nil {
+		return func(_ context.Context) error {
+			return err
+		}
+	}
+
+	return TLSHealthCheck(settings.DimoNodeEndpoint, transport.TLSClientConfig)
+}
+
 // SendToDimoNode sends a CloudEvent to the Dimo Node API.
-func (d *dimoNodeService) SendToDimoNode(event *cloudevent.CloudEvent[json.RawMessage]) (interface{}, error) {
+func (d *dimoNodeService) SendToDimoNode(ctx context.Context, event *cloudevent.CloudEvent[json.RawMessage]) (interface{}, error) {
 	// Marshal the event into JSON
 	payloadBytes, err := json.Marshal(event)
 	if err != nil {
@@ -80,7 +99,15 @@ func (d *dimoNodeService) SendToDimoNode(event *cloudevent.CloudEvent[json.RawMe
 	}
 
 	d.logger.Debug().Msgf("Sending request to Dimo Node : %s", d.apiURL)
+	_, span := tracing.StartClientSpan(ctx, tracing.PeerDimoNode, "send", attribute.String("cloudevent.type", event.Type))
+	start := time.Now()
 	resp, err := d.httpClient.ExecuteRequest(d.apiURL, http.MethodPost, payloadBytes)
+	tracing.End(span, err)
+	statusCode := "none" // no response, eg. connection or TLS errors
+	if resp != nil {
+		statusCode = strconv.Itoa(resp.StatusCode)
+	}
+	disRequestDuration.WithLabelValues(statusCode).Observe(time.Since(start).Seconds())
 	if err != nil {
 		// HTTPClientWrapper treats all 4xx status codes as errors, so we need to handle them here
 		d.logger.Err(err).Msg("Failed to send POST request")
@@ -127,3 +154,9 @@ func (d *dimoNodeService) SendToDimoNode(event *cloudevent.CloudEvent[json.RawMe
 
 	return resp.StatusCode, nil
 }
+
+var disRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "dimo_oracle_dis_request_duration_seconds",
+	Help:    "Duration of requests to the Dimo Node by response status code, including retries",
+	Buckets: prometheus.DefBuckets,
+}, []string{"status_code"})
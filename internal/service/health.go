@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/DIMO-Network/shared/pkg/db"
+	"github.com/friendsofgo/errors"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusWarn = "warn"
+	HealthStatusFail = "fail"
+
+	healthCheckTimeout = 5 * time.Second
+)
+
+// ErrHealthWarning marks errors of components that still work but need attention, they don't fail the report
+var ErrHealthWarning = errors.New("warning")
+
+// HealthCheck returns an error when the component is not working
+type HealthCheck func(ctx context.Context) error
+
+// ComponentHealth is the result of the last check of a component
+type ComponentHealth struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// HealthReport is the status of the components, it fails when any of them fails
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+type healthComponent struct {
+	name     string
+	liveness bool
+	check    HealthCheck
+}
+
+// HealthChecker checks the components the oracle depends on. Results are cached, so probes don't hit the
+// external services on every request.
+type HealthChecker struct {
+	ttl time.Duration
+	// guards the components and results, components can be registered while the monitoring server already reports
+	mu         sync.Mutex
+	components []healthComponent
+	results    map[string]ComponentHealth
+}
+
+// NewHealthChecker creates a new instance of HealthChecker, check results are reused for the ttl.
+func NewHealthChecker(ttl time.Duration) *HealthChecker {
+	return &HealthChecker{
+		ttl:     ttl,
+		results: make(map[string]ComponentHealth),
+	}
+}
+
+// Register adds a component. Liveness components are the ones the process can't recover from without a restart,
+// they are checked by both liveness and readiness, the others only by readiness.
+func (h *HealthChecker) Register(name string, liveness bool, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.components = append(h.components, healthComponent{
+		name:     name,
+		liveness: liveness,
+		check:    check,
+	})
+}
+
+// Liveness checks the liveness components
+func (h *HealthChecker) Liveness(ctx context.Context) HealthReport {
+	return h.report(ctx, true)
+}
+
+// Readiness checks all components
+func (h *HealthChecker) Readiness(ctx context.Context) HealthReport {
+	return h.report(ctx, false)
+}
+
+func (h *HealthChecker) report(ctx context.Context, livenessOnly bool) HealthReport {
+	report := HealthReport{
+		Status:     HealthStatusOK,
+		Components: make(map[string]ComponentHealth),
+	}
+
+	h.mu.Lock()
+	components := slices.Clone(h.components)
+	h.mu.Unlock()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, component := range components {
+		if livenessOnly && !component.liveness {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := h.check(ctx, component)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[component.name] = result
+			if result.Status == HealthStatusFail {
+				report.Status = HealthStatusFail
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (h *HealthChecker) check(ctx context.Context, component healthComponent) ComponentHealth {
+	h.mu.Lock()
+	cached, ok := h.results[component.name]
+	h.mu.Unlock()
+	if ok && time.Since(cached.CheckedAt) < h.ttl {
+		return cached
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	result := ComponentHealth{
+		Status:    HealthStatusOK,
+		CheckedAt: time.Now(),
+	}
+	if err := component.check(checkCtx); err != nil {
+		result.Status = HealthStatusFail
+		if errors.Is(err, ErrHealthWarning) {
+			result.Status = HealthStatusWarn
+		}
+		result.Error = err.Error()
+	}
+
+	h.mu.Lock()
+	h.results[component.name] = result
+	h.mu.Unlock()
+
+	return result
+}
+
+// DBHealthCheck pings the database
+func DBHealthCheck(pdb *db.Store) HealthCheck {
+	return func(ctx context.Context) error {
+		if !pdb.IsReady() {
+			return errors.New("database is not ready")
+		}
+
+		return pdb.DBS().Writer.PingContext(ctx)
+	}
+}
+
+// WarningHealthCheck reports the errors of the check as warnings, for dependencies which only degrade some routes
+// and shouldn't take the service out of the load balancer
+func WarningHealthCheck(check HealthCheck) HealthCheck {
+	return func(ctx context.Context) error {
+		err := check(ctx)
+		if err == nil || errors.Is(err, ErrHealthWarning) {
+			return err
+		}
+
+		return errors.Wrap(ErrHealthWarning, err.Error())
+	}
+}
+
+// HTTPHealthCheck checks the service answers at the URL, any response below 500 means it is reachable
+func HTTPHealthCheck(client *http.Client, method string, endpoint url.URL) HealthCheck {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("received status code: %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+}
+
+// TLSHealthCheck completes a TLS handshake with the host of the endpoint, presenting the client certificate
+func TLSHealthCheck(endpoint string, tlsConfig *tls.Config) HealthCheck {
+	return func(ctx context.Context) error {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return err
+		}
+
+		address := u.Host
+		if u.Port() == "" {
+			address = net.JoinHostPort(u.Hostname(), "443")
+		}
+
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+}
+
+// CertificateHealthCheck fails when the PEM certificate expired and warns when it expires within the margin, so it
+// is renewed in time
+func CertificateHealthCheck(certPEM string, margin time.Duration) HealthCheck {
+	return func(_ context.Context) error {
+		block, _ := pem.Decode([]byte(certPEM))
+		if block == nil {
+			return errors.New("no PEM certificate")
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if now.After(cert.NotAfter) {
+			return fmt.Errorf("certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+		}
+		if now.Add(margin).After(cert.NotAfter) {
+			return errors.Wrapf(ErrHealthWarning, "certificate expires at %s", cert.NotAfter.Format(time.RFC3339))
+		}
+
+		return nil
+	}
+}
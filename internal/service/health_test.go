@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"github.com/friendsofgo/errors"
+	"github.com/stretchr/testify/suite"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type HealthCheckerTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestHealthCheckerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthCheckerTestSuite))
+}
+
+func (s *HealthCheckerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+}
+
+func (s *HealthCheckerTestSuite) TestReport() {
+	checker := NewHealthChecker(time.Minute)
+	checker.Register("river", true, func(_ context.Context) error { return nil })
+	checker.Register("database", false, func(_ context.Context) error { return errors.New("connection refused") })
+	checker.Register("certificate", false, func(_ context.Context) error { return errors.Wrap(ErrHealthWarning, "expires soon") })
+
+	liveness := checker.Liveness(s.ctx)
+	s.Equal(HealthStatusOK, liveness.Status)
+	s.Len(liveness.Components, 1)
+	s.Equal(HealthStatusOK, liveness.Components["river"].Status)
+
+	readiness := checker.Readiness(s.ctx)
+	s.Equal(HealthStatusFail, readiness.Status)
+	s.Len(readiness.Components, 3)
+	s.Equal(HealthStatusFail, readiness.Components["database"].Status)
+	s.Equal("connection refused", readiness.Components["database"].Error)
+	s.Equal(HealthStatusWarn, readiness.Components["certificate"].Status)
+}
+
+func (s *HealthCheckerTestSuite) TestCachedResults() {
+	var calls atomic.Int32
+	check := func(_ context.Context) error {
+		calls.Add(1)
+		return nil
+	}
+
+	checker := NewHealthChecker(time.Minute)
+	checker.Register("identityApi", false, check)
+	checker.Readiness(s.ctx)
+	checker.Readiness(s.ctx)
+	s.Equal(int32(1), calls.Load())
+
+	uncached := NewHealthChecker(0)
+	uncached.Register("identityApi", false, check)
+	uncached.Readiness(s.ctx)
+	uncached.Readiness(s.ctx)
+	s.Equal(int32(3), calls.Load())
+}
+
+func (s *HealthCheckerTestSuite) TestRegisterWhileReporting() {
+	checker := NewHealthChecker(0)
+	checker.Register("river", true, func(_ context.Context) error { return nil })
+
+	// consumers are registered after the monitoring server started, run with -race
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 50 {
+			checker.Register(fmt.Sprintf("kafka:%d", i), true, func(_ context.Context) error { return nil })
+		}
+	}()
+	for range 50 {
+		checker.Liveness(s.ctx)
+	}
+	wg.Wait()
+
+	s.Len(checker.Liveness(s.ctx).Components, 51)
+}
+
+func (s *HealthCheckerTestSuite) TestHTTPHealthCheck() {
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	endpoint, err := url.Parse(server.URL)
+	s.Require().NoError(err)
+	check := HTTPHealthCheck(server.Client(), http.MethodGet, *endpoint)
+
+	// reachable, even when the request itself is rejected
+	s.NoError(check(s.ctx))
+
+	status = http.StatusBadGateway
+	s.Error(check(s.ctx))
+}
+
+func (s *HealthCheckerTestSuite) TestWarningHealthCheck() {
+	s.NoError(WarningHealthCheck(func(_ context.Context) error { return nil })(s.ctx))
+
+	err := WarningHealthCheck(func(_ context.Context) error { return errors.New("connection refused") })(s.ctx)
+	s.ErrorIs(err, ErrHealthWarning)
+	s.Contains(err.Error(), "connection refused")
+
+	warning := errors.Wrap(ErrHealthWarning, "expires soon")
+	s.Equal(warning, WarningHealthCheck(func(_ context.Context) error { return warning })(s.ctx))
+
+	checker := NewHealthChecker(0)
+	checker.Register("identityApi", false, WarningHealthCheck(func(_ context.Context) error { return errors.New("timeout") }))
+	readiness := checker.Readiness(s.ctx)
+	s.Equal(HealthStatusOK, readiness.Status)
+	s.Equal(HealthStatusWarn, readiness.Components["identityApi"].Status)
+}
+
+func (s *HealthCheckerTestSuite) TestCertificateHealthCheck() {
+	margin := 14 * 24 * time.Hour
+
+	s.NoError(CertificateHealthCheck(s.certificate(time.Now().Add(30*24*time.Hour)), margin)(s.ctx))
+
+	err := CertificateHealthCheck(s.certificate(time.Now().Add(24*time.Hour)), margin)(s.ctx)
+	s.ErrorIs(err, ErrHealthWarning)
+
+	err = CertificateHealthCheck(s.certificate(time.Now().Add(-time.Hour)), margin)(s.ctx)
+	s.Error(err)
+	s.NotErrorIs(err, ErrHealthWarning)
+
+	s.Error(CertificateHealthCheck("not a certificate", margin)(s.ctx))
+}
+
+func (s *HealthCheckerTestSuite) certificate(notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "oracle"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.Require().NoError(err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
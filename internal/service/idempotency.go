@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"github.com/DIMO-Network/shared/pkg/db"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/friendsofgo/errors"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"sync"
+	"time"
+)
+
+// how long the response of a request is replayed for the same idempotency key
+const idempotencyKeyTTL = 24 * time.Hour
+
+// how long a request holds the key unless the lease is extended, retries take the key over afterwards, eg. when the
+// instance processing it died
+const defaultIdempotencyKeyLease = time.Minute
+
+const pqUniqueViolation = "23505"
+
+var (
+	ErrIdempotencyKeyMismatch   = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with the idempotency key is still in progress")
+	ErrIdempotencyKeyLeaseLost  = errors.New("idempotency key was taken over by another request")
+)
+
+// IdempotencyKeys stores the responses of mutating requests sent with an idempotency key,
+// so a retried request is answered with the first response instead of being processed again.
+type IdempotencyKeys struct {
+	pdb    *db.Store
+	logger *zerolog.Logger
+	lease  time.Duration
+}
+
+// NewIdempotencyKeysService creates a new instance of IdempotencyKeys. Requests hold their key for the lease, which
+// is extended while they are processed, see KeepLease.
+func NewIdempotencyKeysService(pdb *db.Store, logger *zerolog.Logger, lease time.Duration) *IdempotencyKeys {
+	if lease <= 0 {
+		lease = defaultIdempotencyKeyLease
+	}
+
+	return &IdempotencyKeys{
+		pdb:    pdb,
+		logger: logger,
+		lease:  lease,
+	}
+}
+
+// HashIdempotentRequest computes the hex SHA-256 of the request a key was first used with
+func HashIdempotentRequest(method, uri string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(uri))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin reserves the key of the scope for the request. A key which was already used for the same request
+// is returned with its stored response. A key still in progress is taken over once its lease ended, and is returned
+// without a response.
+func (ik *IdempotencyKeys) Begin(ctx context.Context, scope, key, requestHash string) (*dbmodels.IdempotencyKey, error) {
+	// an expired key can be used again
+	if _, err := dbmodels.IdempotencyKeys(
+		dbmodels.IdempotencyKeyWhere.Scope.EQ(scope),
+		dbmodels.IdempotencyKeyWhere.Key.EQ(key),
+		dbmodels.IdempotencyKeyWhere.ExpiresAt.LT(time.Now()),
+	).DeleteAll(ctx, ik.pdb.DBS().Writer); err != nil {
+		ik.logger.Error().Err(err).Msgf("Failed to delete expired idempotency key %s", key)
+		return nil, err
+	}
+
+	record := dbmodels.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().Add(idempotencyKeyTTL),
+		LockedUntil: null.TimeFrom(ik.leaseEnd()),
+	}
+
+	err := record.Insert(ctx, ik.pdb.DBS().Writer, boil.Infer())
+	if err == nil {
+		return &record, nil
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != pqUniqueViolation {
+		ik.logger.Error().Err(err).Msgf("Failed to insert idempotency key %s", key)
+		return nil, err
+	}
+
+	existing, err := dbmodels.FindIdempotencyKey(ctx, ik.pdb.DBS().Writer, scope, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// released by the concurrent request in the meantime
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		ik.logger.Error().Err(err).Msgf("Failed to get idempotency key %s", key)
+		return nil, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+
+	if !existing.ResponseCode.Valid {
+		return ik.takeOver(ctx, existing)
+	}
+
+	return existing, nil
+}
+
+// takeOver reserves a key in progress whose lease ended for the request, only one of concurrent retries gets it
+func (ik *IdempotencyKeys) takeOver(ctx context.Context, existing *dbmodels.IdempotencyKey) (*dbmodels.IdempotencyKey, error) {
+	now := time.Now()
+	lockedUntil := ik.leaseEnd()
+
+	taken, err := dbmodels.IdempotencyKeys(
+		dbmodels.IdempotencyKeyWhere.Scope.EQ(existing.Scope),
+		dbmodels.IdempotencyKeyWhere.Key.EQ(existing.Key),
+		dbmodels.IdempotencyKeyWhere.ResponseCode.IsNull(),
+		qm.Expr(
+			dbmodels.IdempotencyKeyWhere.LockedUntil.IsNull(),
+			qm.Or2(dbmodels.IdempotencyKeyWhere.LockedUntil.LT(null.TimeFrom(now))),
+		),
+	).UpdateAll(ctx, ik.pdb.DBS().Writer, dbmodels.M{dbmodels.IdempotencyKeyColumns.LockedUntil: lockedUntil})
+	if err != nil {
+		ik.logger.Error().Err(err).Msgf("Failed to take over idempotency key %s", existing.Key)
+		return nil, err
+	}
+	if taken == 0 {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	ik.logger.Info().Msgf("Took over idempotency key %s of a request which didn't finish", existing.Key)
+	existing.LockedUntil = null.TimeFrom(lockedUntil)
+
+	return existing, nil
+}
+
+// KeepLease extends the lease of the key while its request is processed, so slow requests aren't taken over by
+// retries. It stops when the returned func is called, which must happen before Complete or Release, calling it again
+// does nothing.
+func (ik *IdempotencyKeys) KeepLease(record *dbmodels.IdempotencyKey) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	var stop sync.Once
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(ik.lease / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := ik.extend(ctx, record); err != nil {
+					if !errors.Is(err, context.Canceled) {
+						ik.logger.Warn().Err(err).Msgf("Failed to extend lease of idempotency key %s", record.Key)
+					}
+				}
+			}
+		}
+	}()
+
+	return func() {
+		stop.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// extend moves the end of the lease, as long as the request still holds the key
+func (ik *IdempotencyKeys) extend(ctx context.Context, record *dbmodels.IdempotencyKey) error {
+	lockedUntil := ik.leaseEnd()
+
+	extended, err := dbmodels.IdempotencyKeys(leaseHeldBy(record)...).
+		UpdateAll(ctx, ik.pdb.DBS().Writer, dbmodels.M{dbmodels.IdempotencyKeyColumns.LockedUntil: lockedUntil})
+	if err != nil {
+		return err
+	}
+	if extended == 0 {
+		return ErrIdempotencyKeyLeaseLost
+	}
+
+	record.LockedUntil = null.TimeFrom(lockedUntil)
+
+	return nil
+}
+
+// leaseEnd the end of a lease starting now, in the precision of the DB so the stored lease can be matched
+func (ik *IdempotencyKeys) leaseEnd() time.Time {
+	return time.Now().Add(ik.lease).Truncate(time.Microsecond)
+}
+
+// leaseHeldBy matches the key as long as the request of the record still holds its lease, it was neither completed
+// nor taken over by a retry
+func leaseHeldBy(record *dbmodels.IdempotencyKey) []qm.QueryMod {
+	return []qm.QueryMod{
+		dbmodels.IdempotencyKeyWhere.Scope.EQ(record.Scope),
+		dbmodels.IdempotencyKeyWhere.Key.EQ(record.Key),
+		dbmodels.IdempotencyKeyWhere.ResponseCode.IsNull(),
+		dbmodels.IdempotencyKeyWhere.LockedUntil.EQ(record.LockedUntil),
+	}
+}
+
+// Complete stores the response of the request, it is replayed for the key until it expires. Requests which lost
+// their lease don't store it, the retry which took the key over does.
+func (ik *IdempotencyKeys) Complete(ctx context.Context, record *dbmodels.IdempotencyKey, code int, contentType string, body []byte) error {
+	completed, err := dbmodels.IdempotencyKeys(leaseHeldBy(record)...).UpdateAll(ctx, ik.pdb.DBS().Writer, dbmodels.M{
+		dbmodels.IdempotencyKeyColumns.ResponseCode:        code,
+		dbmodels.IdempotencyKeyColumns.ResponseContentType: contentType,
+		dbmodels.IdempotencyKeyColumns.ResponseBody:        body,
+	})
+	if err != nil {
+		ik.logger.Error().Err(err).Msgf("Failed to store response of idempotency key %s", record.Key)
+		return err
+	}
+	if completed == 0 {
+		return ErrIdempotencyKeyLeaseLost
+	}
+
+	record.ResponseCode = null.IntFrom(code)
+	record.ResponseContentType = null.StringFrom(contentType)
+	record.ResponseBody = null.BytesFrom(body)
+
+	return nil
+}
+
+// Release removes the key of a request which failed, so it can be retried with the same key. Keys taken over by a
+// retry are left to it.
+func (ik *IdempotencyKeys) Release(ctx context.Context, record *dbmodels.IdempotencyKey) error {
+	released, err := dbmodels.IdempotencyKeys(leaseHeldBy(record)...).DeleteAll(ctx, ik.pdb.DBS().Writer)
+	if err != nil {
+		ik.logger.Error().Err(err).Msgf("Failed to release idempotency key %s", record.Key)
+		return err
+	}
+	if released == 0 {
+		return ErrIdempotencyKeyLeaseLost
+	}
+
+	return nil
+}
+
+// DeleteExpired removes all keys whose response is no longer replayed
+func (ik *IdempotencyKeys) DeleteExpired(ctx context.Context) (int64, error) {
+	deleted, err := dbmodels.IdempotencyKeys(
+		dbmodels.IdempotencyKeyWhere.ExpiresAt.LT(time.Now()),
+	).DeleteAll(ctx, ik.pdb.DBS().Writer)
+	if err != nil {
+		ik.logger.Error().Err(err).Msg("Failed to delete expired idempotency keys")
+		return 0, err
+	}
+
+	return deleted, nil
+}
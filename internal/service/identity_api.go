@@ -1,30 +1,37 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	shttp "github.com/DIMO-Network/shared/pkg/http"
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
 	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/patrickmn/go-cache"
 	"github.com/rs/zerolog"
 	"io"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
 var ErrBadRequest = errors.New("bad request")
 
+// vehiclesByTokenIDsBatchSize vehicles looked up by a single query of FetchVehiclesByTokenIDs
+const vehiclesByTokenIDsBatchSize = 50
+
 type IdentityAPI interface {
 	GetCachedVehicleByTokenID(tokenID int64) (*models.Vehicle, error)
-	FetchVehicleByTokenID(tokenID int64) (*models.Vehicle, error)
-	FetchVehiclesByWalletAddress(address string) ([]models.Vehicle, error)
+	FetchVehicleByTokenID(ctx context.Context, tokenID int64) (*models.Vehicle, error)
+	FetchVehiclesByWalletAddress(ctx context.Context, address string) ([]models.Vehicle, error)
+	FetchVehiclesByTokenIDs(ctx context.Context, tokenIDs []int64) (map[int64]models.Vehicle, error)
 
-	GetDeviceDefinitionByID(id string) (*models.DeviceDefinition, error)
+	GetDeviceDefinitionByID(ctx context.Context, id string) (*models.DeviceDefinition, error)
 	GetCachedDeviceDefinitionByID(id string) (*models.DeviceDefinition, error)
-	FetchDeviceDefinitionByID(id string) (*models.DeviceDefinition, error)
+	FetchDeviceDefinitionByID(ctx context.Context, id string) (*models.DeviceDefinition, error)
 }
 
 type identityAPIService struct {
@@ -58,13 +65,13 @@ func (i *identityAPIService) GetCachedVehicleByTokenID(tokenID int64) (*models.V
 	return nil, errors.New("not found")
 }
 
-func (i *identityAPIService) FetchVehicleByTokenID(tokenID int64) (*models.Vehicle, error) {
+func (i *identityAPIService) FetchVehicleByTokenID(ctx context.Context, tokenID int64) (*models.Vehicle, error) {
 	strTokenID := strconv.FormatInt(tokenID, 10)
 
 	// GraphQL query
 	graphqlQuery := fmt.Sprintf(VehicleByTokenIDQuery, strTokenID)
 
-	body, err := i.Query(graphqlQuery)
+	body, err := i.Query(ctx, "vehicle", graphqlQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -81,9 +88,9 @@ func (i *identityAPIService) FetchVehicleByTokenID(tokenID int64) (*models.Vehic
 	return &af.Data.Vehicle, nil
 }
 
-func (i *identityAPIService) FetchVehiclesByWalletAddress(walletAddress string) ([]models.Vehicle, error) {
+func (i *identityAPIService) FetchVehiclesByWalletAddress(ctx context.Context, walletAddress string) ([]models.Vehicle, error) {
 	vehicles := []models.Vehicle{}
-	pagedVehicles, err := i.FetchUserVehiclesPage(walletAddress, "")
+	pagedVehicles, err := i.FetchUserVehiclesPage(ctx, walletAddress, "")
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +98,7 @@ func (i *identityAPIService) FetchVehiclesByWalletAddress(walletAddress string)
 	vehicles = append(vehicles, pagedVehicles.Nodes...)
 
 	for pagedVehicles.PageInfo.HasNextPage {
-		pagedVehicles, err = i.FetchUserVehiclesPage(walletAddress, pagedVehicles.PageInfo.EndCursor)
+		pagedVehicles, err = i.FetchUserVehiclesPage(ctx, walletAddress, pagedVehicles.PageInfo.EndCursor)
 		if err != nil {
 			return nil, err
 		}
@@ -102,14 +109,51 @@ func (i *identityAPIService) FetchVehiclesByWalletAddress(walletAddress string)
 	return vehicles, nil
 }
 
-func (i *identityAPIService) FetchUserVehiclesPage(walletAddress string, after string) (*models.PagedVehiclesNodes, error) {
+// FetchVehiclesByTokenIDs looks up the vehicles with a query per batch of token IDs, instead of one per vehicle. Tokens
+// which don't exist (anymore) are left out.
+func (i *identityAPIService) FetchVehiclesByTokenIDs(ctx context.Context, tokenIDs []int64) (map[int64]models.Vehicle, error) {
+	vehicles := make(map[int64]models.Vehicle, len(tokenIDs))
+	for start := 0; start < len(tokenIDs); start += vehiclesByTokenIDsBatchSize {
+		batch := tokenIDs[start:min(start+vehiclesByTokenIDsBatchSize, len(tokenIDs))]
+
+		var query strings.Builder
+		query.WriteString("{")
+		for _, tokenID := range batch {
+			fmt.Fprintf(&query, VehicleByTokenIDAliasQuery, tokenID)
+		}
+		query.WriteString("\n}")
+
+		body, err := i.Query(ctx, "vehicles", query.String())
+		if err != nil {
+			return nil, err
+		}
+
+		// burned tokens are null with an error of their own, the others are still returned
+		var response models.GraphQlData[map[string]*models.Vehicle]
+		if err = json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+
+		for _, vehicle := range response.Data {
+			if vehicle == nil || vehicle.TokenID == 0 {
+				continue
+			}
+			vehicles[vehicle.TokenID] = *vehicle
+			i.cache.Set(fmt.Sprintf("vehicle_%s", strconv.FormatInt(vehicle.TokenID, 10)), vehicle, cache.DefaultExpiration)
+		}
+	}
+
+	return vehicles, nil
+}
+
+func (i *identityAPIService) FetchUserVehiclesPage(ctx context.Context, walletAddress string, after string) (*models.PagedVehiclesNodes, error) {
 	afterCursor := "null"
 	if after != "" {
 		afterCursor = "\"" + after + "\""
 	}
 	graphqlQuery := fmt.Sprintf(VehiclesByWalletAndCursorQuery, walletAddress, afterCursor)
 
-	body, err := i.Query(graphqlQuery)
+	body, err := i.Query(ctx, "vehicles", graphqlQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -123,13 +167,13 @@ func (i *identityAPIService) FetchUserVehiclesPage(walletAddress string, after s
 	return &pagedVehicles.Data.VehicleNodes, nil
 }
 
-func (i *identityAPIService) GetDeviceDefinitionByID(id string) (*models.DeviceDefinition, error) {
+func (i *identityAPIService) GetDeviceDefinitionByID(ctx context.Context, id string) (*models.DeviceDefinition, error) {
 	cached, err := i.GetCachedDeviceDefinitionByID(id)
 	if err == nil {
 		return cached, nil
 	}
 
-	return i.FetchDeviceDefinitionByID(id)
+	return i.FetchDeviceDefinitionByID(ctx, id)
 }
 
 func (i *identityAPIService) GetCachedDeviceDefinitionByID(id string) (*models.DeviceDefinition, error) {
@@ -140,10 +184,10 @@ func (i *identityAPIService) GetCachedDeviceDefinitionByID(id string) (*models.D
 	return nil, errors.New("not found")
 }
 
-func (i *identityAPIService) FetchDeviceDefinitionByID(id string) (*models.DeviceDefinition, error) {
+func (i *identityAPIService) FetchDeviceDefinitionByID(ctx context.Context, id string) (*models.DeviceDefinition, error) {
 	graphqlQuery := fmt.Sprintf(DeviceDefinitionByIDQuery, id)
 
-	body, err := i.Query(graphqlQuery)
+	body, err := i.Query(ctx, "deviceDefinition", graphqlQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -160,14 +204,17 @@ func (i *identityAPIService) FetchDeviceDefinitionByID(id string) (*models.Devic
 	return &af.Data.DeviceDefinition, nil
 }
 
-func (i *identityAPIService) Query(graphqlQuery string) ([]byte, error) {
+// Query sends the GraphQL query, operation names its span
+func (i *identityAPIService) Query(ctx context.Context, operation, graphqlQuery string) ([]byte, error) {
 	requestPayload := models.GraphQLRequest{Query: graphqlQuery}
 	payloadBytes, err := json.Marshal(requestPayload)
 	if err != nil {
 		return nil, err
 	}
 
+	_, span := tracing.StartClientSpan(ctx, tracing.PeerIdentityAPI, operation)
 	resp, err := i.httpClient.ExecuteRequest(i.apiURL.String(), "POST", payloadBytes)
+	tracing.End(span, err)
 	if err != nil {
 		i.logger.Err(err).Msg("Failed to send POST request")
 		return nil, err
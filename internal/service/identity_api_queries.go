@@ -59,3 +59,23 @@ const VehicleByTokenIDQuery = `{
 		}
   	}
 }`
+
+// VehicleByTokenIDAliasQuery one aliased vehicle of VehiclesByTokenIDsQuery
+const VehicleByTokenIDAliasQuery = `
+	v%[1]d: vehicle(tokenId: %[1]d) {
+		id
+		tokenId
+		mintedAt
+		owner
+		definition{
+			id
+			make
+			model
+			year
+		}
+		syntheticDevice {
+			id
+			tokenId
+			mintedAt
+		}
+	}`
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/friendsofgo/errors"
+	"github.com/segmentio/ksuid"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+var ErrImportNotFound = errors.New("import batch not found")
+
+// CreateImportBatch stores an import of the wallet with its rows, in a single transaction.
+func (ds *Vehicle) CreateImportBatch(ctx context.Context, owner common.Address, rows dbmodels.ImportRowSlice) (*dbmodels.ImportBatch, error) {
+	batch := dbmodels.ImportBatch{
+		ID:           ksuid.New().String(),
+		OwnerAddress: owner.Hex(),
+		RowCount:     len(rows),
+	}
+
+	tx, err := ds.pdb.DBS().Writer.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				ds.logger.Error().Err(rbErr).Msgf("CreateImportBatch: Failed to rollback transaction for import %s", batch.ID)
+			}
+		}
+	}()
+
+	if err = batch.Insert(ctx, tx, boil.Infer()); err != nil {
+		return nil, fmt.Errorf("failed to insert import batch: %w", err)
+	}
+
+	for _, row := range rows {
+		row.BatchID = batch.ID
+		if err = row.Insert(ctx, tx, boil.Infer()); err != nil {
+			return nil, fmt.Errorf("failed to insert row %d of import batch: %w", row.RowNumber, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &batch, nil
+}
+
+// UpdateImportRows stores the outcome, reason and details of the rows of an import, in a single transaction.
+func (ds *Vehicle) UpdateImportRows(ctx context.Context, rows dbmodels.ImportRowSlice) error {
+	tx, err := ds.pdb.DBS().Writer.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				ds.logger.Error().Err(rbErr).Msg("UpdateImportRows: Failed to rollback transaction")
+			}
+		}
+	}()
+
+	for _, row := range rows {
+		if _, err = row.Update(ctx, tx, boil.Whitelist(
+			dbmodels.ImportRowColumns.Outcome,
+			dbmodels.ImportRowColumns.Reason,
+			dbmodels.ImportRowColumns.Details,
+		)); err != nil {
+			return fmt.Errorf("failed to update row %d of import batch %s: %w", row.RowNumber, row.BatchID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetImportBatch retrieves an import of the wallet.
+func (ds *Vehicle) GetImportBatch(ctx context.Context, owner common.Address, id string) (*dbmodels.ImportBatch, error) {
+	batch, err := dbmodels.ImportBatches(
+		dbmodels.ImportBatchWhere.ID.EQ(id),
+		dbmodels.ImportBatchWhere.OwnerAddress.EQ(owner.Hex()),
+	).One(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrImportNotFound
+		}
+		ds.logger.Error().Err(err).Msgf("Failed to get import batch %s", id)
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// GetImportRows retrieves the rows of an import in file order.
+func (ds *Vehicle) GetImportRows(ctx context.Context, batchID string) (dbmodels.ImportRowSlice, error) {
+	rows, err := dbmodels.ImportRows(
+		dbmodels.ImportRowWhere.BatchID.EQ(batchID),
+		qm.OrderBy(dbmodels.ImportRowColumns.RowNumber),
+	).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msgf("Failed to get rows of import batch %s", batchID)
+		return nil, err
+	}
+
+	return rows, nil
+}
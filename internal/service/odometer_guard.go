@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DIMO-Network/shared/pkg/db"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/DIMO-Network/volteras-oracle/internal/convert"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/friendsofgo/errors"
+	"github.com/patrickmn/go-cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	OdometerAnomalyZero         = "zero"
+	OdometerAnomalyDecrease     = "decrease"
+	OdometerAnomalyJump         = "jump"
+	OdometerAnomalyUnitMismatch = "unitMismatch"
+)
+
+const (
+	// odometerDecreaseTolerance allows for rounding differences between readings, in km
+	odometerDecreaseTolerance = 1.0
+	// odometerJumpTolerance allows for readings that are not perfectly in sync with their timestamps, in km
+	odometerJumpTolerance = 5.0
+	// odometerUnitMismatchTolerance is the relative difference from a km / miles conversion still taken as unit flip
+	odometerUnitMismatchTolerance = 0.02
+	// odometerUnitMismatchMinimum avoids unit flip detection on nearly new vehicles, where the ratio is meaningless, in km
+	odometerUnitMismatchMinimum = 100.0
+)
+
+const (
+	// odometerRebaseReadings is the number of consistent readings which replace the accepted one, so a vehicle whose
+	// odometer was reset or replaced is not stuck in anomalies
+	odometerRebaseReadings = 3
+	// odometerRebaseMaxJump is the largest increase over the accepted reading which can replace it, in km. Larger jumps
+	// are more likely a broken sensor than a replaced odometer
+	odometerRebaseMaxJump = 1000.0
+)
+
+// odometerGuard keeps odometer readings monotonic per VIN.
+// Zero readings, decreases, jumps the vehicle could not drive in the elapsed time and likely km / miles flips
+// are removed from the event and recorded as anomalies. A nil guard accepts every reading.
+type odometerGuard struct {
+	pdb      *db.Store
+	logger   zerolog.Logger
+	states   *cache.Cache
+	maxSpeed float64
+	m        sync.Mutex
+}
+
+// odometerState is the accepted reading of a VIN, seeded from the last forwarded one, and the rejected readings
+// which are consistent among themselves
+type odometerState struct {
+	m          sync.Mutex
+	loaded     bool
+	accepted   *signalValue
+	candidate  *signalValue
+	candidates int
+}
+
+func newOdometerGuard(pdb *db.Store, logger zerolog.Logger, settings config.Settings) *odometerGuard {
+	maxSpeed := settings.OdometerMaxSpeed
+	if maxSpeed <= 0 {
+		maxSpeed = 250
+	}
+
+	return &odometerGuard{
+		pdb:      pdb,
+		logger:   logger,
+		states:   cache.New(7*24*time.Hour, time.Hour),
+		maxSpeed: maxSpeed,
+	}
+}
+
+// Check validates the odometer reading in the signals against the last accepted one for the VIN
+// and returns the signals without the odometer if the reading is an anomaly
+func (g *odometerGuard) Check(ctx context.Context, vin string, signals []interface{}, fallback time.Time) []interface{} {
+	if g == nil {
+		return signals
+	}
+
+	reading, ok := signalValues(signals, fallback)[signalOdometer]
+	if !ok {
+		return signals
+	}
+
+	state := g.state(vin)
+	state.m.Lock()
+	g.load(ctx, vin, state)
+	anomaly, previous := state.check(reading, g.maxSpeed)
+	state.m.Unlock()
+
+	if anomaly == "" {
+		return signals
+	}
+
+	odometerAnomaliesCntr.WithLabelValues(anomaly).Inc()
+	g.logger.Warn().Str("vin", vin).Str("anomaly", anomaly).Float64("odometer", reading.Value).Msg("Odometer anomaly, reading removed")
+
+	if err := g.record(ctx, vin, anomaly, reading, previous); err != nil {
+		g.logger.Error().Err(err).Str("vin", vin).Msg("Failed to record odometer anomaly")
+	}
+
+	result := make([]interface{}, 0, len(signals))
+	for _, s := range signals {
+		if signal, ok := s.(map[string]interface{}); ok && signal["name"] == signalOdometer {
+			continue
+		}
+		result = append(result, s)
+	}
+
+	return result
+}
+
+func (g *odometerGuard) state(vin string) *odometerState {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if state, found := g.states.Get(vin); found {
+		return state.(*odometerState)
+	}
+
+	state := &odometerState{}
+	g.states.SetDefault(vin, state)
+	return state
+}
+
+// load seeds the accepted reading with the last odometer forwarded for the VIN, eg. after restart.
+// Failures are retried with the next reading, which is checked without a baseline meanwhile.
+func (g *odometerGuard) load(ctx context.Context, vin string, state *odometerState) {
+	if state.loaded {
+		return
+	}
+
+	signal, err := dbmodels.TelemetrySignals(
+		dbmodels.TelemetrySignalWhere.Vin.EQ(vin),
+		dbmodels.TelemetrySignalWhere.Name.EQ(signalOdometer),
+	).One(ctx, g.pdb.DBS().Reader)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			g.logger.Error().Err(err).Str("vin", vin).Msg("Failed to load the last odometer reading")
+			return
+		}
+		state.loaded = true
+		return
+	}
+	state.loaded = true
+
+	var value float64
+	if err := signal.Value.Unmarshal(&value); err != nil {
+		g.logger.Warn().Err(err).Str("vin", vin).Msg("Last odometer reading is not a number")
+		return
+	}
+	if state.accepted == nil || signal.Timestamp.After(state.accepted.Timestamp) {
+		state.accepted = &signalValue{Value: value, Timestamp: signal.Timestamp}
+	}
+}
+
+// check returns the anomaly type of the reading and the accepted reading it was compared to, accepting valid readings.
+// Anomalies which are consistent with the ones before them replace the accepted reading after odometerRebaseReadings,
+// except zero readings, unit mismatches and jumps above odometerRebaseMaxJump.
+func (s *odometerState) check(reading signalValue, maxSpeed float64) (string, *signalValue) {
+	previous := s.accepted
+
+	anomaly := detectOdometerAnomaly(previous, reading, maxSpeed)
+	if anomaly == "" {
+		// out of order readings are not newer than the accepted one, do not move it back
+		if previous == nil || reading.Timestamp.After(previous.Timestamp) {
+			s.accepted = &reading
+		}
+		s.candidate, s.candidates = nil, 0
+		return "", previous
+	}
+
+	// a converted reading must not become the baseline, the next readings in the right unit would be anomalies
+	if anomaly == OdometerAnomalyZero || anomaly == OdometerAnomalyUnitMismatch {
+		return anomaly, previous
+	}
+	if anomaly == OdometerAnomalyJump && reading.Value-previous.Value > odometerRebaseMaxJump {
+		return anomaly, previous
+	}
+
+	if s.candidate != nil && reading.Timestamp.After(s.candidate.Timestamp) &&
+		detectOdometerAnomaly(s.candidate, reading, maxSpeed) == "" {
+		s.candidates++
+	} else {
+		s.candidates = 1
+	}
+	s.candidate = &reading
+
+	if s.candidates >= odometerRebaseReadings {
+		s.accepted = &reading
+		s.candidate, s.candidates = nil, 0
+		odometerRebasesCntr.Inc()
+		return "", previous
+	}
+
+	return anomaly, previous
+}
+
+func (g *odometerGuard) record(ctx context.Context, vin, anomaly string, reading signalValue, previous *signalValue) error {
+	record := dbmodels.OdometerAnomaly{
+		ID:          ksuid.New().String(),
+		Vin:         vin,
+		AnomalyType: anomaly,
+		Value:       reading.Value,
+		SignalTime:  reading.Timestamp,
+	}
+	if previous != nil {
+		record.PreviousValue = null.Float64From(previous.Value)
+		record.PreviousTime = null.TimeFrom(previous.Timestamp)
+	}
+
+	return record.Insert(ctx, g.pdb.DBS().Writer, boil.Infer())
+}
+
+// detectOdometerAnomaly returns the anomaly type of the reading, or empty string for a valid reading
+func detectOdometerAnomaly(previous *signalValue, reading signalValue, maxSpeed float64) string {
+	if reading.Value <= 0 {
+		return OdometerAnomalyZero
+	}
+
+	if previous == nil {
+		return ""
+	}
+
+	if previous.Value >= odometerUnitMismatchMinimum {
+		for _, converted := range []float64{previous.Value * convert.KilometersPerMile, previous.Value / convert.KilometersPerMile} {
+			if math.Abs(reading.Value-converted)/converted <= odometerUnitMismatchTolerance {
+				return OdometerAnomalyUnitMismatch
+			}
+		}
+	}
+
+	// older readings can't be higher than the newer accepted one
+	elapsed := reading.Timestamp.Sub(previous.Timestamp)
+	if elapsed < 0 {
+		if reading.Value > previous.Value+odometerDecreaseTolerance {
+			return OdometerAnomalyDecrease
+		}
+		return ""
+	}
+
+	if reading.Value < previous.Value-odometerDecreaseTolerance {
+		return OdometerAnomalyDecrease
+	}
+
+	if reading.Value-previous.Value > maxSpeed*elapsed.Hours()+odometerJumpTolerance {
+		return OdometerAnomalyJump
+	}
+
+	return ""
+}
+
+// Prometheus metrics
+var odometerAnomaliesCntr = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "dimo_oracle_odometer_anomalies_total",
+	Help: "Total number of odometer readings removed as anomalies, by type",
+}, []string{"type"})
+
+var odometerRebasesCntr = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dimo_oracle_odometer_rebases_total",
+	Help: "Total number of consistent odometer readings which replaced the accepted one after anomalies",
+})
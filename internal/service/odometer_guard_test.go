@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"testing"
+	"time"
+)
+
+type OdometerGuardTestSuite struct {
+	suite.Suite
+}
+
+func TestOdometerGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(OdometerGuardTestSuite))
+}
+
+func (s *OdometerGuardTestSuite) TestDetectOdometerAnomaly() {
+	now := time.Now()
+	previous := &signalValue{Value: 10000, Timestamp: now}
+
+	tests := []struct {
+		name     string
+		previous *signalValue
+		reading  signalValue
+		expected string
+	}{
+		{"first reading", nil, signalValue{Value: 10000, Timestamp: now}, ""},
+		{"first reading zero", nil, signalValue{Value: 0, Timestamp: now}, OdometerAnomalyZero},
+		{"zero", previous, signalValue{Value: 0, Timestamp: now.Add(time.Minute)}, OdometerAnomalyZero},
+		{"increase", previous, signalValue{Value: 10050, Timestamp: now.Add(time.Hour)}, ""},
+		{"rounding", previous, signalValue{Value: 9999.5, Timestamp: now.Add(time.Minute)}, ""},
+		{"decrease", previous, signalValue{Value: 9900, Timestamp: now.Add(time.Minute)}, OdometerAnomalyDecrease},
+		{"jump", previous, signalValue{Value: 10500, Timestamp: now.Add(time.Hour)}, OdometerAnomalyJump},
+		{"miles", previous, signalValue{Value: 6213.7, Timestamp: now.Add(time.Minute)}, OdometerAnomalyUnitMismatch},
+		{"kilometers", previous, signalValue{Value: 16093.4, Timestamp: now.Add(time.Minute)}, OdometerAnomalyUnitMismatch},
+		{"older lower", previous, signalValue{Value: 9990, Timestamp: now.Add(-time.Hour)}, ""},
+		{"older higher", previous, signalValue{Value: 10100, Timestamp: now.Add(-time.Hour)}, OdometerAnomalyDecrease},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.expected, detectOdometerAnomaly(tt.previous, tt.reading, 250))
+		})
+	}
+}
+
+func (s *OdometerGuardTestSuite) TestStateRebase() {
+	now := time.Now()
+	at := func(minutes int, value float64) signalValue {
+		return signalValue{Value: value, Timestamp: now.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	tests := []struct {
+		name     string
+		readings []signalValue
+		// anomaly type of every reading
+		expected []string
+		accepted float64
+	}{
+		{
+			name:     "consistent readings after a reset replace the accepted one",
+			readings: []signalValue{at(0, 10000), at(1, 50), at(2, 51), at(3, 52), at(4, 53)},
+			expected: []string{"", OdometerAnomalyDecrease, OdometerAnomalyDecrease, "", ""},
+			accepted: 53,
+		},
+		{
+			name:     "inconsistent readings do not",
+			readings: []signalValue{at(0, 10000), at(1, 50), at(2, 9000), at(3, 60), at(4, 8000)},
+			expected: []string{"", OdometerAnomalyDecrease, OdometerAnomalyDecrease, OdometerAnomalyDecrease, OdometerAnomalyDecrease},
+			accepted: 10000,
+		},
+		{
+			name:     "valid reading in between starts over",
+			readings: []signalValue{at(0, 10000), at(1, 50), at(2, 51), at(3, 10001), at(4, 52)},
+			expected: []string{"", OdometerAnomalyDecrease, OdometerAnomalyDecrease, "", OdometerAnomalyDecrease},
+			accepted: 10001,
+		},
+		{
+			name:     "zero readings never replace it",
+			readings: []signalValue{at(0, 10000), at(1, 0), at(2, 0), at(3, 0)},
+			expected: []string{"", OdometerAnomalyZero, OdometerAnomalyZero, OdometerAnomalyZero},
+			accepted: 10000,
+		},
+		{
+			name:     "unit mismatches never replace it",
+			readings: []signalValue{at(0, 10000), at(1, 6213.7), at(2, 6213.7), at(3, 6213.8), at(4, 6213.8)},
+			expected: []string{"", OdometerAnomalyUnitMismatch, OdometerAnomalyUnitMismatch, OdometerAnomalyUnitMismatch, OdometerAnomalyUnitMismatch},
+			accepted: 10000,
+		},
+		{
+			name:     "consistent small jumps replace it",
+			readings: []signalValue{at(0, 10000), at(1, 10500), at(2, 10501), at(3, 10502)},
+			expected: []string{"", OdometerAnomalyJump, OdometerAnomalyJump, ""},
+			accepted: 10502,
+		},
+		{
+			name:     "large jumps never replace it",
+			readings: []signalValue{at(0, 10000), at(1, 50000), at(2, 50001), at(3, 50002), at(4, 50003)},
+			expected: []string{"", OdometerAnomalyJump, OdometerAnomalyJump, OdometerAnomalyJump, OdometerAnomalyJump},
+			accepted: 10000,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			state := &odometerState{loaded: true}
+			for i, reading := range tt.readings {
+				anomaly, _ := state.check(reading, 250)
+				s.Equal(tt.expected[i], anomaly, "reading %d", i)
+			}
+			s.Equal(tt.accepted, state.accepted.Value)
+		})
+	}
+}
+
+func (s *OdometerGuardTestSuite) TestCheckNilGuard() {
+	var g *odometerGuard
+	signals := []interface{}{
+		map[string]interface{}{"name": signalOdometer, "timestamp": "2024-01-01T00:00:00Z", "value": 0.0},
+	}
+
+	s.Equal(signals, g.Check(context.Background(), vin, signals, time.Now()))
+}
+
+func (s *OracleTestSuite) TestOdometerGuardSeed() {
+	defer s.TearDownTest()
+
+	ts := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	signal := dbmodels.TelemetrySignal{Vin: vin, Name: signalOdometer, Value: types.JSON("10000"), Timestamp: ts}
+	s.Require().NoError(signal.Insert(s.ctx, s.pdb.DBS().Writer, boil.Infer()))
+
+	guard := newOdometerGuard(&s.pdb, zerolog.Nop(), config.Settings{})
+	signals := []interface{}{
+		map[string]interface{}{"name": signalOdometer, "timestamp": ts.Add(time.Minute).Format(time.RFC3339), "value": 9000.0},
+	}
+	s.Empty(guard.Check(s.ctx, vin, signals, time.Now()))
+
+	anomalies, err := dbmodels.OdometerAnomalies(dbmodels.OdometerAnomalyWhere.Vin.EQ(vin)).All(s.ctx, s.pdb.DBS().Reader)
+	s.Require().NoError(err)
+	s.Require().Len(anomalies, 1)
+	s.Equal(OdometerAnomalyDecrease, anomalies[0].AnomalyType)
+	s.Equal(10000.0, anomalies[0].PreviousValue.Float64)
+}
@@ -8,14 +8,20 @@ import (
 	"github.com/DIMO-Network/volteras-oracle/internal/config"
 	"github.com/DIMO-Network/volteras-oracle/internal/convert"
 	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/tracing"
 	"github.com/patrickmn/go-cache"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 	"time"
 )
 
+// cloudEventTypeEvent is the DIMO CloudEvent type for vehicle events, eg. trips
+const cloudEventTypeEvent = "dimo.event"
+
 type OracleService struct {
 	Ctx             context.Context
 	dimoNodeAPISvc  DimoNodeAPI
@@ -25,6 +31,11 @@ type OracleService struct {
 	stop            chan bool
 	Db              *Vehicle
 	cache           *cache.Cache
+	filter          *telemetryFilter
+	limiter         *telemetryLimiter
+	status          *TelemetryStatusTracker
+	sessions        *SessionDetector
+	odometer        *odometerGuard
 }
 
 // Stop is used only for functional tests
@@ -32,7 +43,7 @@ func (cs *OracleService) Stop() {
 	cs.stop <- true
 }
 
-func NewOracleService(ctx context.Context, logger zerolog.Logger, settings config.Settings, db *Vehicle) (*OracleService, error) {
+func NewOracleService(ctx context.Context, logger zerolog.Logger, settings config.Settings, db *Vehicle, status *TelemetryStatusTracker) (*OracleService, error) {
 	// Initialize the dimo node service
 	dimoNodeAPISvc := NewDimoNodeAPIService(logger, settings)
 
@@ -50,6 +61,11 @@ func NewOracleService(ctx context.Context, logger zerolog.Logger, settings confi
 		settings:        settings,
 		Db:              db,
 		cache:           c,
+		filter:          newTelemetryFilter(settings),
+		limiter:         newTelemetryLimiter(settings),
+		status:          status,
+		sessions:        newSessionDetector(db.pdb, logger, settings),
+		odometer:        newOdometerGuard(db.pdb, logger, settings),
 	}
 
 	return cs, nil
@@ -71,7 +87,7 @@ func ParseCloudEvent(msg []byte) (*cloudevent.CloudEvent[json.RawMessage], error
 	return &telemetry, nil
 }
 
-func (cs *OracleService) HandleDeviceByVIN(msg interface{}) error {
+func (cs *OracleService) HandleDeviceByVIN(ctx context.Context, msg interface{}) error {
 	cs.logger.Debug().Msgf("Received message: %s", msg)
 
 	// Ensure msg is of type []byte
@@ -105,13 +121,75 @@ func (cs *OracleService) HandleDeviceByVIN(msg interface{}) error {
 	if !ok {
 		return fmt.Errorf("VIN is missing in the message data for CloudEvent ID: %s", cloudEvent.ID)
 	}
+	// checked before anything is recorded for the VIN
+	if len(vin) != vinLength {
+		return fmt.Errorf("invalid VIN of length %d in the message data for CloudEvent ID: %s", len(vin), cloudEvent.ID)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(tracing.VIN(vin))
+	cs.status.Received(vin)
 
 	// Validate signals
 	err = validateSignals(data, vin, cs.logger)
 	if err != nil {
+		cs.status.Failed(vin, err)
 		return err
 	}
 
+	// Skip duplicates and late events
+	late := false
+	signalTs := latestSignalTimestamp(data, cloudEvent.Time)
+	switch cs.filter.Check(vin, cloudEvent.ID, signalTs) {
+	case telemetryDuplicate:
+		cs.logger.Debug().Msgf("Duplicate event %s for VIN: %s, skipping", cloudEvent.ID, vin)
+		return nil
+	case telemetryLate:
+		if cs.filter.DropLate() {
+			cs.logger.Debug().Msgf("Late event %s for VIN: %s, skipping", cloudEvent.ID, vin)
+			return nil
+		}
+		cs.logger.Debug().Msgf("Late event %s for VIN: %s, flagging", cloudEvent.ID, vin)
+		late = true
+		if cloudEvent.Extras == nil {
+			cloudEvent.Extras = make(map[string]any)
+		}
+		cloudEvent.Extras[lateEventExtension] = true
+	}
+
+	// Per VIN rate limit and location downsampling
+	if !cs.limiter.Allow(vin) {
+		cs.logger.Debug().Msgf("Rate limit exceeded for VIN: %s, skipping", vin)
+		cs.reportLimited(vin)
+		return nil
+	}
+
+	signals, _ := data["signals"].([]interface{})
+	forwarded := signals
+	if signals != nil {
+		received := len(signals)
+
+		// Remove odometer anomalies, before anything else uses the odometer
+		signals = cs.odometer.Check(cs.Ctx, vin, signals, cloudEvent.Time)
+
+		downsampled := cs.limiter.Downsample(vin, signals)
+		if len(downsampled) != len(signals) {
+			cs.logger.Debug().Msgf("Downsampled %d location signals for VIN: %s", len(signals)-len(downsampled), vin)
+			cs.reportLimited(vin)
+		}
+		if len(downsampled) == 0 {
+			cs.logger.Debug().Msgf("All signals removed for VIN: %s, skipping", vin)
+			return nil
+		}
+		forwarded = downsampled
+		if len(downsampled) != received {
+			data["signals"] = downsampled
+			cloudEvent.Data, err = json.Marshal(data)
+			if err != nil {
+				cs.logger.Err(err).Msg("Failed to marshal downsampled data")
+				return err
+			}
+		}
+	}
+
 	vehicleID := vin
 	cachedResponse, found := cs.cache.Get(vehicleID)
 	if found {
@@ -123,6 +201,7 @@ func (cs *OracleService) HandleDeviceByVIN(msg interface{}) error {
 		if err != nil {
 			failedStatusEventCntr.Inc()
 			cs.logger.Error().Err(err).Msgf("Error querying vehicle by vehicleID: %s", vehicleID)
+			cs.status.Failed(vin, err)
 			return err
 		}
 		dBVehicle = response
@@ -141,29 +220,139 @@ func (cs *OracleService) HandleDeviceByVIN(msg interface{}) error {
 		return err
 	}
 
+	// Detect trips and charging sessions, finished ones are sent to DIS as events
+	events, err := cs.sessions.Detect(cs.Ctx, vin, cloudEvent.CloudEventHeader, signals, cloudEvent.Time)
+	if err != nil {
+		cs.logger.Error().Err(err).Msgf("Failed to detect trips and charging sessions for VIN: %s", vin)
+	}
+	for _, event := range events {
+		cs.sendSessionEvent(ctx, event)
+	}
+
 	// Send the DISEvent to the Dimo Node
-	return cs.HandleSendToDIS(cloudEvent)
+	accepted, err := cs.sendToDIS(ctx, vin, cloudEvent)
+	if err != nil {
+		return err
+	}
+
+	if accepted {
+		// only now, the event is redelivered when sending fails
+		cs.filter.Commit(vin, cloudEvent.ID, signalTs)
+
+		// late events are older than the last values
+		if !late {
+			cs.status.Signals(vin, forwarded)
+		}
+	}
+
+	return nil
+}
+
+// reportLimited warns about a VIN whose telemetry is limited, once per report interval with the counts since
+func (cs *OracleService) reportLimited(vin string) {
+	if limited, ok := cs.limiter.Report(vin, time.Now()); ok {
+		cs.logger.Warn().Str("vin", vin).Int("rateLimited", limited.rateLimited).Int("downsampled", limited.downsampled).
+			Msgf("Telemetry of VIN: %s limited", vin)
+	}
 }
 
-func (cs *OracleService) HandleSendToDIS(ce *cloudevent.CloudEvent[json.RawMessage]) error {
+// SendSessionEvents ends the sessions of VINs which stopped reporting and sends the session events DIS didn't accept
+// yet
+func (cs *OracleService) SendSessionEvents(ctx context.Context) error {
+	events, err := cs.sessions.closeIdle(ctx, time.Now())
+	// sessions closed before the error are sent
+	for _, event := range events {
+		cs.sendSessionEvent(ctx, event)
+	}
+	if err != nil {
+		return err
+	}
+
+	pending, err := cs.sessions.pendingEvents(ctx, time.Now().Add(-sessionEventRetryDelay))
+	if err != nil {
+		return err
+	}
+	for _, event := range pending {
+		cs.sendSessionEvent(ctx, event)
+	}
+
+	return nil
+}
+
+// sendSessionEvent sends the event of an ended session to DIS, it stays pending and is sent again when sending fails
+func (cs *OracleService) sendSessionEvent(ctx context.Context, event sessionEvent) {
+	eventCloudEvent, err := newEventCloudEvent(event)
+	if err != nil {
+		// sending it again doesn't help
+		cs.logger.Error().Err(err).Msgf("Failed to create %s event for VIN: %s, dropping it", event.event.Name, event.vin)
+	} else if _, err := cs.sendToDIS(ctx, event.vin, eventCloudEvent); err != nil {
+		cs.logger.Warn().Err(err).Msgf("Failed to send %s event for VIN: %s, it will be retried", event.event.Name, event.vin)
+		return
+	}
+
+	// rejected events aren't retried either
+	if err := cs.sessions.markSent(ctx, event); err != nil {
+		cs.logger.Error().Err(err).Msgf("Failed to mark %s event for VIN: %s as sent", event.event.Name, event.vin)
+	}
+}
+
+// newEventCloudEvent wraps the session event into a DIMO event CloudEvent, using the header of the telemetry the
+// session started with. The ID is the one of the session, so DIS can drop events sent again.
+func newEventCloudEvent(event sessionEvent) (*cloudevent.CloudEvent[json.RawMessage], error) {
+	if !event.header.Valid {
+		return nil, fmt.Errorf("no CloudEvent header stored for session: %s", event.sessionID)
+	}
+
+	var header cloudevent.CloudEventHeader
+	if err := json.Unmarshal(event.header.JSON, &header); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(models.EventsData{Events: []models.Event{event.event}})
+	if err != nil {
+		return nil, err
+	}
+
+	header.ID = event.sessionID
+	header.Type = cloudEventTypeEvent
+	header.Time = event.event.Timestamp.Add(time.Duration(event.event.DurationNs))
+	header.Extras = nil
+
+	return &cloudevent.CloudEvent[json.RawMessage]{
+		CloudEventHeader: header,
+		Data:             data,
+	}, nil
+}
+
+func (cs *OracleService) HandleSendToDIS(ctx context.Context, vin string, ce *cloudevent.CloudEvent[json.RawMessage]) error {
+	_, err := cs.sendToDIS(ctx, vin, ce)
+	return err
+}
+
+// sendToDIS sends the CloudEvent to the Dimo Node, reporting whether it was accepted. Rejected events aren't retried,
+// so they don't return an error.
+func (cs *OracleService) sendToDIS(ctx context.Context, vin string, ce *cloudevent.CloudEvent[json.RawMessage]) (bool, error) {
 	// Send the CloudEvent to the Dimo Node
-	statusCode, err := cs.dimoNodeAPISvc.SendToDimoNode(ce)
+	statusCode, err := cs.dimoNodeAPISvc.SendToDimoNode(ctx, ce)
 	if err != nil {
 		failedStatusEventCntr.Inc()
 		cs.logger.Error().Err(err).Msg("Failed to send event to Dimo Node")
-		return err
+		cs.status.Failed(vin, err)
+		return false, err
 	}
 
 	if statusCode == http.StatusBadRequest {
 		failedStatusEventCntr.Inc()
 		// Just log it and do not retry
 		cs.logger.Error().Err(err).Msg("Failed to send event to Dimo Node")
-		return nil
+		cs.status.Failed(vin, fmt.Errorf("event rejected by Dimo Node, status code: %d", http.StatusBadRequest))
+		return false, nil
 	}
 
 	successStatusEventCntr.Inc()
+	cs.status.Forwarded(vin)
 	cs.logger.Debug().Msg("Successfully sent event to Dimo Node")
-	return nil
+	return true, nil
 }
 
 func validateSignals(data map[string]interface{}, vin string, logger zerolog.Logger) error {
@@ -182,11 +371,11 @@ func validateSignals(data map[string]interface{}, vin string, logger zerolog.Log
 
 // Prometheus metrics
 var successStatusEventCntr = promauto.NewCounter(prometheus.CounterOpts{
-	Name: "oracle_example_success_status_event_total",
+	Name: "dimo_oracle_success_status_event_total",
 	Help: "Total success events processed",
 })
 
 var failedStatusEventCntr = promauto.NewCounter(prometheus.CounterOpts{
-	Name: "oracle_example_failed_status_events_total",
+	Name: "dimo_oracle_failed_status_events_total",
 	Help: "Total number of failed events",
 })
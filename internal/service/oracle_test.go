@@ -159,7 +159,7 @@ func (s *OracleTestSuite) TestDeviceMinted() {
 	oracleService.identityService = mockService
 
 	// then
-	err := oracleService.HandleDeviceByVIN([]byte(validCloudEventMsgNoProduceAndSubject))
+	err := oracleService.HandleDeviceByVIN(context.Background(), []byte(validCloudEventMsgNoProduceAndSubject))
 
 	// verify
 	require.NoError(s.T(), err)
@@ -176,7 +176,7 @@ func (s *OracleTestSuite) TestDeviceNotFound() {
 	// when
 
 	// then
-	err := oracleService.HandleDeviceByVIN([]byte(validCloudEventMsgNoProduceAndSubject))
+	err := oracleService.HandleDeviceByVIN(context.Background(), []byte(validCloudEventMsgNoProduceAndSubject))
 
 	// verify
 	require.Error(s.T(), err)
@@ -200,7 +200,7 @@ func (s *OracleTestSuite) TestDeviceNotMinted() {
 
 	// then
 	// should not send msg to DIS but not fail
-	err := oracleService.HandleDeviceByVIN([]byte(validCloudEventMsgNoProduceAndSubject))
+	err := oracleService.HandleDeviceByVIN(context.Background(), []byte(validCloudEventMsgNoProduceAndSubject))
 
 	// verify
 	require.NoError(s.T(), err)
@@ -224,7 +224,7 @@ func (s *OracleTestSuite) TestDeviceSendValidCloudEvent() {
 
 	// then
 	// should not send msg to DIS but not fail
-	err := oracleService.HandleDeviceByVIN([]byte(validCloudEventMsg))
+	err := oracleService.HandleDeviceByVIN(context.Background(), []byte(validCloudEventMsg))
 
 	// verify
 	require.NoError(s.T(), err)
@@ -248,7 +248,7 @@ func (s *OracleTestSuite) TestDeviceSendInvalidCloudEvent() {
 
 	// then
 	// should not send msg to DIS but not fail
-	err := oracleService.HandleDeviceByVIN([]byte(invalidCloudEventMsg))
+	err := oracleService.HandleDeviceByVIN(context.Background(), []byte(invalidCloudEventMsg))
 
 	// verify
 	require.Error(s.T(), err)
@@ -317,6 +317,7 @@ type MockIdentityAPIService struct {
 	MockGetCachedVehicleByTokenID    func(tokenID int64) (*models.Vehicle, error)
 	MockFetchVehicleByTokenID        func(tokenID int64) (*models.Vehicle, error)
 	MockFetchVehiclesByWalletAddress func(walletAddress string) ([]models.Vehicle, error)
+	MockFetchVehiclesByTokenIDs      func(tokenIDs []int64) (map[int64]models.Vehicle, error)
 
 	MockGetDeviceDefinitionByID       func(id string) (*models.DeviceDefinition, error)
 	MockGetCachedDeviceDefinitionByID func(id string) (*models.DeviceDefinition, error)
@@ -330,21 +331,28 @@ func (m *MockIdentityAPIService) GetCachedVehicleByTokenID(tokenID int64) (*mode
 	return nil, nil
 }
 
-func (m *MockIdentityAPIService) FetchVehicleByTokenID(tokenID int64) (*models.Vehicle, error) {
+func (m *MockIdentityAPIService) FetchVehicleByTokenID(_ context.Context, tokenID int64) (*models.Vehicle, error) {
 	if m.MockFetchVehicleByTokenID != nil {
 		return m.MockFetchVehicleByTokenID(tokenID)
 	}
 	return nil, nil
 }
 
-func (m *MockIdentityAPIService) FetchVehiclesByWalletAddress(walletAddress string) ([]models.Vehicle, error) {
+func (m *MockIdentityAPIService) FetchVehiclesByWalletAddress(_ context.Context, walletAddress string) ([]models.Vehicle, error) {
 	if m.MockFetchVehiclesByWalletAddress != nil {
 		return m.MockFetchVehiclesByWalletAddress(walletAddress)
 	}
 	return nil, nil
 }
 
-func (m *MockIdentityAPIService) GetDeviceDefinitionByID(id string) (*models.DeviceDefinition, error) {
+func (m *MockIdentityAPIService) FetchVehiclesByTokenIDs(_ context.Context, tokenIDs []int64) (map[int64]models.Vehicle, error) {
+	if m.MockFetchVehiclesByTokenIDs != nil {
+		return m.MockFetchVehiclesByTokenIDs(tokenIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockIdentityAPIService) GetDeviceDefinitionByID(_ context.Context, id string) (*models.DeviceDefinition, error) {
 	if m.MockGetCachedDeviceDefinitionByID != nil {
 		return m.MockGetCachedDeviceDefinitionByID(id)
 	}
@@ -358,7 +366,7 @@ func (m *MockIdentityAPIService) GetCachedDeviceDefinitionByID(id string) (*mode
 	return nil, nil
 }
 
-func (m *MockIdentityAPIService) FetchDeviceDefinitionByID(id string) (*models.DeviceDefinition, error) {
+func (m *MockIdentityAPIService) FetchDeviceDefinitionByID(_ context.Context, id string) (*models.DeviceDefinition, error) {
 	if m.MockFetchDeviceDefinitionByID != nil {
 		return m.MockFetchDeviceDefinitionByID(id)
 	}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/friendsofgo/errors"
+	"github.com/patrickmn/go-cache"
+	"github.com/rs/zerolog"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSacd         = errors.New("invalid SACD")
+	ErrSacdSignerMismatch  = errors.New("SACD not signed by the owner")
+	ErrSacdGranteeMismatch = errors.New("SACD not granted to the developer license")
+	ErrSacdExpired         = errors.New("SACD expired")
+	ErrSacdRevoked         = errors.New("SACD revoked")
+)
+
+// Privileges of DIMO vehicles a SACD grants, every route a developer license calls on behalf of the owner requires one
+// of them
+const (
+	SacdPrivilegeNonLocationHistory = "privilege:GetNonLocationHistory"
+	SacdPrivilegeExecuteCommands    = "privilege:ExecuteCommands"
+)
+
+const (
+	sacdDocumentType      = "dimo.sacd"
+	sacdAgreementType     = "permission"
+	sacdAccountDIDPrefix  = "did:ethr:"
+	sacdVehicleDIDPrefix  = "did:erc721:"
+	sacdVerifiedCacheTime = 5 * time.Minute
+	// on-chain permission records are cached for a shorter time, revocations apply once it passed
+	sacdRecordCacheTime = time.Minute
+)
+
+// erc1271MagicValue returned by isValidSignature of smart accounts for valid signatures
+var erc1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+// sacdPermissionRecord permission record of the SACD contract, expiration in unix seconds
+type sacdPermissionRecord struct {
+	Permissions *big.Int
+	Expiration  *big.Int
+	Source      string
+}
+
+// sacdABI permission records of the DIMO SACD contract, the current record of a vehicle NFT or of the account of the
+// grantor for the grantee. Revoked records have no permissions left.
+var sacdABI = mustParseABI(`[{"name":"currentPermissionRecord","type":"function","stateMutability":"view","inputs":[{"name":"asset","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"grantee","type":"address"}],"outputs":[{"name":"","type":"tuple","components":[{"name":"permissions","type":"uint256"},{"name":"expiration","type":"uint256"},{"name":"source","type":"string"}]}]},{"name":"currentAccountPermissionRecord","type":"function","stateMutability":"view","inputs":[{"name":"grantor","type":"address"},{"name":"grantee","type":"address"}],"outputs":[{"name":"","type":"tuple","components":[{"name":"permissions","type":"uint256"},{"name":"expiration","type":"uint256"},{"name":"source","type":"string"}]}]}]`)
+
+var erc1271ABI = mustParseABI(`[{"name":"isValidSignature","type":"function","stateMutability":"view","inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],"outputs":[{"name":"magicValue","type":"bytes4"}]}]`)
+
+// SacdDocument is the SACD document signed by the grantor, as stored on IPFS and referenced by the source of the
+// permission record on chain
+type SacdDocument struct {
+	SpecVersion string          `json:"specversion"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+	Signature   string          `json:"signature"` // EIP-191 signature of data by the grantor
+}
+
+// SacdData grant of the document
+type SacdData struct {
+	Grantor     SacdParty       `json:"grantor"`
+	Grantee     SacdParty       `json:"grantee"`
+	EffectiveAt time.Time       `json:"effectiveAt"`
+	ExpiresAt   time.Time       `json:"expiresAt"`
+	Agreements  []SacdAgreement `json:"agreements"`
+}
+
+type SacdParty struct {
+	Address common.Address `json:"address"`
+}
+
+// SacdAgreement grants privileges on an asset, the account of the grantor (did:ethr) covers all its vehicles, a vehicle
+// NFT (did:erc721) only that vehicle. Dates left empty are the ones of the document.
+type SacdAgreement struct {
+	Type        string           `json:"type"`
+	Asset       string           `json:"asset"`
+	Permissions []SacdPermission `json:"permissions"`
+	EffectiveAt time.Time        `json:"effectiveAt,omitempty"`
+	ExpiresAt   time.Time        `json:"expiresAt,omitempty"`
+}
+
+type SacdPermission struct {
+	Name string `json:"name"`
+}
+
+// SacdGrant privileges a verified SACD grants to the developer license
+type SacdGrant struct {
+	Grantor   common.Address
+	Grantee   common.Address
+	ExpiresAt time.Time
+	account   map[string]struct{}
+	vehicles  map[int64]map[string]struct{}
+}
+
+// AllowsAllVehicles tells whether the privilege is granted on every vehicle of the owner
+func (g *SacdGrant) AllowsAllVehicles(privilege string) bool {
+	_, ok := g.account[privilege]
+	return ok
+}
+
+// AllowsVehicle tells whether the privilege is granted on the vehicle, vehicles which aren't minted (token ID 0) are
+// only covered by grants on all vehicles
+func (g *SacdGrant) AllowsVehicle(privilege string, tokenID int64) bool {
+	if g.AllowsAllVehicles(privilege) {
+		return true
+	}
+	if tokenID == 0 {
+		return false
+	}
+
+	_, ok := g.vehicles[tokenID][privilege]
+	return ok
+}
+
+// AllowsAnyVehicle tells whether the privilege is granted on some vehicle
+func (g *SacdGrant) AllowsAnyVehicle(privilege string) bool {
+	if g.AllowsAllVehicles(privilege) {
+		return true
+	}
+
+	for _, privileges := range g.vehicles {
+		if _, ok := privileges[privilege]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EncodeSacdDocument encodes the document as sent in a header, base64url of its JSON
+func EncodeSacdDocument(document SacdDocument) string {
+	data, _ := json.Marshal(document)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// SacdVerifier checks SACD documents of vehicle owners. Owners are smart accounts, their signatures are checked with
+// ERC-1271 isValidSignature of the account contract, other wallets sign with their key. Grants must still be recorded
+// in the SACD contract, the owner revokes them there.
+type SacdVerifier struct {
+	logger            zerolog.Logger
+	caller            ethereum.ContractCaller
+	chainID           int64
+	vehicleNftAddress common.Address
+	sacdAddress       common.Address
+	verified          *cache.Cache
+	records           *cache.Cache
+}
+
+// NewSacdVerifier creates a verifier calling smart accounts and the SACD contract through the RPC URL. Without one,
+// only signatures of wallet keys are accepted and no SACD passes the revocation check.
+func NewSacdVerifier(logger zerolog.Logger, settings *config.Settings) *SacdVerifier {
+	var caller ethereum.ContractCaller
+	if settings.RPCURL.String() != "" {
+		client, err := ethclient.Dial(settings.RPCURL.String())
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to create RPC client, SACDs of smart accounts can't be verified")
+		} else {
+			caller = client
+		}
+	}
+
+	return NewSacdVerifierWithCaller(logger, caller, settings.ChainID, settings.VehicleNftAddress, settings.SacdContractAddress)
+}
+
+func NewSacdVerifierWithCaller(logger zerolog.Logger, caller ethereum.ContractCaller, chainID int64, vehicleNftAddress, sacdAddress common.Address) *SacdVerifier {
+	return &SacdVerifier{
+		logger:            logger,
+		caller:            caller,
+		chainID:           chainID,
+		vehicleNftAddress: vehicleNftAddress,
+		sacdAddress:       sacdAddress,
+		verified:          cache.New(sacdVerifiedCacheTime, 2*sacdVerifiedCacheTime),
+		records:           cache.New(sacdRecordCacheTime, 2*sacdRecordCacheTime),
+	}
+}
+
+// Verify checks that the encoded document was signed by the owner, is granted to the developer license, is in effect,
+// wasn't revoked on chain and grants privileges on some vehicles. Callers check the privileges and vehicles they need.
+func (v *SacdVerifier) Verify(ctx context.Context, encoded string, owner, grantee common.Address, now time.Time) (*SacdGrant, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(encoded), "="))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSacd, err.Error())
+	}
+
+	var document SacdDocument
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, errors.Wrap(ErrInvalidSacd, err.Error())
+	}
+	if document.Type != sacdDocumentType {
+		return nil, errors.Wrap(ErrInvalidSacd, "not a SACD document")
+	}
+
+	var data SacdData
+	if err := json.Unmarshal(document.Data, &data); err != nil {
+		return nil, errors.Wrap(ErrInvalidSacd, err.Error())
+	}
+
+	signature, err := hexutil.Decode(document.Signature)
+	if err != nil || len(signature) == 0 {
+		return nil, errors.Wrap(ErrInvalidSacd, "malformed signature")
+	}
+
+	if data.Grantor.Address != owner {
+		return nil, ErrSacdSignerMismatch
+	}
+
+	if data.Grantee.Address != grantee {
+		return nil, ErrSacdGranteeMismatch
+	}
+
+	if !data.ExpiresAt.After(now) {
+		return nil, ErrSacdExpired
+	}
+
+	if data.EffectiveAt.After(now) {
+		return nil, errors.Wrap(ErrInvalidSacd, "SACD not in effect yet")
+	}
+
+	grant := v.grantOf(&data, now)
+	if len(grant.account) == 0 && len(grant.vehicles) == 0 {
+		return nil, errors.Wrap(ErrInvalidSacd, "no privileges granted")
+	}
+
+	valid, err := v.isSignedBy(ctx, owner, document.Data, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify SACD signature")
+	}
+	if !valid {
+		return nil, ErrSacdSignerMismatch
+	}
+
+	if err := v.dropRevoked(ctx, grant, now); err != nil {
+		return nil, errors.Wrap(err, "failed to check SACD revocation")
+	}
+	if len(grant.account) == 0 && len(grant.vehicles) == 0 {
+		return nil, ErrSacdRevoked
+	}
+
+	return grant, nil
+}
+
+// dropRevoked removes the assets of the grant whose permission record in the SACD contract was revoked or expired
+func (v *SacdVerifier) dropRevoked(ctx context.Context, grant *SacdGrant, now time.Time) error {
+	if v.caller == nil || v.sacdAddress == (common.Address{}) {
+		return errors.New("SACD contract not configured")
+	}
+
+	if len(grant.account) > 0 {
+		active, err := v.isRecordActive(ctx, now, "currentAccountPermissionRecord", grant.Grantor, grant.Grantee)
+		if err != nil {
+			return err
+		}
+		if !active {
+			v.logger.Info().Str("grantor", grant.Grantor.Hex()).Str("grantee", grant.Grantee.Hex()).Msg("SACD of the account revoked on chain")
+			clear(grant.account)
+		}
+	}
+
+	for tokenID := range grant.vehicles {
+		active, err := v.isRecordActive(ctx, now, "currentPermissionRecord", v.vehicleNftAddress, big.NewInt(tokenID), grant.Grantee)
+		if err != nil {
+			return err
+		}
+		if !active {
+			v.logger.Info().Int64("vehicleTokenId", tokenID).Str("grantee", grant.Grantee.Hex()).Msg("SACD of the vehicle revoked on chain")
+			delete(grant.vehicles, tokenID)
+		}
+	}
+
+	return nil
+}
+
+// isRecordActive reads the permission record from the SACD contract, records are active while they grant permissions
+// and didn't expire. Records are cached for a minute.
+func (v *SacdVerifier) isRecordActive(ctx context.Context, now time.Time, method string, args ...interface{}) (bool, error) {
+	input, err := sacdABI.Pack(method, args...)
+	if err != nil {
+		return false, err
+	}
+
+	cacheKey := hexutil.Encode(input)
+	if expiration, ok := v.records.Get(cacheKey); ok {
+		return now.Before(expiration.(time.Time)), nil
+	}
+
+	output, err := v.caller.CallContract(ctx, ethereum.CallMsg{To: &v.sacdAddress, Data: input}, nil)
+	if err != nil {
+		return false, err
+	}
+
+	unpacked, err := sacdABI.Unpack(method, output)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid permission record")
+	}
+	if len(unpacked) != 1 {
+		return false, errors.New("invalid permission record")
+	}
+	record := *abi.ConvertType(unpacked[0], new(sacdPermissionRecord)).(*sacdPermissionRecord)
+
+	// revoked records have neither permissions nor an expiration
+	var expiration time.Time
+	if record.Permissions.Sign() > 0 && record.Expiration.IsInt64() {
+		expiration = time.Unix(record.Expiration.Int64(), 0)
+	}
+	v.records.SetDefault(cacheKey, expiration)
+
+	return now.Before(expiration), nil
+}
+
+// grantOf collects the privileges of the agreements in effect, assets of other chains, contracts or accounts are ignored
+func (v *SacdVerifier) grantOf(data *SacdData, now time.Time) *SacdGrant {
+	grant := &SacdGrant{
+		Grantor:   data.Grantor.Address,
+		Grantee:   data.Grantee.Address,
+		ExpiresAt: data.ExpiresAt,
+		account:   make(map[string]struct{}),
+		vehicles:  make(map[int64]map[string]struct{}),
+	}
+
+	for _, agreement := range data.Agreements {
+		if agreement.Type != sacdAgreementType || len(agreement.Permissions) == 0 {
+			continue
+		}
+		if !agreement.EffectiveAt.IsZero() && agreement.EffectiveAt.After(now) {
+			continue
+		}
+		if !agreement.ExpiresAt.IsZero() && !agreement.ExpiresAt.After(now) {
+			continue
+		}
+
+		var privileges map[string]struct{}
+		if v.isAccountAsset(agreement.Asset, data.Grantor.Address) {
+			privileges = grant.account
+		} else if tokenID, ok := v.vehicleAsset(agreement.Asset); ok {
+			if grant.vehicles[tokenID] == nil {
+				grant.vehicles[tokenID] = make(map[string]struct{})
+			}
+			privileges = grant.vehicles[tokenID]
+		} else {
+			continue
+		}
+
+		for _, permission := range agreement.Permissions {
+			privileges[permission.Name] = struct{}{}
+		}
+	}
+
+	return grant
+}
+
+// isAccountAsset checks the asset is the account of the grantor, did:ethr:<chain ID>:<address>
+func (v *SacdVerifier) isAccountAsset(asset string, grantor common.Address) bool {
+	rest, ok := strings.CutPrefix(asset, sacdAccountDIDPrefix)
+	if !ok {
+		return false
+	}
+
+	parts := strings.Split(rest, ":")
+	return len(parts) == 2 && parts[0] == strconv.FormatInt(v.chainID, 10) &&
+		common.IsHexAddress(parts[1]) && common.HexToAddress(parts[1]) == grantor
+}
+
+// vehicleAsset returns the token ID of a vehicle NFT asset, did:erc721:<chain ID>:<contract>:<token ID>
+func (v *SacdVerifier) vehicleAsset(asset string) (int64, bool) {
+	rest, ok := strings.CutPrefix(asset, sacdVehicleDIDPrefix)
+	if !ok {
+		return 0, false
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 || parts[0] != strconv.FormatInt(v.chainID, 10) ||
+		!common.IsHexAddress(parts[1]) || common.HexToAddress(parts[1]) != v.vehicleNftAddress {
+		return 0, false
+	}
+
+	tokenID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || tokenID <= 0 {
+		return 0, false
+	}
+
+	return tokenID, true
+}
+
+// isSignedBy checks the EIP-191 signature of the payload, signatures which don't recover to the signer are checked
+// with ERC-1271 of the signer's smart account. Results are cached, fleet operators send the same SACD on every request.
+func (v *SacdVerifier) isSignedBy(ctx context.Context, signer common.Address, payload, signature []byte) (bool, error) {
+	hash := accounts.TextHash(payload)
+	cacheKey := signer.Hex() + ":" + hexutil.Encode(hash) + ":" + hexutil.Encode(signature)
+	if valid, ok := v.verified.Get(cacheKey); ok {
+		return valid.(bool), nil
+	}
+
+	if recoversTo(hash, signature, signer) {
+		v.verified.SetDefault(cacheKey, true)
+		return true, nil
+	}
+
+	if v.caller == nil {
+		return false, nil
+	}
+
+	valid, err := v.isValidERC1271Signature(ctx, signer, hash, signature)
+	if err != nil {
+		return false, err
+	}
+	v.verified.SetDefault(cacheKey, valid)
+
+	return valid, nil
+}
+
+// recoversTo checks a signature of a wallet key, wallets sign with v of 27 or 28
+func recoversTo(hash, signature []byte, signer common.Address) bool {
+	if len(signature) != crypto.SignatureLength {
+		return false
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return false
+	}
+
+	return crypto.PubkeyToAddress(*publicKey) == signer
+}
+
+// isValidERC1271Signature calls isValidSignature of the smart account, accounts without code or which don't answer with
+// the magic value don't accept the signature
+func (v *SacdVerifier) isValidERC1271Signature(ctx context.Context, account common.Address, hash, signature []byte) (bool, error) {
+	input, err := erc1271ABI.Pack("isValidSignature", common.BytesToHash(hash), signature)
+	if err != nil {
+		return false, err
+	}
+
+	output, err := v.caller.CallContract(ctx, ethereum.CallMsg{To: &account, Data: input}, nil)
+	if err != nil {
+		// reverts are rejections, other errors mean the chain couldn't be asked
+		if strings.Contains(err.Error(), "execution reverted") {
+			v.logger.Debug().Err(err).Str("account", account.Hex()).Msg("isValidSignature reverted")
+			return false, nil
+		}
+		return false, err
+	}
+
+	if len(output) < 4 {
+		return false, nil
+	}
+
+	var magicValue [4]byte
+	copy(magicValue[:], output[:4])
+
+	return magicValue == erc1271MagicValue, nil
+}
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI: %v", err))
+	}
+
+	return parsed
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var (
+	sacdTestVehicleNft = common.HexToAddress("0xbA5738a18d83D41847dfFbDC6101d37C69c9B0cF")
+	sacdTestContract   = common.HexToAddress("0x0000000000000000000000000000000000005acd")
+)
+
+// fakeSmartAccount answers isValidSignature of the account, accepting signatures of its signer key, and the permission
+// records of the SACD contract
+type fakeSmartAccount struct {
+	account common.Address
+	signer  common.Address
+	calls   int
+	// permission records revoked in the SACD contract
+	accountRevoked  bool
+	vehiclesRevoked map[int64]bool
+	recordCalls     int
+}
+
+func (f *fakeSmartAccount) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if call.To != nil && *call.To == sacdTestContract {
+		return f.permissionRecord(call.Data)
+	}
+
+	f.calls++
+	if call.To == nil || *call.To != f.account {
+		// wallets without code return nothing
+		return nil, nil
+	}
+
+	args, err := erc1271ABI.Methods["isValidSignature"].Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	hash := args[0].([32]byte)
+	signature := args[1].([]byte)
+
+	if recoversTo(hash[:], signature, f.signer) {
+		return common.RightPadBytes(erc1271MagicValue[:], 32), nil
+	}
+
+	return common.RightPadBytes([]byte{0xff, 0xff, 0xff, 0xff}, 32), nil
+}
+
+func (f *fakeSmartAccount) permissionRecord(data []byte) ([]byte, error) {
+	f.recordCalls++
+
+	method, err := sacdABI.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	revoked := f.accountRevoked
+	if method.Name == "currentPermissionRecord" {
+		revoked = f.vehiclesRevoked[args[1].(*big.Int).Int64()]
+	}
+
+	// revoked records are cleared
+	record := sacdPermissionRecord{Permissions: new(big.Int), Expiration: new(big.Int)}
+	if !revoked {
+		record = sacdPermissionRecord{Permissions: big.NewInt(3), Expiration: big.NewInt(2_000_000_000), Source: "ipfs://sacd"}
+	}
+
+	return method.Outputs.Pack(record)
+}
+
+type SacdTestSuite struct {
+	suite.Suite
+	key          *ecdsa.PrivateKey
+	owner        common.Address
+	license      common.Address
+	now          time.Time
+	smartAccount *fakeSmartAccount
+	verifier     *SacdVerifier
+}
+
+func TestSacdTestSuite(t *testing.T) {
+	suite.Run(t, new(SacdTestSuite))
+}
+
+func (s *SacdTestSuite) SetupTest() {
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	s.key = key
+	s.owner = crypto.PubkeyToAddress(key.PublicKey)
+	s.license = common.HexToAddress("0x0000000000000000000000000000000000000123")
+	s.now = time.Unix(1_700_000_000, 0).UTC()
+	s.smartAccount = &fakeSmartAccount{
+		account:         common.HexToAddress("0x0000000000000000000000000000000000000abc"),
+		signer:          s.owner,
+		vehiclesRevoked: make(map[int64]bool),
+	}
+	s.verifier = NewSacdVerifierWithCaller(zerolog.Nop(), s.smartAccount, 137, sacdTestVehicleNft, sacdTestContract)
+}
+
+func (s *SacdTestSuite) data(grantor common.Address, agreements ...SacdAgreement) SacdData {
+	return SacdData{
+		Grantor:     SacdParty{Address: grantor},
+		Grantee:     SacdParty{Address: s.license},
+		EffectiveAt: s.now.Add(-time.Hour),
+		ExpiresAt:   s.now.Add(time.Hour),
+		Agreements:  agreements,
+	}
+}
+
+func (s *SacdTestSuite) sign(data SacdData, key *ecdsa.PrivateKey) string {
+	payload, err := json.Marshal(data)
+	s.Require().NoError(err)
+
+	signature, err := crypto.Sign(accounts.TextHash(payload), key)
+	s.Require().NoError(err)
+	// as returned by wallets
+	signature[crypto.RecoveryIDOffset] += 27
+
+	return EncodeSacdDocument(SacdDocument{
+		SpecVersion: "1.0",
+		Type:        sacdDocumentType,
+		Data:        payload,
+		Signature:   hexutil.Encode(signature),
+	})
+}
+
+func accountAgreement(grantor common.Address, privileges ...string) SacdAgreement {
+	return SacdAgreement{Type: sacdAgreementType, Asset: "did:ethr:137:" + grantor.Hex(), Permissions: permissionsOf(privileges)}
+}
+
+func vehicleAgreement(tokenID int64, privileges ...string) SacdAgreement {
+	return SacdAgreement{Type: sacdAgreementType, Asset: fmt.Sprintf("did:erc721:137:%s:%d", sacdTestVehicleNft.Hex(), tokenID), Permissions: permissionsOf(privileges)}
+}
+
+func permissionsOf(privileges []string) []SacdPermission {
+	permissions := make([]SacdPermission, 0, len(privileges))
+	for _, privilege := range privileges {
+		permissions = append(permissions, SacdPermission{Name: privilege})
+	}
+
+	return permissions
+}
+
+func (s *SacdTestSuite) TestVerify() {
+	encoded := s.sign(s.data(s.owner, accountAgreement(s.owner, SacdPrivilegeNonLocationHistory)), s.key)
+
+	grant, err := s.verifier.Verify(context.Background(), encoded, s.owner, s.license, s.now)
+	s.Require().NoError(err)
+	s.Equal(s.owner, grant.Grantor)
+	s.Equal(s.license, grant.Grantee)
+	s.True(grant.AllowsAllVehicles(SacdPrivilegeNonLocationHistory))
+	s.False(grant.AllowsAllVehicles(SacdPrivilegeExecuteCommands))
+	// wallets without code aren't asked
+	s.Zero(s.smartAccount.calls)
+}
+
+func (s *SacdTestSuite) TestVerify_SmartAccount() {
+	account := s.smartAccount.account
+	encoded := s.sign(s.data(account, vehicleAgreement(7, SacdPrivilegeExecuteCommands)), s.key)
+
+	grant, err := s.verifier.Verify(context.Background(), encoded, account, s.license, s.now)
+	s.Require().NoError(err)
+	s.True(grant.AllowsVehicle(SacdPrivilegeExecuteCommands, 7))
+	s.Equal(1, s.smartAccount.calls)
+
+	// verified signatures are cached
+	_, err = s.verifier.Verify(context.Background(), encoded, account, s.license, s.now)
+	s.Require().NoError(err)
+	s.Equal(1, s.smartAccount.calls)
+
+	otherKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	_, err = s.verifier.Verify(context.Background(), s.sign(s.data(account, vehicleAgreement(7, SacdPrivilegeExecuteCommands)), otherKey), account, s.license, s.now)
+	s.ErrorIs(err, ErrSacdSignerMismatch)
+}
+
+func (s *SacdTestSuite) TestVerify_Rejected() {
+	otherKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	valid := s.data(s.owner, accountAgreement(s.owner, SacdPrivilegeNonLocationHistory))
+
+	_, err = s.verifier.Verify(context.Background(), s.sign(valid, otherKey), s.owner, s.license, s.now)
+	s.ErrorIs(err, ErrSacdSignerMismatch)
+
+	otherGrantor := valid
+	otherGrantor.Grantor = SacdParty{Address: crypto.PubkeyToAddress(otherKey.PublicKey)}
+	_, err = s.verifier.Verify(context.Background(), s.sign(otherGrantor, otherKey), s.owner, s.license, s.now)
+	s.ErrorIs(err, ErrSacdSignerMismatch)
+
+	otherGrantee := valid
+	otherGrantee.Grantee = SacdParty{Address: common.HexToAddress("0x0000000000000000000000000000000000000456")}
+	_, err = s.verifier.Verify(context.Background(), s.sign(otherGrantee, s.key), s.owner, s.license, s.now)
+	s.ErrorIs(err, ErrSacdGranteeMismatch)
+
+	expired := valid
+	expired.ExpiresAt = s.now
+	_, err = s.verifier.Verify(context.Background(), s.sign(expired, s.key), s.owner, s.license, s.now)
+	s.ErrorIs(err, ErrSacdExpired)
+
+	notYet := valid
+	notYet.EffectiveAt = s.now.Add(time.Minute)
+	_, err = s.verifier.Verify(context.Background(), s.sign(notYet, s.key), s.owner, s.license, s.now)
+	s.ErrorIs(err, ErrInvalidSacd)
+
+	// assets of other accounts, chains or contracts grant nothing
+	for _, asset := range []string{
+		"did:ethr:137:0x0000000000000000000000000000000000000456",
+		"did:ethr:80002:" + s.owner.Hex(),
+		"did:erc721:137:0x0000000000000000000000000000000000000456:7",
+		"did:erc721:137:" + sacdTestVehicleNft.Hex() + ":notAToken",
+	} {
+		other := s.data(s.owner, SacdAgreement{Type: sacdAgreementType, Asset: asset, Permissions: permissionsOf([]string{SacdPrivilegeNonLocationHistory})})
+		_, err = s.verifier.Verify(context.Background(), s.sign(other, s.key), s.owner, s.license, s.now)
+		s.ErrorIs(err, ErrInvalidSacd, asset)
+	}
+
+	_, err = s.verifier.Verify(context.Background(), "", s.owner, s.license, s.now)
+	s.ErrorIs(err, ErrInvalidSacd)
+
+	_, err = s.verifier.Verify(context.Background(), EncodeSacdDocument(SacdDocument{Type: "dimo.other", Data: []byte("{}"), Signature: "0x1234"}), s.owner, s.license, s.now)
+	s.ErrorIs(err, ErrInvalidSacd)
+}
+
+func (s *SacdTestSuite) TestVerify_Revoked() {
+	vehicles := s.sign(s.data(s.owner,
+		vehicleAgreement(7, SacdPrivilegeExecuteCommands),
+		vehicleAgreement(8, SacdPrivilegeExecuteCommands),
+	), s.key)
+	s.smartAccount.vehiclesRevoked[7] = true
+
+	// revoked vehicles are left out of the grant
+	grant, err := s.verifier.Verify(context.Background(), vehicles, s.owner, s.license, s.now)
+	s.Require().NoError(err)
+	s.False(grant.AllowsVehicle(SacdPrivilegeExecuteCommands, 7))
+	s.True(grant.AllowsVehicle(SacdPrivilegeExecuteCommands, 8))
+	s.Equal(2, s.smartAccount.recordCalls)
+
+	// records are cached
+	_, err = s.verifier.Verify(context.Background(), vehicles, s.owner, s.license, s.now)
+	s.Require().NoError(err)
+	s.Equal(2, s.smartAccount.recordCalls)
+
+	// records expired on chain, though the document didn't
+	longLived := s.data(s.owner, vehicleAgreement(8, SacdPrivilegeExecuteCommands))
+	longLived.ExpiresAt = time.Unix(3_000_000_000, 0)
+	_, err = s.verifier.Verify(context.Background(), s.sign(longLived, s.key), s.owner, s.license, time.Unix(2_000_000_000, 0))
+	s.ErrorIs(err, ErrSacdRevoked)
+
+	s.smartAccount.accountRevoked = true
+	_, err = s.verifier.Verify(context.Background(), s.sign(s.data(s.owner, accountAgreement(s.owner, SacdPrivilegeNonLocationHistory)), s.key), s.owner, s.license, s.now)
+	s.ErrorIs(err, ErrSacdRevoked)
+
+	// without the SACD contract revocations can't be checked
+	unchecked := NewSacdVerifierWithCaller(zerolog.Nop(), s.smartAccount, 137, sacdTestVehicleNft, common.Address{})
+	_, err = unchecked.Verify(context.Background(), vehicles, s.owner, s.license, s.now)
+	s.Error(err)
+	s.NotErrorIs(err, ErrSacdRevoked)
+}
+
+func (s *SacdTestSuite) TestSacdGrant_Scope() {
+	data := s.data(s.owner,
+		vehicleAgreement(7, SacdPrivilegeNonLocationHistory, SacdPrivilegeExecuteCommands),
+		vehicleAgreement(8, SacdPrivilegeNonLocationHistory),
+		// expired agreements grant nothing
+		SacdAgreement{Type: sacdAgreementType, Asset: "did:ethr:137:" + s.owner.Hex(), Permissions: permissionsOf([]string{SacdPrivilegeExecuteCommands}), ExpiresAt: s.now},
+	)
+	grant := s.verifier.grantOf(&data, s.now)
+
+	s.False(grant.AllowsAllVehicles(SacdPrivilegeNonLocationHistory))
+	s.False(grant.AllowsAllVehicles(SacdPrivilegeExecuteCommands))
+	s.True(grant.AllowsAnyVehicle(SacdPrivilegeExecuteCommands))
+	s.True(grant.AllowsVehicle(SacdPrivilegeExecuteCommands, 7))
+	s.False(grant.AllowsVehicle(SacdPrivilegeExecuteCommands, 8))
+	s.True(grant.AllowsVehicle(SacdPrivilegeNonLocationHistory, 8))
+	// not minted
+	s.False(grant.AllowsVehicle(SacdPrivilegeNonLocationHistory, 0))
+
+	all := s.data(s.owner, accountAgreement(s.owner, SacdPrivilegeNonLocationHistory))
+	grant = s.verifier.grantOf(&all, s.now)
+	s.True(grant.AllowsAllVehicles(SacdPrivilegeNonLocationHistory))
+	s.True(grant.AllowsVehicle(SacdPrivilegeNonLocationHistory, 0))
+	s.True(grant.AllowsVehicle(SacdPrivilegeNonLocationHistory, 9))
+	s.False(grant.AllowsAnyVehicle(SacdPrivilegeExecuteCommands))
+}
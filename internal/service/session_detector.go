@@ -0,0 +1,705 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DIMO-Network/cloudevent"
+	"github.com/DIMO-Network/shared/pkg/db"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/DIMO-Network/volteras-oracle/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"sync"
+	"time"
+)
+
+const (
+	eventNameTrip            = "trip"
+	eventNameChargingSession = "chargingSession"
+
+	// states of VINs not reporting for this long are dropped, open sessions are loaded again when they report
+	sessionStateTTL = time.Hour
+	// how often states are checked for eviction
+	sessionStateSweep = 10 * time.Minute
+	// how often the last moving time of an open trip is stored
+	tripMovingPersist = time.Minute
+	// events of ended sessions DIS didn't accept are sent again after this long, the detection sends them first
+	sessionEventRetryDelay = time.Minute
+	// sessions closed or events sent again at once
+	sessionEventsBatchSize = 100
+)
+
+// sessionEvent is the DIMO event of an ended session, it's sent until DIS accepts it
+type sessionEvent struct {
+	vin       string
+	sessionID string
+	// CloudEvent header of the telemetry the session started with
+	header null.JSON
+	event  models.Event
+}
+
+type sessionState struct {
+	// held while the signals of the VIN are processed, DB writes of a VIN don't block the other VINs
+	m      sync.Mutex
+	loaded bool
+	// guarded by the detector mutex
+	users  int
+	seenAt time.Time
+
+	trip              *dbmodels.Trip
+	lastMovingAt      time.Time
+	persistedMovingAt time.Time
+	latitude          null.Float64
+	longitude         null.Float64
+	odometer          null.Float64
+
+	charging    *dbmodels.ChargingSession
+	soc         null.Float64
+	startEnergy null.Float64
+	energy      null.Float64
+}
+
+// SessionDetector derives trips and charging sessions from the raw telemetry of a VIN.
+// A trip starts when the speed reaches the start speed and ends after the vehicle stood still for the idle time, or
+// when it didn't report for longer than that, a charging session follows the isCharging signal. Sessions are persisted
+// when they start and when they end, ended sessions are returned as DIMO events. Sessions of VINs which stopped
+// reporting are closed by closeIdle, events stay pending until markSent. A nil detector detects nothing.
+type SessionDetector struct {
+	pdb            *db.Store
+	logger         zerolog.Logger
+	tripStartSpeed float64
+	tripEndIdle    time.Duration
+	states         map[string]*sessionState
+	sweptAt        time.Time
+	m              sync.Mutex
+}
+
+func newSessionDetector(pdb *db.Store, logger zerolog.Logger, settings config.Settings) *SessionDetector {
+	if !settings.EnableSessionDetection {
+		return nil
+	}
+
+	tripStartSpeed := settings.TripStartSpeed
+	if tripStartSpeed <= 0 {
+		tripStartSpeed = 5
+	}
+
+	tripEndIdle := time.Duration(settings.TripEndIdleMinutes) * time.Minute
+	if tripEndIdle <= 0 {
+		tripEndIdle = 5 * time.Minute
+	}
+
+	return &SessionDetector{
+		pdb:            pdb,
+		logger:         logger,
+		tripStartSpeed: tripStartSpeed,
+		tripEndIdle:    tripEndIdle,
+		states:         make(map[string]*sessionState),
+		sweptAt:        time.Now(),
+	}
+}
+
+// Detect updates the VIN state with the signals and returns events for trips and charging sessions which ended. The
+// header of the telemetry is stored with started sessions, their events are sent with it.
+func (d *SessionDetector) Detect(ctx context.Context, vin string, header cloudevent.CloudEventHeader, signals []interface{}, ts time.Time) ([]sessionEvent, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	values := signalValues(signals, ts)
+	eventHeader, err := sessionEventHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	state := d.acquireState(vin)
+	defer d.releaseState(state)
+
+	state.m.Lock()
+	defer state.m.Unlock()
+
+	if err := d.loadState(ctx, vin, state); err != nil {
+		return nil, err
+	}
+
+	var events []sessionEvent
+
+	tripEvent, err := d.detectTrip(ctx, vin, state, values, ts, eventHeader)
+	if err != nil {
+		return nil, err
+	}
+	if tripEvent != nil {
+		events = append(events, *tripEvent)
+	}
+
+	chargingEvent, err := d.detectCharging(ctx, vin, state, values, eventHeader)
+	if err != nil {
+		return nil, err
+	}
+	if chargingEvent != nil {
+		events = append(events, *chargingEvent)
+	}
+
+	return events, nil
+}
+
+// sessionEventHeader the header stored with sessions, events get their own ID, type and time
+func sessionEventHeader(header cloudevent.CloudEventHeader) (null.JSON, error) {
+	header.ID = ""
+	header.Extras = nil
+	b, err := json.Marshal(header)
+	if err != nil {
+		return null.JSON{}, err
+	}
+
+	return null.JSONFrom(b), nil
+}
+
+// acquireState returns the state of the VIN and keeps it from being evicted until it's released
+func (d *SessionDetector) acquireState(vin string) *sessionState {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	now := time.Now()
+	if now.Sub(d.sweptAt) >= sessionStateSweep {
+		d.evict(now)
+		d.sweptAt = now
+	}
+
+	state, ok := d.states[vin]
+	if !ok {
+		state = &sessionState{}
+		d.states[vin] = state
+	}
+	state.users++
+	state.seenAt = now
+
+	return state
+}
+
+func (d *SessionDetector) releaseState(state *sessionState) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	state.users--
+}
+
+// evict drops the states of VINs which didn't report within the TTL, d.m must be held
+func (d *SessionDetector) evict(now time.Time) {
+	for vin, state := range d.states {
+		if state.users == 0 && now.Sub(state.seenAt) >= sessionStateTTL {
+			delete(d.states, vin)
+		}
+	}
+}
+
+// loadState picks up the sessions still open in the DB when the VIN reports for the first time, eg. after restart
+func (d *SessionDetector) loadState(ctx context.Context, vin string, state *sessionState) error {
+	if state.loaded {
+		return nil
+	}
+
+	trip, err := dbmodels.Trips(
+		dbmodels.TripWhere.Vin.EQ(vin),
+		dbmodels.TripWhere.EndTime.IsNull(),
+		qm.OrderBy(dbmodels.TripColumns.StartTime+" DESC"),
+	).One(ctx, d.pdb.DBS().Reader)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to load open trip: %w", err)
+	}
+	if trip != nil {
+		state.trip = trip
+		state.lastMovingAt = trip.StartTime
+		if trip.LastMovingAt.Valid {
+			state.lastMovingAt = trip.LastMovingAt.Time
+		}
+		state.persistedMovingAt = state.lastMovingAt
+	}
+
+	charging, err := dbmodels.ChargingSessions(
+		dbmodels.ChargingSessionWhere.Vin.EQ(vin),
+		dbmodels.ChargingSessionWhere.EndTime.IsNull(),
+		qm.OrderBy(dbmodels.ChargingSessionColumns.StartTime+" DESC"),
+	).One(ctx, d.pdb.DBS().Reader)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to load open charging session: %w", err)
+	}
+	if charging != nil {
+		state.charging = charging
+		state.soc = charging.StartSoc
+	}
+
+	state.loaded = true
+
+	return nil
+}
+
+func (d *SessionDetector) detectTrip(ctx context.Context, vin string, state *sessionState, values map[string]signalValue, ts time.Time, header null.JSON) (*sessionEvent, error) {
+	speed, hasSpeed := values[signalSpeed]
+	now := ts
+	if hasSpeed {
+		now = speed.Timestamp
+	}
+	moving := hasSpeed && speed.Value >= d.tripStartSpeed
+
+	// the open trip ends where the vehicle last moved, when it stood still or didn't report for the idle time. The
+	// position is the one before these signals, a vehicle moving again after a gap starts a new trip
+	var event *sessionEvent
+	if state.trip != nil && now.Sub(state.lastMovingAt) >= d.tripEndIdle {
+		var err error
+		if event, err = d.endTrip(ctx, vin, state, header); err != nil {
+			return nil, err
+		}
+	}
+
+	// keep the last known position and odometer, trips start and end there
+	if lat, ok := values[signalLocationLatitude]; ok {
+		state.latitude = null.Float64From(lat.Value)
+	}
+	if lon, ok := values[signalLocationLongitude]; ok {
+		state.longitude = null.Float64From(lon.Value)
+	}
+	if odometer, ok := values[signalOdometer]; ok {
+		state.odometer = null.Float64From(odometer.Value)
+	}
+
+	if !moving {
+		return event, nil
+	}
+
+	if speed.Timestamp.After(state.lastMovingAt) {
+		state.lastMovingAt = speed.Timestamp
+	}
+
+	if state.trip == nil {
+		trip := &dbmodels.Trip{
+			ID:             ksuid.New().String(),
+			Vin:            vin,
+			StartTime:      speed.Timestamp,
+			StartLatitude:  state.latitude,
+			StartLongitude: state.longitude,
+			StartOdometer:  state.odometer,
+			LastMovingAt:   null.TimeFrom(speed.Timestamp),
+			EventHeader:    header,
+		}
+		if err := trip.Insert(ctx, d.pdb.DBS().Writer, boil.Infer()); err != nil {
+			return nil, fmt.Errorf("failed to insert trip: %w", err)
+		}
+		state.trip = trip
+		state.persistedMovingAt = speed.Timestamp
+		d.logger.Debug().Str("vin", vin).Str("tripId", trip.ID).Msg("Trip started")
+
+		return event, nil
+	}
+
+	// stored now and then, so a restart doesn't stretch the trip to the start time or the next signals
+	if state.lastMovingAt.Sub(state.persistedMovingAt) >= tripMovingPersist {
+		state.trip.LastMovingAt = null.TimeFrom(state.lastMovingAt)
+		if _, err := state.trip.Update(ctx, d.pdb.DBS().Writer, boil.Whitelist(dbmodels.TripColumns.LastMovingAt, dbmodels.TripColumns.UpdatedAt)); err != nil {
+			return nil, fmt.Errorf("failed to update trip: %w", err)
+		}
+		state.persistedMovingAt = state.lastMovingAt
+	}
+
+	return event, nil
+}
+
+// endTrip closes the open trip of the VIN at the last time it moved and returns its event. Trips already closed, eg.
+// by the idle sweep of another instance, return no event.
+func (d *SessionDetector) endTrip(ctx context.Context, vin string, state *sessionState, header null.JSON) (*sessionEvent, error) {
+	trip := *state.trip
+	trip.EndTime = null.TimeFrom(state.lastMovingAt)
+	trip.LastMovingAt = null.TimeFrom(state.lastMovingAt)
+	trip.EndLatitude = state.latitude
+	trip.EndLongitude = state.longitude
+	trip.EndOdometer = state.odometer
+	if trip.StartOdometer.Valid && trip.EndOdometer.Valid {
+		trip.DistanceKM = null.Float64From(trip.EndOdometer.Float64 - trip.StartOdometer.Float64)
+	}
+	// trips open before headers were stored are sent with the one of their last telemetry
+	if !trip.EventHeader.Valid {
+		trip.EventHeader = header
+	}
+	trip.UpdatedAt = time.Now()
+
+	closed, err := dbmodels.Trips(
+		dbmodels.TripWhere.ID.EQ(trip.ID),
+		dbmodels.TripWhere.EndTime.IsNull(),
+	).UpdateAll(ctx, d.pdb.DBS().Writer, dbmodels.M{
+		dbmodels.TripColumns.EndTime:      trip.EndTime,
+		dbmodels.TripColumns.LastMovingAt: trip.LastMovingAt,
+		dbmodels.TripColumns.EndLatitude:  trip.EndLatitude,
+		dbmodels.TripColumns.EndLongitude: trip.EndLongitude,
+		dbmodels.TripColumns.EndOdometer:  trip.EndOdometer,
+		dbmodels.TripColumns.DistanceKM:   trip.DistanceKM,
+		dbmodels.TripColumns.EventHeader:  trip.EventHeader,
+		dbmodels.TripColumns.UpdatedAt:    trip.UpdatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+	state.trip = nil
+	if closed == 0 {
+		return nil, nil
+	}
+	tripsCntr.Inc()
+	d.logger.Debug().Str("vin", vin).Str("tripId", trip.ID).Msg("Trip ended")
+
+	return tripEvent(&trip)
+}
+
+func tripEvent(trip *dbmodels.Trip) (*sessionEvent, error) {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"tripId":         trip.ID,
+		"startLatitude":  trip.StartLatitude,
+		"startLongitude": trip.StartLongitude,
+		"endLatitude":    trip.EndLatitude,
+		"endLongitude":   trip.EndLongitude,
+		"distanceKm":     trip.DistanceKM,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionEvent{
+		vin:       trip.Vin,
+		sessionID: trip.ID,
+		header:    trip.EventHeader,
+		event: models.Event{
+			Name:       eventNameTrip,
+			Timestamp:  trip.StartTime,
+			DurationNs: trip.EndTime.Time.Sub(trip.StartTime).Nanoseconds(),
+			Metadata:   string(metadata),
+		},
+	}, nil
+}
+
+func (d *SessionDetector) detectCharging(ctx context.Context, vin string, state *sessionState, values map[string]signalValue, header null.JSON) (*sessionEvent, error) {
+	if soc, ok := values[signalStateOfCharge]; ok {
+		state.soc = null.Float64From(soc.Value)
+	}
+	if energy, ok := values[signalStateOfChargeEnergy]; ok {
+		state.energy = null.Float64From(energy.Value)
+	}
+
+	isCharging, ok := values[signalIsCharging]
+	if !ok {
+		return nil, nil
+	}
+
+	if isCharging.Value > 0 {
+		if state.charging == nil {
+			session := &dbmodels.ChargingSession{
+				ID:          ksuid.New().String(),
+				Vin:         vin,
+				StartTime:   isCharging.Timestamp,
+				StartSoc:    state.soc,
+				EventHeader: header,
+			}
+			if err := session.Insert(ctx, d.pdb.DBS().Writer, boil.Infer()); err != nil {
+				return nil, fmt.Errorf("failed to insert charging session: %w", err)
+			}
+			state.charging = session
+			state.startEnergy = state.energy
+			d.logger.Debug().Str("vin", vin).Str("sessionId", session.ID).Msg("Charging session started")
+		}
+
+		return nil, nil
+	}
+
+	if state.charging == nil {
+		return nil, nil
+	}
+
+	added := null.Float64{}
+	if value, ok := values[signalChargingAddedEnergy]; ok {
+		added = null.Float64From(value.Value)
+	}
+
+	return d.endCharging(ctx, vin, state, isCharging.Timestamp, added, header)
+}
+
+// endCharging closes the open charging session of the VIN and returns its event. Sessions already closed, eg. by the
+// idle sweep of another instance, return no event.
+func (d *SessionDetector) endCharging(ctx context.Context, vin string, state *sessionState, endTime time.Time, added null.Float64, header null.JSON) (*sessionEvent, error) {
+	session := *state.charging
+	session.EndTime = null.TimeFrom(endTime)
+	session.EndSoc = state.soc
+	if added.Valid {
+		session.EnergyKWH = added
+	} else if state.startEnergy.Valid && state.energy.Valid {
+		session.EnergyKWH = null.Float64From(state.energy.Float64 - state.startEnergy.Float64)
+	}
+	if !session.EventHeader.Valid {
+		session.EventHeader = header
+	}
+	session.UpdatedAt = time.Now()
+
+	closed, err := dbmodels.ChargingSessions(
+		dbmodels.ChargingSessionWhere.ID.EQ(session.ID),
+		dbmodels.ChargingSessionWhere.EndTime.IsNull(),
+	).UpdateAll(ctx, d.pdb.DBS().Writer, dbmodels.M{
+		dbmodels.ChargingSessionColumns.EndTime:     session.EndTime,
+		dbmodels.ChargingSessionColumns.EndSoc:      session.EndSoc,
+		dbmodels.ChargingSessionColumns.EnergyKWH:   session.EnergyKWH,
+		dbmodels.ChargingSessionColumns.EventHeader: session.EventHeader,
+		dbmodels.ChargingSessionColumns.UpdatedAt:   session.UpdatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update charging session: %w", err)
+	}
+	state.charging = nil
+	state.startEnergy = null.Float64{}
+	if closed == 0 {
+		return nil, nil
+	}
+	chargingSessionsCntr.Inc()
+	d.logger.Debug().Str("vin", vin).Str("sessionId", session.ID).Msg("Charging session ended")
+
+	return chargingEvent(&session)
+}
+
+func chargingEvent(session *dbmodels.ChargingSession) (*sessionEvent, error) {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"sessionId": session.ID,
+		"startSoc":  session.StartSoc,
+		"endSoc":    session.EndSoc,
+		"energyKwh": session.EnergyKWH,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionEvent{
+		vin:       session.Vin,
+		sessionID: session.ID,
+		header:    session.EventHeader,
+		event: models.Event{
+			Name:       eventNameChargingSession,
+			Timestamp:  session.StartTime,
+			DurationNs: session.EndTime.Time.Sub(session.StartTime).Nanoseconds(),
+			Metadata:   string(metadata),
+		},
+	}, nil
+}
+
+// closeIdle ends the open sessions of VINs which stopped reporting, no later signals would end them. Trips end where
+// the vehicle last moved, charging sessions when the VIN last reported.
+func (d *SessionDetector) closeIdle(ctx context.Context, now time.Time) ([]sessionEvent, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	cutoff := now.Add(-sessionStateTTL)
+	var events []sessionEvent
+
+	trips, err := dbmodels.Trips(
+		dbmodels.TripWhere.EndTime.IsNull(),
+		qm.Where("coalesce("+dbmodels.TripColumns.LastMovingAt+", "+dbmodels.TripColumns.StartTime+") < ?", cutoff),
+		qm.Limit(sessionEventsBatchSize),
+	).All(ctx, d.pdb.DBS().Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load idle trips: %w", err)
+	}
+	for _, trip := range trips {
+		event, err := d.closeIdleTrip(ctx, trip.Vin, trip.ID, now)
+		if err != nil {
+			return events, err
+		}
+		if event != nil {
+			events = append(events, *event)
+		}
+	}
+
+	sessions, err := dbmodels.ChargingSessions(
+		dbmodels.ChargingSessionWhere.EndTime.IsNull(),
+		dbmodels.ChargingSessionWhere.StartTime.LT(cutoff),
+		qm.Where("not exists (select 1 from oracle_example.telemetry_status s where s.vin = charging_sessions.vin and s.last_received_at >= ?)", cutoff),
+		qm.Limit(sessionEventsBatchSize),
+	).All(ctx, d.pdb.DBS().Reader)
+	if err != nil {
+		return events, fmt.Errorf("failed to load idle charging sessions: %w", err)
+	}
+	for _, session := range sessions {
+		event, err := d.closeIdleCharging(ctx, session.Vin, session.ID, now)
+		if err != nil {
+			return events, err
+		}
+		if event != nil {
+			events = append(events, *event)
+		}
+	}
+
+	return events, nil
+}
+
+func (d *SessionDetector) closeIdleTrip(ctx context.Context, vin, tripID string, now time.Time) (*sessionEvent, error) {
+	state := d.acquireState(vin)
+	defer d.releaseState(state)
+
+	state.m.Lock()
+	defer state.m.Unlock()
+
+	if err := d.loadState(ctx, vin, state); err != nil {
+		return nil, err
+	}
+	// moved since it was stored
+	if state.trip == nil || state.trip.ID != tripID || now.Sub(state.lastMovingAt) < sessionStateTTL {
+		return nil, nil
+	}
+
+	// the detector didn't see the VIN since a restart, the trip ends at the last stored position
+	if !state.latitude.Valid && !state.longitude.Valid && !state.odometer.Valid {
+		state.latitude = d.lastSignalValue(ctx, vin, signalLocationLatitude)
+		state.longitude = d.lastSignalValue(ctx, vin, signalLocationLongitude)
+		state.odometer = d.lastSignalValue(ctx, vin, signalOdometer)
+	}
+
+	return d.endTrip(ctx, vin, state, null.JSON{})
+}
+
+func (d *SessionDetector) closeIdleCharging(ctx context.Context, vin, sessionID string, now time.Time) (*sessionEvent, error) {
+	state := d.acquireState(vin)
+	defer d.releaseState(state)
+
+	state.m.Lock()
+	defer state.m.Unlock()
+
+	if err := d.loadState(ctx, vin, state); err != nil {
+		return nil, err
+	}
+	if state.charging == nil || state.charging.ID != sessionID {
+		return nil, nil
+	}
+
+	endTime := state.charging.StartTime
+	status, err := dbmodels.FindTelemetryStatus(ctx, d.pdb.DBS().Reader, vin)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to load telemetry status: %w", err)
+	}
+	if status != nil && status.LastReceivedAt.Valid {
+		// reported again meanwhile
+		if now.Sub(status.LastReceivedAt.Time) < sessionStateTTL {
+			return nil, nil
+		}
+		if status.LastReceivedAt.Time.After(endTime) {
+			endTime = status.LastReceivedAt.Time
+		}
+	}
+	if !state.soc.Valid {
+		state.soc = d.lastSignalValue(ctx, vin, signalStateOfCharge)
+	}
+
+	return d.endCharging(ctx, vin, state, endTime, null.Float64{}, null.JSON{})
+}
+
+// lastSignalValue the last stored value of the signal of the VIN, invalid when there is none
+func (d *SessionDetector) lastSignalValue(ctx context.Context, vin, name string) null.Float64 {
+	signal, err := dbmodels.FindTelemetrySignal(ctx, d.pdb.DBS().Reader, vin, name)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			d.logger.Warn().Err(err).Str("vin", vin).Msgf("Failed to load last %s signal", name)
+		}
+		return null.Float64{}
+	}
+
+	var value float64
+	if err := json.Unmarshal(signal.Value, &value); err != nil {
+		return null.Float64{}
+	}
+
+	return null.Float64From(value)
+}
+
+// pendingEvents returns events of sessions which ended before the time, DIS didn't accept them yet
+func (d *SessionDetector) pendingEvents(ctx context.Context, endedBefore time.Time) ([]sessionEvent, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	var events []sessionEvent
+
+	trips, err := dbmodels.Trips(
+		dbmodels.TripWhere.EndTime.IsNotNull(),
+		dbmodels.TripWhere.EventSentAt.IsNull(),
+		dbmodels.TripWhere.UpdatedAt.LT(endedBefore),
+		qm.OrderBy(dbmodels.TripColumns.UpdatedAt),
+		qm.Limit(sessionEventsBatchSize),
+	).All(ctx, d.pdb.DBS().Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending trip events: %w", err)
+	}
+	for _, trip := range trips {
+		event, err := tripEvent(trip)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+
+	sessions, err := dbmodels.ChargingSessions(
+		dbmodels.ChargingSessionWhere.EndTime.IsNotNull(),
+		dbmodels.ChargingSessionWhere.EventSentAt.IsNull(),
+		dbmodels.ChargingSessionWhere.UpdatedAt.LT(endedBefore),
+		qm.OrderBy(dbmodels.ChargingSessionColumns.UpdatedAt),
+		qm.Limit(sessionEventsBatchSize),
+	).All(ctx, d.pdb.DBS().Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending charging session events: %w", err)
+	}
+	for _, session := range sessions {
+		event, err := chargingEvent(session)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+
+	return events, nil
+}
+
+// markSent stores that the event was sent, so it isn't sent again
+func (d *SessionDetector) markSent(ctx context.Context, event sessionEvent) error {
+	now := time.Now()
+
+	var err error
+	switch event.event.Name {
+	case eventNameTrip:
+		_, err = dbmodels.Trips(dbmodels.TripWhere.ID.EQ(event.sessionID)).UpdateAll(ctx, d.pdb.DBS().Writer, dbmodels.M{
+			dbmodels.TripColumns.EventSentAt: now,
+			dbmodels.TripColumns.UpdatedAt:   now,
+		})
+	case eventNameChargingSession:
+		_, err = dbmodels.ChargingSessions(dbmodels.ChargingSessionWhere.ID.EQ(event.sessionID)).UpdateAll(ctx, d.pdb.DBS().Writer, dbmodels.M{
+			dbmodels.ChargingSessionColumns.EventSentAt: now,
+			dbmodels.ChargingSessionColumns.UpdatedAt:   now,
+		})
+	default:
+		return fmt.Errorf("unknown session event: %s", event.event.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark %s event as sent: %w", event.event.Name, err)
+	}
+
+	return nil
+}
+
+// Prometheus metrics
+var tripsCntr = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dimo_oracle_trips_total",
+	Help: "Total number of detected trips",
+})
+
+var chargingSessionsCntr = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dimo_oracle_charging_sessions_total",
+	Help: "Total number of detected charging sessions",
+})
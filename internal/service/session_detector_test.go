@@ -0,0 +1,192 @@
+package service
+
+import (
+	"github.com/DIMO-Network/cloudevent"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volatiletech/null/v8"
+	"testing"
+	"time"
+)
+
+var sessionTestHeader = cloudevent.CloudEventHeader{
+	ID:       "telemetry",
+	Source:   "0x0000000000000000000000000000000000000abc",
+	Producer: "did:nft:137:0x0000000000000000000000000000000000000def_1",
+	Subject:  "did:nft:137:0x0000000000000000000000000000000000000def_2",
+	Type:     cloudevent.TypeStatus,
+}
+
+// speedSignals the speed and odometer signals of the vehicle at the time
+func speedSignals(speed, odometer float64, ts time.Time) []interface{} {
+	return []interface{}{
+		map[string]interface{}{"name": signalSpeed, "value": speed, "timestamp": ts.Format(time.RFC3339)},
+		map[string]interface{}{"name": signalOdometer, "value": odometer, "timestamp": ts.Format(time.RFC3339)},
+	}
+}
+
+func (s *OracleTestSuite) TestSessionDetectorTrips() {
+	settings := config.Settings{EnableSessionDetection: true, TripStartSpeed: 5, TripEndIdleMinutes: 5}
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	type reading struct {
+		minute   int
+		speed    float64
+		odometer float64
+		// the detector is created again before the reading, like after a restart
+		restart bool
+	}
+
+	tests := []struct {
+		name     string
+		readings []reading
+		// start and end minute of the ended trips
+		ended [][2]int
+		open  bool
+	}{
+		{
+			name:     "trip opens when moving",
+			readings: []reading{{minute: 0, speed: 0}, {minute: 1, speed: 30, odometer: 100}, {minute: 3, speed: 50, odometer: 102}},
+			open:     true,
+		},
+		{
+			name: "trip closes after standing still",
+			readings: []reading{
+				{minute: 0, speed: 30, odometer: 100}, {minute: 4, speed: 40, odometer: 104},
+				{minute: 6, speed: 0, odometer: 105}, {minute: 9, speed: 0, odometer: 105},
+			},
+			ended: [][2]int{{0, 4}},
+		},
+		{
+			name: "gap without signals closes the trip and a new one opens",
+			readings: []reading{
+				{minute: 0, speed: 30, odometer: 100}, {minute: 2, speed: 30, odometer: 101},
+				{minute: 60, speed: 40, odometer: 150},
+			},
+			ended: [][2]int{{0, 2}},
+			open:  true,
+		},
+		{
+			name: "trip is picked up after restart and ends where it last moved",
+			readings: []reading{
+				{minute: 0, speed: 30, odometer: 100}, {minute: 3, speed: 30, odometer: 103},
+				{minute: 10, speed: 0, odometer: 104, restart: true},
+			},
+			ended: [][2]int{{0, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			defer s.TearDownTest()
+
+			var detector *SessionDetector
+			var events []sessionEvent
+			for _, r := range tt.readings {
+				if detector == nil || r.restart {
+					detector = newSessionDetector(&s.pdb, zerolog.Nop(), settings)
+				}
+
+				detected, err := detector.Detect(s.ctx, vin, sessionTestHeader, speedSignals(r.speed, r.odometer, at(r.minute)), at(r.minute))
+				s.Require().NoError(err)
+				events = append(events, detected...)
+			}
+
+			s.Require().Len(events, len(tt.ended))
+			for i, ended := range tt.ended {
+				s.Equal(eventNameTrip, events[i].event.Name)
+				s.Equal(at(ended[0]), events[i].event.Timestamp.UTC())
+				s.Equal(at(ended[1]).Sub(at(ended[0])).Nanoseconds(), events[i].event.DurationNs)
+			}
+
+			openTrips, err := dbmodels.Trips(dbmodels.TripWhere.Vin.EQ(vin), dbmodels.TripWhere.EndTime.IsNull()).Count(s.ctx, s.pdb.DBS().Reader)
+			s.Require().NoError(err)
+			s.Equal(tt.open, openTrips == 1)
+		})
+	}
+}
+
+func (s *OracleTestSuite) TestSessionDetectorEviction() {
+	detector := newSessionDetector(&s.pdb, zerolog.Nop(), config.Settings{EnableSessionDetection: true})
+
+	_, err := detector.Detect(s.ctx, vin, sessionTestHeader, speedSignals(0, 100, time.Now()), time.Now())
+	s.Require().NoError(err)
+	s.Len(detector.states, 1)
+
+	detector.evict(time.Now())
+	s.Len(detector.states, 1)
+
+	detector.evict(time.Now().Add(sessionStateTTL))
+	s.Empty(detector.states)
+}
+
+func (s *OracleTestSuite) TestSessionDetectorCloseIdle() {
+	detector := newSessionDetector(&s.pdb, zerolog.Nop(), config.Settings{EnableSessionDetection: true})
+	start := time.Now().Add(-2 * sessionStateTTL)
+
+	_, err := detector.Detect(s.ctx, vin, sessionTestHeader, speedSignals(30, 100, start), start)
+	s.Require().NoError(err)
+	_, err = detector.Detect(s.ctx, vin, sessionTestHeader, speedSignals(30, 102, start.Add(time.Minute)), start.Add(time.Minute))
+	s.Require().NoError(err)
+
+	// a restarted detector closes the trip the VIN left open
+	detector = newSessionDetector(&s.pdb, zerolog.Nop(), config.Settings{EnableSessionDetection: true})
+	events, err := detector.closeIdle(s.ctx, time.Now())
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(eventNameTrip, events[0].event.Name)
+	s.Equal(time.Minute.Nanoseconds(), events[0].event.DurationNs)
+	tripID := events[0].sessionID
+
+	events, err = detector.closeIdle(s.ctx, time.Now())
+	s.Require().NoError(err)
+	s.Empty(events)
+
+	// stays pending until it's sent
+	pending, err := detector.pendingEvents(s.ctx, time.Now().Add(sessionEventRetryDelay))
+	s.Require().NoError(err)
+	s.Require().Len(pending, 1)
+	s.Equal(tripID, pending[0].sessionID)
+
+	s.Require().NoError(detector.markSent(s.ctx, pending[0]))
+	pending, err = detector.pendingEvents(s.ctx, time.Now().Add(sessionEventRetryDelay))
+	s.Require().NoError(err)
+	s.Empty(pending)
+}
+
+func TestNewEventCloudEvent(t *testing.T) {
+	header, err := sessionEventHeader(sessionTestHeader)
+	require.NoError(t, err)
+
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	trip := &dbmodels.Trip{
+		ID:          ksuid.New().String(),
+		Vin:         vin,
+		StartTime:   start,
+		EndTime:     null.TimeFrom(start.Add(time.Hour)),
+		EventHeader: header,
+	}
+	event, err := tripEvent(trip)
+	require.NoError(t, err)
+
+	ce, err := newEventCloudEvent(*event)
+	require.NoError(t, err)
+	// sent again with the same ID
+	assert.Equal(t, trip.ID, ce.ID)
+	assert.Equal(t, cloudEventTypeEvent, ce.Type)
+	assert.Equal(t, sessionTestHeader.Source, ce.Source)
+	assert.Equal(t, sessionTestHeader.Subject, ce.Subject)
+	assert.Equal(t, start.Add(time.Hour), ce.Time.UTC())
+
+	// sessions without a header can't be sent
+	trip.EventHeader = null.JSON{}
+	event, err = tripEvent(trip)
+	require.NoError(t, err)
+	_, err = newEventCloudEvent(*event)
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package service
+
+import "time"
+
+const (
+	signalSpeed               = "speed"
+	signalLocationLatitude    = "currentLocationLatitude"
+	signalLocationLongitude   = "currentLocationLongitude"
+	signalOdometer            = "powertrainTransmissionTravelledDistance"
+	signalIsCharging          = "powertrainTractionBatteryChargingIsCharging"
+	signalStateOfCharge       = "powertrainTractionBatteryStateOfChargeCurrent"
+	signalStateOfChargeEnergy = "powertrainTractionBatteryStateOfChargeCurrentEnergy"
+	signalChargingAddedEnergy = "powertrainTractionBatteryChargingAddedEnergy"
+)
+
+type signalValue struct {
+	Value     float64
+	Timestamp time.Time
+}
+
+// signalValues returns the latest numeric value of every signal in the message, keyed by signal name.
+// Signals without a valid timestamp are stamped with fallback.
+func signalValues(signals []interface{}, fallback time.Time) map[string]signalValue {
+	values := make(map[string]signalValue, len(signals))
+
+	for _, s := range signals {
+		signal, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := signal["name"].(string)
+		if !ok {
+			continue
+		}
+		value, ok := signal["value"].(float64)
+		if !ok {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, stringValue(signal["timestamp"]))
+		if err != nil {
+			ts = fallback
+		}
+
+		if current, found := values[name]; found && current.Timestamp.After(ts) {
+			continue
+		}
+		values[name] = signalValue{Value: value, Timestamp: ts}
+	}
+
+	return values
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/patrickmn/go-cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sync"
+	"time"
+)
+
+type telemetryVerdict int
+
+const (
+	telemetryAccepted telemetryVerdict = iota
+	telemetryDuplicate
+	telemetryLate
+)
+
+// lateEventExtension is the CloudEvent extension set on late events that are forwarded instead of dropped
+const lateEventExtension = "late"
+
+// telemetryFilter protects DIS from duplicated and out-of-order telemetry.
+// Duplicates are detected per VIN by CloudEvent ID + signal timestamp within a dedup window,
+// late events are detected against the newest signal timestamp seen for the VIN (watermark).
+// A nil filter accepts every event.
+type telemetryFilter struct {
+	seen       *cache.Cache
+	watermarks *cache.Cache
+	maxSkew    time.Duration
+	dropLate   bool
+	m          sync.Mutex
+}
+
+func newTelemetryFilter(settings config.Settings) *telemetryFilter {
+	f := &telemetryFilter{
+		// watermarks are kept for a day after the last event, vehicles silent for longer start fresh
+		watermarks: cache.New(24*time.Hour, time.Hour),
+		maxSkew:    time.Duration(settings.TelemetryMaxSkewSeconds) * time.Second,
+		dropLate:   settings.TelemetryDropLateEvents,
+	}
+
+	if settings.TelemetryDedupWindowSeconds > 0 {
+		window := time.Duration(settings.TelemetryDedupWindowSeconds) * time.Second
+		f.seen = cache.New(window, window)
+	}
+
+	return f
+}
+
+// Check classifies the event, without recording it. Events are only recorded with Commit once DIS accepted them,
+// so an event redelivered after a failed send isn't taken for a duplicate.
+func (f *telemetryFilter) Check(vin, eventID string, signalTs time.Time) telemetryVerdict {
+	if f == nil {
+		return telemetryAccepted
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.seen != nil {
+		if _, found := f.seen.Get(seenKey(vin, eventID, signalTs)); found {
+			duplicateEventsCntr.Inc()
+			return telemetryDuplicate
+		}
+	}
+
+	if watermark, found := f.watermarks.Get(vin); found {
+		if signalTs.Before(watermark.(time.Time).Add(-f.maxSkew)) {
+			if f.dropLate {
+				lateEventsCntr.WithLabelValues("dropped").Inc()
+			} else {
+				lateEventsCntr.WithLabelValues("flagged").Inc()
+			}
+			return telemetryLate
+		}
+	}
+
+	return telemetryAccepted
+}
+
+// Commit records the event sent to DIS, so it is reported as duplicate next time, and moves the watermark forward
+func (f *telemetryFilter) Commit(vin, eventID string, signalTs time.Time) {
+	if f == nil {
+		return
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.seen != nil {
+		f.seen.SetDefault(seenKey(vin, eventID, signalTs), struct{}{})
+	}
+
+	if watermark, found := f.watermarks.Get(vin); found && !signalTs.After(watermark.(time.Time)) {
+		return
+	}
+	f.watermarks.SetDefault(vin, signalTs)
+}
+
+func seenKey(vin, eventID string, signalTs time.Time) string {
+	return fmt.Sprintf("%s|%s|%d", vin, eventID, signalTs.UnixNano())
+}
+
+// DropLate tells whether late events should be dropped or forwarded with the late extension set
+func (f *telemetryFilter) DropLate() bool {
+	return f != nil && f.dropLate
+}
+
+// latestSignalTimestamp returns the newest signal timestamp in the message data, or fallback if there is none
+func latestSignalTimestamp(data map[string]interface{}, fallback time.Time) time.Time {
+	signals, ok := data["signals"].([]interface{})
+	if !ok {
+		return fallback
+	}
+
+	var latest time.Time
+	for _, s := range signals {
+		signal, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		raw, ok := signal["timestamp"].(string)
+		if !ok {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			continue
+		}
+		if ts.After(latest) {
+			latest = ts
+		}
+	}
+
+	if latest.IsZero() {
+		return fallback
+	}
+
+	return latest
+}
+
+// Prometheus metrics
+var duplicateEventsCntr = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dimo_oracle_duplicate_events_total",
+	Help: "Total number of duplicated telemetry events skipped",
+})
+
+var lateEventsCntr = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "dimo_oracle_late_events_total",
+	Help: "Total number of telemetry events older than the VIN watermark, by action taken",
+}, []string{"action"})
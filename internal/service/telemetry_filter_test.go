@@ -0,0 +1,100 @@
+package service
+
+import (
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type TelemetryFilterTestSuite struct {
+	suite.Suite
+	ts time.Time
+}
+
+func (s *TelemetryFilterTestSuite) SetupTest() {
+	s.ts = time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+}
+
+func TestTelemetryFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(TelemetryFilterTestSuite))
+}
+
+// checkAndCommit checks the event and commits it when accepted, as if DIS accepted it
+func checkAndCommit(f *telemetryFilter, vin, eventID string, signalTs time.Time) telemetryVerdict {
+	verdict := f.Check(vin, eventID, signalTs)
+	if verdict == telemetryAccepted {
+		f.Commit(vin, eventID, signalTs)
+	}
+	return verdict
+}
+
+func (s *TelemetryFilterTestSuite) TestDuplicate() {
+	f := newTelemetryFilter(config.Settings{TelemetryDedupWindowSeconds: 60})
+
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-1", s.ts))
+	s.Equal(telemetryDuplicate, checkAndCommit(f, vin, "event-1", s.ts))
+
+	// same ID with another signal timestamp is a new event
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-1", s.ts.Add(time.Second)))
+	// other VIN is tracked separately
+	s.Equal(telemetryAccepted, checkAndCommit(f, "1GGCM82633A654321", "event-1", s.ts))
+}
+
+func (s *TelemetryFilterTestSuite) TestNotCommitted() {
+	f := newTelemetryFilter(config.Settings{TelemetryDedupWindowSeconds: 60, TelemetryMaxSkewSeconds: 60, TelemetryDropLateEvents: true})
+
+	// sending to DIS failed, the redelivered event is accepted again
+	s.Equal(telemetryAccepted, f.Check(vin, "event-1", s.ts))
+	s.Equal(telemetryAccepted, f.Check(vin, "event-1", s.ts))
+
+	// the watermark doesn't move either
+	s.Equal(telemetryAccepted, f.Check(vin, "event-2", s.ts.Add(time.Hour)))
+	s.Equal(telemetryAccepted, f.Check(vin, "event-1", s.ts))
+
+	f.Commit(vin, "event-1", s.ts)
+	s.Equal(telemetryDuplicate, f.Check(vin, "event-1", s.ts))
+}
+
+func (s *TelemetryFilterTestSuite) TestDedupDisabled() {
+	f := newTelemetryFilter(config.Settings{TelemetryMaxSkewSeconds: 60})
+
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-1", s.ts))
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-1", s.ts))
+}
+
+func (s *TelemetryFilterTestSuite) TestLate() {
+	f := newTelemetryFilter(config.Settings{TelemetryMaxSkewSeconds: 60, TelemetryDropLateEvents: true})
+
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-1", s.ts))
+	// within skew
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-2", s.ts.Add(-30*time.Second)))
+	// older than skew
+	s.Equal(telemetryLate, checkAndCommit(f, vin, "event-3", s.ts.Add(-2*time.Minute)))
+	s.True(f.DropLate())
+
+	// watermark moves forward only
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-4", s.ts.Add(5*time.Minute)))
+	s.Equal(telemetryLate, checkAndCommit(f, vin, "event-5", s.ts))
+}
+
+func (s *TelemetryFilterTestSuite) TestNilFilter() {
+	var f *telemetryFilter
+
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-1", s.ts))
+	s.Equal(telemetryAccepted, checkAndCommit(f, vin, "event-1", s.ts))
+	s.False(f.DropLate())
+}
+
+func (s *TelemetryFilterTestSuite) TestLatestSignalTimestamp() {
+	data := map[string]interface{}{
+		"signals": []interface{}{
+			map[string]interface{}{"name": "speed", "timestamp": "2025-03-04T12:01:00Z", "value": 55},
+			map[string]interface{}{"name": "powertrainTransmissionTravelledDistance", "timestamp": "2025-03-04T12:02:00Z", "value": 100},
+			map[string]interface{}{"name": "speed", "value": 55},
+		},
+	}
+
+	s.Equal(time.Date(2025, 3, 4, 12, 2, 0, 0, time.UTC), latestSignalTimestamp(data, s.ts))
+	s.Equal(s.ts, latestSignalTimestamp(map[string]interface{}{}, s.ts))
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/patrickmn/go-cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"math"
+	"sync"
+	"time"
+)
+
+// limitedReportInterval how often the limited telemetry of a VIN is reported
+const limitedReportInterval = time.Minute
+
+// limitedVin counts the telemetry of a VIN removed since it was last reported
+type limitedVin struct {
+	rateLimited int
+	downsampled int
+	reportedAt  time.Time
+}
+
+type locationPoint struct {
+	ts    time.Time
+	speed float64
+}
+
+// telemetryLimiter keeps a single noisy vehicle from starving the rest of the fleet on the shared DIS limiter.
+// Every VIN gets its own token bucket and location signals are downsampled to one point per interval,
+// unless the speed changed by more than the configured delta since the last forwarded point.
+// A nil limiter lets everything through.
+type telemetryLimiter struct {
+	buckets          *cache.Cache
+	locations        *cache.Cache
+	limited          *cache.Cache
+	limit            rate.Limit
+	burst            int
+	locationInterval time.Duration
+	speedDelta       float64
+	m                sync.Mutex
+}
+
+func newTelemetryLimiter(settings config.Settings) *telemetryLimiter {
+	burst := settings.TelemetryVINRateBurst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &telemetryLimiter{
+		buckets:          cache.New(time.Hour, time.Hour),
+		locations:        cache.New(time.Hour, time.Hour),
+		limited:          cache.New(time.Hour, time.Hour),
+		limit:            rate.Limit(settings.TelemetryVINRateLimit),
+		burst:            burst,
+		locationInterval: time.Duration(settings.TelemetryLocationIntervalSeconds) * time.Second,
+		speedDelta:       settings.TelemetryLocationSpeedDelta,
+	}
+}
+
+// Allow takes a token from the VIN bucket, events without a token should be dropped
+func (l *telemetryLimiter) Allow(vin string) bool {
+	if l == nil || l.limit <= 0 {
+		return true
+	}
+
+	l.m.Lock()
+	bucket, found := l.buckets.Get(vin)
+	if !found {
+		bucket = rate.NewLimiter(l.limit, l.burst)
+	}
+	// refresh expiration, so active vehicles keep their bucket
+	l.buckets.SetDefault(vin, bucket)
+	l.m.Unlock()
+
+	if !bucket.(*rate.Limiter).Allow() {
+		rateLimitedEventsCntr.Inc()
+		l.count(vin, func(c *limitedVin) { c.rateLimited++ })
+		return false
+	}
+
+	return true
+}
+
+// Downsample removes location signals arriving sooner than the location interval after the last forwarded point
+// and returns the remaining signals, which are merged into the forwarded event.
+func (l *telemetryLimiter) Downsample(vin string, signals []interface{}) []interface{} {
+	if l == nil || l.locationInterval <= 0 {
+		return signals
+	}
+
+	var point *locationPoint
+	speed, hasSpeed := 0.0, false
+	for _, s := range signals {
+		signal, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch signal["name"] {
+		case signalSpeed:
+			if v, ok := signal["value"].(float64); ok {
+				speed, hasSpeed = v, true
+			}
+		case signalLocationLatitude, signalLocationLongitude:
+			ts, err := time.Parse(time.RFC3339, stringValue(signal["timestamp"]))
+			if err != nil {
+				ts = time.Now()
+			}
+			if point == nil || ts.After(point.ts) {
+				point = &locationPoint{ts: ts}
+			}
+		}
+	}
+
+	// nothing to downsample
+	if point == nil {
+		return signals
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if last, found := l.locations.Get(vin); found {
+		last := last.(locationPoint)
+		if !hasSpeed {
+			speed = last.speed
+		}
+		if point.ts.Sub(last.ts) < l.locationInterval && math.Abs(speed-last.speed) <= l.speedDelta {
+			result := make([]interface{}, 0, len(signals))
+			for _, s := range signals {
+				if signal, ok := s.(map[string]interface{}); ok && (signal["name"] == signalLocationLatitude || signal["name"] == signalLocationLongitude) {
+					downsampledSignalsCntr.Inc()
+					continue
+				}
+				result = append(result, s)
+			}
+			l.countLocked(vin, func(c *limitedVin) { c.downsampled += len(signals) - len(result) })
+			return result
+		}
+	}
+
+	point.speed = speed
+	l.locations.SetDefault(vin, *point)
+
+	return signals
+}
+
+func (l *telemetryLimiter) count(vin string, update func(c *limitedVin)) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.countLocked(vin, update)
+}
+
+// countLocked updates the counts of the VIN, l.m must be held
+func (l *telemetryLimiter) countLocked(vin string, update func(c *limitedVin)) {
+	counts, found := l.limited.Get(vin)
+	if !found {
+		counts = &limitedVin{}
+	}
+	update(counts.(*limitedVin))
+	l.limited.SetDefault(vin, counts)
+}
+
+// Report returns the telemetry of the VIN removed since it was last reported, at most once per report interval. The
+// first removal is reported right away.
+func (l *telemetryLimiter) Report(vin string, now time.Time) (limitedVin, bool) {
+	if l == nil {
+		return limitedVin{}, false
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	counts, found := l.limited.Get(vin)
+	if !found {
+		return limitedVin{}, false
+	}
+	c := counts.(*limitedVin)
+	if now.Sub(c.reportedAt) < limitedReportInterval || (c.rateLimited == 0 && c.downsampled == 0) {
+		return limitedVin{}, false
+	}
+
+	report := *c
+	*c = limitedVin{reportedAt: now}
+
+	return report, true
+}
+
+// Prometheus metrics, not labeled by VIN to keep their cardinality bounded, VINs are reported by Report instead
+var rateLimitedEventsCntr = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dimo_oracle_rate_limited_events_total",
+	Help: "Total number of telemetry events dropped by the per VIN rate limit",
+})
+
+var downsampledSignalsCntr = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dimo_oracle_downsampled_signals_total",
+	Help: "Total number of location signals removed by downsampling",
+})
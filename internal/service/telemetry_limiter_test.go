@@ -0,0 +1,100 @@
+package service
+
+import (
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type TelemetryLimiterTestSuite struct {
+	suite.Suite
+}
+
+func TestTelemetryLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(TelemetryLimiterTestSuite))
+}
+
+func locationSignals(ts string, speed float64) []interface{} {
+	return []interface{}{
+		map[string]interface{}{"name": signalSpeed, "timestamp": ts, "value": speed},
+		map[string]interface{}{"name": signalLocationLatitude, "timestamp": ts, "value": 52.1},
+		map[string]interface{}{"name": signalLocationLongitude, "timestamp": ts, "value": 13.4},
+	}
+}
+
+func (s *TelemetryLimiterTestSuite) TestAllow() {
+	l := newTelemetryLimiter(config.Settings{TelemetryVINRateLimit: 0.001, TelemetryVINRateBurst: 2})
+
+	s.True(l.Allow(vin))
+	s.True(l.Allow(vin))
+	s.False(l.Allow(vin))
+
+	// other VIN has its own bucket
+	s.True(l.Allow("1GGCM82633A654321"))
+}
+
+func (s *TelemetryLimiterTestSuite) TestAllowDisabled() {
+	l := newTelemetryLimiter(config.Settings{})
+
+	for i := 0; i < 100; i++ {
+		s.True(l.Allow(vin))
+	}
+}
+
+func (s *TelemetryLimiterTestSuite) TestDownsample() {
+	l := newTelemetryLimiter(config.Settings{TelemetryLocationIntervalSeconds: 10, TelemetryLocationSpeedDelta: 5})
+
+	s.Len(l.Downsample(vin, locationSignals("2025-03-04T12:00:00Z", 50)), 3)
+
+	// within interval, speed about the same - location removed, speed kept
+	result := l.Downsample(vin, locationSignals("2025-03-04T12:00:05Z", 52))
+	s.Len(result, 1)
+	s.Equal(signalSpeed, result[0].(map[string]interface{})["name"])
+
+	// within interval, but speed changed
+	s.Len(l.Downsample(vin, locationSignals("2025-03-04T12:00:06Z", 80)), 3)
+
+	// interval elapsed since last forwarded point
+	s.Len(l.Downsample(vin, locationSignals("2025-03-04T12:00:16Z", 80)), 3)
+}
+
+func (s *TelemetryLimiterTestSuite) TestDownsampleDisabled() {
+	l := newTelemetryLimiter(config.Settings{})
+
+	s.Len(l.Downsample(vin, locationSignals("2025-03-04T12:00:00Z", 50)), 3)
+	s.Len(l.Downsample(vin, locationSignals("2025-03-04T12:00:01Z", 50)), 3)
+}
+
+func (s *TelemetryLimiterTestSuite) TestReport() {
+	l := newTelemetryLimiter(config.Settings{TelemetryVINRateLimit: 0.001, TelemetryVINRateBurst: 1, TelemetryLocationIntervalSeconds: 10})
+	now := time.Now()
+
+	// nothing removed yet
+	_, ok := l.Report(vin, now)
+	s.False(ok)
+
+	s.True(l.Allow(vin))
+	s.False(l.Allow(vin))
+	l.Downsample(vin, locationSignals("2025-03-04T12:00:00Z", 50))
+	l.Downsample(vin, locationSignals("2025-03-04T12:00:05Z", 50))
+
+	// the first removal is reported right away
+	limited, ok := l.Report(vin, now)
+	s.Require().True(ok)
+	s.Equal(1, limited.rateLimited)
+	s.Equal(2, limited.downsampled)
+
+	s.False(l.Allow(vin))
+	_, ok = l.Report(vin, now.Add(time.Second))
+	s.False(ok)
+
+	// counted since the last report
+	limited, ok = l.Report(vin, now.Add(limitedReportInterval))
+	s.Require().True(ok)
+	s.Equal(1, limited.rateLimited)
+	s.Zero(limited.downsampled)
+
+	_, ok = l.Report(vin, now.Add(2*limitedReportInterval))
+	s.False(ok)
+}
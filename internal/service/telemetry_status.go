@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DIMO-Network/shared/pkg/db"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// vinLength is the length of a VIN, the key of the telemetry rows
+	vinLength               = 17
+	maxTelemetryErrorLength = 512
+	// maxSignalNameLength is the length of telemetry_signals.name, longer names are not signals we know
+	maxSignalNameLength = 100
+)
+
+// upsertTelemetrySignalQuery keeps the newest value of the signal, a flush never moves it back to an older one
+const upsertTelemetrySignalQuery = `INSERT INTO oracle_example.telemetry_signals (vin, name, value, timestamp, updated_at)
+	VALUES ($1, $2, $3, $4, now())
+	ON CONFLICT (vin, name) DO UPDATE
+	SET value = EXCLUDED.value, timestamp = EXCLUDED.timestamp, updated_at = EXCLUDED.updated_at
+	WHERE telemetry_signals.timestamp < EXCLUDED.timestamp`
+
+// TelemetryStatusTracker records when telemetry was last received, forwarded or failed for a VIN, and the last
+// value of every forwarded signal.
+// Updates are kept in memory and written to the database in batches, not on every message.
+// A nil tracker records nothing.
+type TelemetryStatusTracker struct {
+	pdb           *db.Store
+	logger        zerolog.Logger
+	flushInterval time.Duration
+	staleAfter    time.Duration
+	pending       map[string]*dbmodels.TelemetryStatus
+	signals       map[string]map[string]*dbmodels.TelemetrySignal
+	m             sync.Mutex
+}
+
+func NewTelemetryStatusTracker(pdb *db.Store, logger zerolog.Logger, settings config.Settings) *TelemetryStatusTracker {
+	flushInterval := time.Duration(settings.TelemetryStatusFlushSeconds) * time.Second
+	if flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+
+	staleAfter := time.Duration(settings.TelemetryStaleAfterMinutes) * time.Minute
+	if staleAfter <= 0 {
+		staleAfter = time.Hour
+	}
+
+	return &TelemetryStatusTracker{
+		pdb:           pdb,
+		logger:        logger,
+		flushInterval: flushInterval,
+		staleAfter:    staleAfter,
+		pending:       make(map[string]*dbmodels.TelemetryStatus),
+		signals:       make(map[string]map[string]*dbmodels.TelemetrySignal),
+	}
+}
+
+// Received records that a message for the VIN was received
+func (t *TelemetryStatusTracker) Received(vin string) {
+	t.record(vin, func(s *dbmodels.TelemetryStatus) {
+		s.LastReceivedAt = null.TimeFrom(time.Now())
+	})
+}
+
+// Forwarded records that a message for the VIN was accepted by DIS
+func (t *TelemetryStatusTracker) Forwarded(vin string) {
+	t.record(vin, func(s *dbmodels.TelemetryStatus) {
+		s.LastForwardedAt = null.TimeFrom(time.Now())
+	})
+}
+
+// Failed records the last error while processing a message for the VIN
+func (t *TelemetryStatusTracker) Failed(vin string, err error) {
+	msg := truncateUTF8(err.Error(), maxTelemetryErrorLength)
+
+	t.record(vin, func(s *dbmodels.TelemetryStatus) {
+		s.LastError = null.StringFrom(msg)
+		s.LastErrorAt = null.TimeFrom(time.Now())
+	})
+}
+
+// Signals records the last value of the forwarded signals of the VIN, signals without a name, value or valid
+// timestamp are ignored, as are names too long to be stored
+func (t *TelemetryStatusTracker) Signals(vin string, signals []interface{}) {
+	if t == nil || len(vin) != vinLength {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	for _, s := range signals {
+		signal, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := signal["name"].(string)
+		if !ok || name == "" || len(name) > maxSignalNameLength {
+			continue
+		}
+		raw, ok := signal["timestamp"].(string)
+		if !ok {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			continue
+		}
+		if signal["value"] == nil {
+			continue
+		}
+		value, err := json.Marshal(signal["value"])
+		if err != nil {
+			continue
+		}
+
+		vinSignals, ok := t.signals[vin]
+		if !ok {
+			vinSignals = make(map[string]*dbmodels.TelemetrySignal)
+			t.signals[vin] = vinSignals
+		}
+		if previous, ok := vinSignals[name]; ok && previous.Timestamp.After(ts) {
+			continue
+		}
+		vinSignals[name] = &dbmodels.TelemetrySignal{
+			Vin:       vin,
+			Name:      name,
+			Value:     value,
+			Timestamp: ts,
+		}
+	}
+}
+
+// record applies the update to the pending status of the VIN, VINs that can't be stored are ignored
+func (t *TelemetryStatusTracker) record(vin string, update func(s *dbmodels.TelemetryStatus)) {
+	if t == nil || len(vin) != vinLength {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	status, ok := t.pending[vin]
+	if !ok {
+		status = &dbmodels.TelemetryStatus{Vin: vin}
+		t.pending[vin] = status
+	}
+	update(status)
+}
+
+// Run flushes pending updates every flush interval until the context is done, then flushes for the last time
+func (t *TelemetryStatusTracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// parent context is done, but we still want to save what we have
+			return t.Flush(context.Background())
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				t.logger.Error().Err(err).Msg("Failed to flush telemetry status")
+			}
+			t.updateStaleVehicles(ctx)
+		}
+	}
+}
+
+// Flush writes all pending updates in a single transaction, updates of a failed flush are kept for the next one.
+// Updates of a VIN the database rejects are dropped, retrying them would fail every following flush.
+func (t *TelemetryStatusTracker) Flush(ctx context.Context) error {
+	t.m.Lock()
+	pending := t.pending
+	t.pending = make(map[string]*dbmodels.TelemetryStatus)
+	signals := t.signals
+	t.signals = make(map[string]map[string]*dbmodels.TelemetrySignal)
+	t.m.Unlock()
+
+	if len(pending) == 0 && len(signals) == 0 {
+		return nil
+	}
+
+	if err := t.write(ctx, pending, signals); err != nil {
+		t.restore(pending, signals)
+		return err
+	}
+
+	t.logger.Debug().Msgf("Flushed telemetry status for %d vehicles and signals for %d vehicles", len(pending), len(signals))
+
+	return nil
+}
+
+// restore merges the updates of a failed flush back into the pending ones, updates recorded since are newer and win
+func (t *TelemetryStatusTracker) restore(pending map[string]*dbmodels.TelemetryStatus, signals map[string]map[string]*dbmodels.TelemetrySignal) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	for vin, status := range pending {
+		current, ok := t.pending[vin]
+		if !ok {
+			t.pending[vin] = status
+			continue
+		}
+		if !current.LastReceivedAt.Valid {
+			current.LastReceivedAt = status.LastReceivedAt
+		}
+		if !current.LastForwardedAt.Valid {
+			current.LastForwardedAt = status.LastForwardedAt
+		}
+		if !current.LastErrorAt.Valid {
+			current.LastError, current.LastErrorAt = status.LastError, status.LastErrorAt
+		}
+	}
+
+	for vin, vinSignals := range signals {
+		current, ok := t.signals[vin]
+		if !ok {
+			t.signals[vin] = vinSignals
+			continue
+		}
+		for name, signal := range vinSignals {
+			if newer, ok := current[name]; !ok || signal.Timestamp.After(newer.Timestamp) {
+				current[name] = signal
+			}
+		}
+	}
+}
+
+func (t *TelemetryStatusTracker) write(ctx context.Context, pending map[string]*dbmodels.TelemetryStatus, signals map[string]map[string]*dbmodels.TelemetrySignal) error {
+	tx, err := t.pdb.DBS().Writer.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				t.logger.Error().Err(rbErr).Msg("Failed to rollback transaction")
+			}
+		}
+	}()
+
+	vins := make(map[string]struct{}, len(pending)+len(signals))
+	for vin := range pending {
+		vins[vin] = struct{}{}
+	}
+	for vin := range signals {
+		vins[vin] = struct{}{}
+	}
+
+	for vin := range vins {
+		// a savepoint per VIN, so a rejected row doesn't abort the rows of the other VINs
+		if _, err = tx.ExecContext(ctx, "SAVEPOINT telemetry_vin"); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		err = t.writeVin(ctx, tx, pending[vin], signals[vin])
+		if err != nil {
+			if !isPermanentWriteError(err) {
+				return err
+			}
+			t.logger.Warn().Err(err).Msgf("Dropped telemetry status for VIN: %s, rejected by the database", vin)
+			droppedTelemetryStatusCntr.Inc()
+			if _, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT telemetry_vin"); err != nil {
+				return fmt.Errorf("failed to rollback to savepoint: %w", err)
+			}
+		}
+
+		if _, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT telemetry_vin"); err != nil {
+			return fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (t *TelemetryStatusTracker) writeVin(ctx context.Context, tx *sql.Tx, status *dbmodels.TelemetryStatus, signals map[string]*dbmodels.TelemetrySignal) error {
+	if status != nil {
+		// only overwrite the columns we have new values for
+		columns := []string{dbmodels.TelemetryStatusColumns.UpdatedAt}
+		if status.LastReceivedAt.Valid {
+			columns = append(columns, dbmodels.TelemetryStatusColumns.LastReceivedAt)
+		}
+		if status.LastForwardedAt.Valid {
+			columns = append(columns, dbmodels.TelemetryStatusColumns.LastForwardedAt)
+		}
+		if status.LastErrorAt.Valid {
+			columns = append(columns, dbmodels.TelemetryStatusColumns.LastError, dbmodels.TelemetryStatusColumns.LastErrorAt)
+		}
+
+		err := status.Upsert(ctx, tx, true, []string{dbmodels.TelemetryStatusColumns.Vin}, boil.Whitelist(columns...), boil.Infer())
+		if err != nil {
+			return fmt.Errorf("failed to upsert telemetry status for %s: %w", status.Vin, err)
+		}
+	}
+
+	for _, signal := range signals {
+		_, err := queries.Raw(upsertTelemetrySignalQuery, signal.Vin, signal.Name, signal.Value, signal.Timestamp).ExecContext(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("failed to upsert telemetry signal %s for %s: %w", signal.Name, signal.Vin, err)
+		}
+	}
+
+	return nil
+}
+
+// isPermanentWriteError reports whether the database rejected the values themselves, eg. a value too long for its
+// column, writing them again fails the same way
+func isPermanentWriteError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	switch pqErr.Code.Class() {
+	case "22", "23": // data exception, integrity constraint violation
+		return true
+	default:
+		return false
+	}
+}
+
+// truncateUTF8 cuts the string to at most max bytes, without splitting a character. Invalid UTF-8 is removed, the
+// database doesn't store it.
+func truncateUTF8(s string, max int) string {
+	s = strings.ToValidUTF8(s, "")
+	if len(s) <= max {
+		return s
+	}
+
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+
+	return s[:max]
+}
+
+func (t *TelemetryStatusTracker) updateStaleVehicles(ctx context.Context) {
+	count, err := dbmodels.TelemetryStatuses(
+		dbmodels.TelemetryStatusWhere.LastReceivedAt.LT(null.TimeFrom(time.Now().Add(-t.staleAfter))),
+	).Count(ctx, t.pdb.DBS().Reader)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("Failed to count stale vehicles")
+		return
+	}
+
+	staleVehiclesGauge.Set(float64(count))
+}
+
+// Prometheus metrics
+var staleVehiclesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "dimo_oracle_stale_vehicles",
+	Help: "Number of vehicles that have not reported telemetry within the stale threshold",
+})
+
+var droppedTelemetryStatusCntr = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dimo_oracle_dropped_telemetry_status_total",
+	Help: "Total number of telemetry status updates dropped because the database rejected them",
+})
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/friendsofgo/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+type TelemetryStatusTestSuite struct {
+	suite.Suite
+}
+
+func TestTelemetryStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(TelemetryStatusTestSuite))
+}
+
+func (s *TelemetryStatusTestSuite) TestSignals() {
+	tracker := NewTelemetryStatusTracker(nil, zerolog.Nop(), config.Settings{})
+
+	tracker.Signals(vin, locationSignals("2025-01-01T10:00:00Z", 50))
+	// older values of the same signal are ignored
+	tracker.Signals(vin, []interface{}{
+		map[string]interface{}{"name": signalSpeed, "timestamp": "2025-01-01T09:00:00Z", "value": 20.0},
+		map[string]interface{}{"name": signalLocationLatitude, "timestamp": "2025-01-01T11:00:00Z", "value": 52.2},
+	})
+	// invalid signals are ignored
+	tracker.Signals(vin, []interface{}{
+		"not a signal",
+		map[string]interface{}{"timestamp": "2025-01-01T11:00:00Z", "value": 1.0},
+		map[string]interface{}{"name": "powertrainRange", "timestamp": "yesterday", "value": 1.0},
+		map[string]interface{}{"name": "powertrainTractionBatteryStateOfChargeCurrent", "timestamp": "2025-01-01T11:00:00Z"},
+		map[string]interface{}{"name": strings.Repeat("x", maxSignalNameLength+1), "timestamp": "2025-01-01T11:00:00Z", "value": 1.0},
+	})
+
+	signals := tracker.signals[vin]
+	s.Require().Len(signals, 3)
+	s.JSONEq(`50`, string(signals[signalSpeed].Value))
+	s.Equal("2025-01-01T10:00:00Z", signals[signalSpeed].Timestamp.UTC().Format(time.RFC3339))
+	s.JSONEq(`52.2`, string(signals[signalLocationLatitude].Value))
+	s.JSONEq(`13.4`, string(signals[signalLocationLongitude].Value))
+}
+
+func (s *TelemetryStatusTestSuite) TestRestore() {
+	tracker := NewTelemetryStatusTracker(nil, zerolog.Nop(), config.Settings{})
+
+	tracker.Received(vin)
+	tracker.Failed(vin, errors.New("DIS unavailable"))
+	tracker.Signals(vin, locationSignals("2025-01-01T10:00:00Z", 50))
+	failed := tracker.pending
+	failedSignals := tracker.signals
+	tracker.pending = make(map[string]*dbmodels.TelemetryStatus)
+	tracker.signals = make(map[string]map[string]*dbmodels.TelemetrySignal)
+
+	// recorded while the flush was failing
+	tracker.Forwarded(vin)
+	tracker.Received("1GGCM82633A654321")
+	tracker.Signals(vin, []interface{}{
+		map[string]interface{}{"name": signalSpeed, "timestamp": "2025-01-01T11:00:00Z", "value": 70.0},
+	})
+	received := tracker.pending["1GGCM82633A654321"].LastReceivedAt
+
+	tracker.restore(failed, failedSignals)
+
+	s.Require().Len(tracker.pending, 2)
+	status := tracker.pending[vin]
+	s.True(status.LastReceivedAt.Valid)
+	s.True(status.LastForwardedAt.Valid)
+	s.Equal("DIS unavailable", status.LastError.String)
+	s.Equal(received, tracker.pending["1GGCM82633A654321"].LastReceivedAt)
+
+	signals := tracker.signals[vin]
+	s.Require().Len(signals, 3)
+	s.JSONEq(`70`, string(signals[signalSpeed].Value))
+	s.JSONEq(`13.4`, string(signals[signalLocationLongitude].Value))
+}
+
+func (s *TelemetryStatusTestSuite) TestInvalidVin() {
+	tracker := NewTelemetryStatusTracker(nil, zerolog.Nop(), config.Settings{})
+
+	// too long for the rows, they would fail every flush
+	tracker.Received(vin + "0")
+	tracker.Signals(vin+"0", locationSignals("2025-01-01T10:00:00Z", 50))
+
+	s.Empty(tracker.pending)
+	s.Empty(tracker.signals)
+}
+
+func (s *TelemetryStatusTestSuite) TestFailedTruncatesOnCharacter() {
+	tracker := NewTelemetryStatusTracker(nil, zerolog.Nop(), config.Settings{})
+
+	tracker.Failed(vin, errors.New("x"+strings.Repeat("é", maxTelemetryErrorLength)))
+
+	msg := tracker.pending[vin].LastError.String
+	s.True(utf8.ValidString(msg))
+	s.Len(msg, maxTelemetryErrorLength-1)
+}
+
+func (s *TelemetryStatusTestSuite) TestSignalsNilTracker() {
+	var tracker *TelemetryStatusTracker
+
+	s.NotPanics(func() {
+		tracker.Signals(vin, locationSignals("2025-01-01T10:00:00Z", 50))
+	})
+}
+
+func (s *OracleTestSuite) TestTelemetryStatusFlushFailure() {
+	defer s.TearDownTest()
+
+	tracker := NewTelemetryStatusTracker(&s.pdb, zerolog.Nop(), config.Settings{})
+	tracker.Received(vin)
+	tracker.Signals(vin, locationSignals("2025-01-01T10:00:00Z", 50))
+
+	cancelled, cancel := context.WithCancel(s.ctx)
+	cancel()
+	s.Require().Error(tracker.Flush(cancelled))
+
+	// the next flush writes what the failed one didn't
+	s.Require().NoError(tracker.Flush(s.ctx))
+
+	status, err := dbmodels.FindTelemetryStatus(s.ctx, s.pdb.DBS().Reader, vin)
+	s.Require().NoError(err)
+	s.True(status.LastReceivedAt.Valid)
+
+	count, err := dbmodels.TelemetrySignals(dbmodels.TelemetrySignalWhere.Vin.EQ(vin)).Count(s.ctx, s.pdb.DBS().Reader)
+	s.Require().NoError(err)
+	s.Equal(int64(3), count)
+}
+
+func (s *OracleTestSuite) TestTelemetryStatusFlushDropsRejected() {
+	defer s.TearDownTest()
+
+	tracker := NewTelemetryStatusTracker(&s.pdb, zerolog.Nop(), config.Settings{})
+	tracker.Received(vin)
+	// postgres doesn't store NUL characters
+	tracker.Signals("1GGCM82633A654321", []interface{}{
+		map[string]interface{}{"name": "speed\x00", "timestamp": "2025-01-01T10:00:00Z", "value": 20.0},
+	})
+
+	s.Require().NoError(tracker.Flush(s.ctx))
+	s.Empty(tracker.pending)
+	s.Empty(tracker.signals)
+
+	// the other VINs are written
+	status, err := dbmodels.FindTelemetryStatus(s.ctx, s.pdb.DBS().Reader, vin)
+	s.Require().NoError(err)
+	s.True(status.LastReceivedAt.Valid)
+}
+
+func (s *OracleTestSuite) TestTelemetrySignalsFlushKeepsNewest() {
+	defer s.TearDownTest()
+
+	tracker := NewTelemetryStatusTracker(&s.pdb, zerolog.Nop(), config.Settings{})
+
+	tracker.Signals(vin, locationSignals("2025-01-01T10:00:00Z", 50))
+	s.Require().NoError(tracker.Flush(s.ctx))
+
+	// an older speed, flushed after the newer one, eg. by another replica
+	tracker.Signals(vin, []interface{}{
+		map[string]interface{}{"name": signalSpeed, "timestamp": "2025-01-01T09:00:00Z", "value": 20.0},
+		map[string]interface{}{"name": signalLocationLatitude, "timestamp": "2025-01-01T11:00:00Z", "value": 52.2},
+	})
+	s.Require().NoError(tracker.Flush(s.ctx))
+
+	signals, err := dbmodels.TelemetrySignals(dbmodels.TelemetrySignalWhere.Vin.EQ(vin)).All(s.ctx, s.pdb.DBS().Reader)
+	s.Require().NoError(err)
+
+	values := make(map[string]string, len(signals))
+	for _, signal := range signals {
+		values[signal.Name] = string(signal.Value)
+	}
+	s.JSONEq(`50`, values[signalSpeed])
+	s.JSONEq(`52.2`, values[signalLocationLatitude])
+}
@@ -3,15 +3,22 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/DIMO-Network/shared/pkg/db"
 	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
 	"github.com/DIMO-Network/volteras-oracle/internal/models"
 	"github.com/friendsofgo/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
 	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"strings"
+	"time"
 )
 
 type Vehicle struct {
@@ -191,7 +198,7 @@ func (ds *Vehicle) GetVehicleByExternalID(ctx context.Context, externalID string
 	vin, err := dbmodels.Vins(dbmodels.VinWhere.ExternalID.EQ(externalIDNull)).One(ctx, tx)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("vehicle not found")
+			return nil, ErrVehicleNotFound
 		}
 		ds.logger.Error().Err(err).Msgf("Failed to check if vehicle with external ID %s has been processed", externalID)
 		return nil, err
@@ -261,8 +268,9 @@ func (ds *Vehicle) InsertOrUpdateVin(ctx context.Context, vin *dbmodels.Vin) err
 	return nil
 }
 
-// UpdateEnrollmentStatus updates the enrollment status and external ID of a VIN record.
-func (ds *Vehicle) UpdateEnrollmentStatus(ctx context.Context, vin, status, externalID string, error *models.OperationError) error {
+// UpdateEnrollmentStatus updates the enrollment status, external ID and data services of a VIN record. Data
+// services are kept when none are given.
+func (ds *Vehicle) UpdateEnrollmentStatus(ctx context.Context, vin, status, externalID string, dataServices []string, error *models.OperationError) error {
 	tx, err := ds.pdb.DBS().Writer.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
 	if err != nil {
 		return err
@@ -282,6 +290,9 @@ func (ds *Vehicle) UpdateEnrollmentStatus(ctx context.Context, vin, status, exte
 
 	vinRecord.ConnectionStatus = null.StringFrom(status)
 	vinRecord.ExternalID = null.StringFrom(externalID)
+	if len(dataServices) > 0 {
+		vinRecord.DataServices = dataServices
+	}
 	if status == "succeeded" {
 		vinRecord.DisconnectionStatus = null.String{String: "", Valid: false}
 	}
@@ -300,6 +311,7 @@ func (ds *Vehicle) UpdateEnrollmentStatus(ctx context.Context, vin, status, exte
 		dbmodels.VinColumns.ConnectionStatus,
 		dbmodels.VinColumns.DisconnectionStatus,
 		dbmodels.VinColumns.ExternalID,
+		dbmodels.VinColumns.DataServices,
 		dbmodels.VinColumns.OperationErrorType,
 		dbmodels.VinColumns.OperationErrorCode,
 		dbmodels.VinColumns.OperationErrorDescription,
@@ -379,6 +391,36 @@ func (ds *Vehicle) GetVinsByTokenIDs(ctx context.Context, tokenIDsToCheck []int6
 	return vins, nil
 }
 
+// GetMintedVehiclesPage retrieves minted VINs ordered by vehicle token ID, starting after the given token ID.
+func (ds *Vehicle) GetMintedVehiclesPage(ctx context.Context, afterTokenID int64, limit int) (dbmodels.VinSlice, error) {
+	vins, err := dbmodels.Vins(
+		dbmodels.VinWhere.VehicleTokenID.GT(null.Int64From(afterTokenID)),
+		qm.OrderBy(dbmodels.VinColumns.VehicleTokenID),
+		qm.Limit(limit),
+	).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to get minted VINs")
+		return nil, fmt.Errorf("failed to get minted VINs: %w", err)
+	}
+
+	return vins, nil
+}
+
+// SetVinOwner stores the on-chain owner of a minted VIN, so listings by owner follow transfers. The developer client
+// the VIN was submitted through acted for the previous owner, its webhooks stop receiving the events of the VIN.
+func (ds *Vehicle) SetVinOwner(ctx context.Context, vin, owner string) error {
+	_, err := dbmodels.Vins(dbmodels.VinWhere.Vin.EQ(vin)).UpdateAll(ctx, ds.pdb.DBS().Writer, dbmodels.M{
+		dbmodels.VinColumns.OwnerAddress: null.StringFrom(owner),
+		dbmodels.VinColumns.ClientID:     null.String{},
+	})
+	if err != nil {
+		ds.logger.Error().Err(err).Msgf("Failed to update owner of VIN %s", vin)
+		return fmt.Errorf("failed to update VIN owner: %w", err)
+	}
+
+	return nil
+}
+
 // GetVehiclesFromDB retrieves all VINs from the database.
 func (ds *Vehicle) GetVehiclesFromDB(ctx context.Context) (dbmodels.VinSlice, error) {
 	tx, err := ds.pdb.DBS().Writer.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
@@ -407,3 +449,254 @@ func (ds *Vehicle) GetVehiclesFromDB(ctx context.Context) (dbmodels.VinSlice, er
 
 	return vins, nil
 }
+
+// GetTelemetryStatusByVins retrieves telemetry freshness records for the VINs, keyed by VIN.
+func (ds *Vehicle) GetTelemetryStatusByVins(ctx context.Context, vins []string) (map[string]*dbmodels.TelemetryStatus, error) {
+	statuses, err := dbmodels.TelemetryStatuses(dbmodels.TelemetryStatusWhere.Vin.IN(vins)).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to get telemetry status by VINs")
+		return nil, fmt.Errorf("failed to get telemetry status by VINs: %w", err)
+	}
+
+	result := make(map[string]*dbmodels.TelemetryStatus, len(statuses))
+	for _, status := range statuses {
+		result[status.Vin] = status
+	}
+
+	return result, nil
+}
+
+// GetTelemetrySignals retrieves the last value of every signal forwarded for the VIN.
+func (ds *Vehicle) GetTelemetrySignals(ctx context.Context, vin string) (dbmodels.TelemetrySignalSlice, error) {
+	signals, err := dbmodels.TelemetrySignals(
+		dbmodels.TelemetrySignalWhere.Vin.EQ(vin),
+		qm.OrderBy(dbmodels.TelemetrySignalColumns.Name),
+	).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to get telemetry signals")
+		return nil, fmt.Errorf("failed to get telemetry signals: %w", err)
+	}
+
+	return signals, nil
+}
+
+// GetOdometerAnomalies retrieves the latest odometer anomalies recorded for the VIN.
+func (ds *Vehicle) GetOdometerAnomalies(ctx context.Context, vin string, limit int) (dbmodels.OdometerAnomalySlice, error) {
+	anomalies, err := dbmodels.OdometerAnomalies(
+		dbmodels.OdometerAnomalyWhere.Vin.EQ(vin),
+		qm.OrderBy(dbmodels.OdometerAnomalyColumns.CreatedAt+" DESC"),
+		qm.Limit(limit),
+	).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to get odometer anomalies")
+		return nil, fmt.Errorf("failed to get odometer anomalies: %w", err)
+	}
+
+	return anomalies, nil
+}
+
+// VehicleFilter narrows down the VINs returned by SearchVehicles, empty fields are not filtered on.
+type VehicleFilter struct {
+	Vin              string
+	OwnerAddress     string
+	OnboardingStatus *int
+	ErrorCode        string
+	Limit            int
+	Offset           int
+}
+
+// SearchVehicles retrieves VINs matching the filter, ordered by VIN.
+func (ds *Vehicle) SearchVehicles(ctx context.Context, filter VehicleFilter) (dbmodels.VinSlice, error) {
+	mods := []qm.QueryMod{
+		qm.OrderBy(dbmodels.VinColumns.Vin),
+		qm.Limit(filter.Limit),
+		qm.Offset(filter.Offset),
+	}
+	if filter.Vin != "" {
+		mods = append(mods, dbmodels.VinWhere.Vin.ILIKE("%"+filter.Vin+"%"))
+	}
+	if filter.OwnerAddress != "" {
+		mods = append(mods, qm.Where("lower("+dbmodels.VinColumns.OwnerAddress+") = lower(?)", filter.OwnerAddress))
+	}
+	if filter.OnboardingStatus != nil {
+		mods = append(mods, dbmodels.VinWhere.OnboardingStatus.EQ(*filter.OnboardingStatus))
+	}
+	if filter.ErrorCode != "" {
+		mods = append(mods, dbmodels.VinWhere.OperationErrorCode.EQ(null.StringFrom(filter.ErrorCode)))
+	}
+
+	vins, err := dbmodels.Vins(mods...).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to search VINs")
+		return nil, fmt.Errorf("failed to search VINs: %w", err)
+	}
+
+	return vins, nil
+}
+
+// UpdateVin updates only the given columns of the VIN.
+func (ds *Vehicle) UpdateVin(ctx context.Context, vin *dbmodels.Vin, columns ...string) error {
+	_, err := vin.Update(ctx, ds.pdb.DBS().Writer, boil.Whitelist(columns...))
+	if err != nil {
+		ds.logger.Error().Err(err).Msgf("Failed to update VIN %s", vin.Vin)
+		return fmt.Errorf("failed to update VIN: %w", err)
+	}
+
+	return nil
+}
+
+// RiverJob is a summary of an onboarding job stored by River.
+type RiverJob struct {
+	ID          int64       `boil:"id" json:"id"`
+	Kind        string      `boil:"kind" json:"kind"`
+	State       string      `boil:"state" json:"state"`
+	Attempt     int         `boil:"attempt" json:"attempt"`
+	MaxAttempts int         `boil:"max_attempts" json:"maxAttempts"`
+	CreatedAt   time.Time   `boil:"created_at" json:"createdAt"`
+	FinalizedAt null.Time   `boil:"finalized_at" json:"finalizedAt"`
+	LastError   null.String `boil:"last_error" json:"lastError"`
+}
+
+// GetJobsByVin retrieves the latest River jobs for the VIN, newest first.
+func (ds *Vehicle) GetJobsByVin(ctx context.Context, vin string, kinds []string, limit int) ([]*RiverJob, error) {
+	args, err := json.Marshal(map[string]string{"vin": vin})
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*RiverJob
+	err = queries.Raw(`SELECT id, kind, state, attempt, max_attempts, created_at, finalized_at,
+		errors[array_length(errors, 1)]->>'error' AS last_error
+		FROM river_job
+		WHERE args @> $1 AND (cardinality($2::text[]) = 0 OR kind = ANY($2::text[]))
+		ORDER BY id DESC
+		LIMIT $3`, string(args), pq.StringArray(kinds), limit).Bind(ctx, ds.pdb.DBS().Reader, &jobs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ds.logger.Error().Err(err).Msg("Failed to get jobs by VIN")
+		return nil, fmt.Errorf("failed to get jobs by VIN: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// maxAuditActorLength length of the actor column of the audit log
+const maxAuditActorLength = 100
+
+// AuditEntry is an admin action recorded in the audit log.
+type AuditEntry struct {
+	Actor   string
+	Action  string
+	Vin     string
+	Details interface{}
+}
+
+// record the audit log row of the entry, actors are cut to the column length
+func (e AuditEntry) record() (*dbmodels.AdminAuditLog, error) {
+	actor := []rune(strings.TrimSpace(e.Actor))
+	if len(actor) > maxAuditActorLength {
+		actor = actor[:maxAuditActorLength]
+	}
+
+	record := &dbmodels.AdminAuditLog{
+		ID:     ksuid.New().String(),
+		Actor:  string(actor),
+		Action: e.Action,
+	}
+	if e.Vin != "" {
+		record.Vin = null.StringFrom(e.Vin)
+	}
+	if e.Details != nil {
+		if err := record.Details.Marshal(e.Details); err != nil {
+			return nil, fmt.Errorf("failed to marshal audit details: %w", err)
+		}
+	}
+
+	return record, nil
+}
+
+// InsertAuditLog records an admin action.
+func (ds *Vehicle) InsertAuditLog(ctx context.Context, entry AuditEntry) error {
+	record, err := entry.record()
+	if err != nil {
+		return err
+	}
+
+	if err := record.Insert(ctx, ds.pdb.DBS().Writer, boil.Infer()); err != nil {
+		ds.logger.Error().Err(err).Msgf("Failed to insert audit log for %s", entry.Action)
+		return fmt.Errorf("failed to insert audit log: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateVinAudited updates the columns of the VIN and records the admin action in the same transaction.
+func (ds *Vehicle) UpdateVinAudited(ctx context.Context, vin *dbmodels.Vin, entry AuditEntry, columns ...string) error {
+	record, err := entry.record()
+	if err != nil {
+		return err
+	}
+
+	tx, err := ds.pdb.DBS().Writer.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		ds.logger.Error().Err(err).Msgf("UpdateVinAudited: Failed to begin transaction for VIN %s", vin.Vin)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				ds.logger.Error().Err(rbErr).Msgf("UpdateVinAudited: Failed to rollback transaction for VIN %s", vin.Vin)
+			}
+		}
+	}()
+
+	if _, err = vin.Update(ctx, tx, boil.Whitelist(columns...)); err != nil {
+		ds.logger.Error().Err(err).Msgf("Failed to update VIN %s", vin.Vin)
+		return fmt.Errorf("failed to update VIN: %w", err)
+	}
+
+	if err = record.Insert(ctx, tx, boil.Infer()); err != nil {
+		ds.logger.Error().Err(err).Msgf("Failed to insert audit log for %s", entry.Action)
+		return fmt.Errorf("failed to insert audit log: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		ds.logger.Error().Err(err).Msgf("UpdateVinAudited: Failed to commit transaction for VIN %s", vin.Vin)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// InsertAuditLogTx records an admin action in the transaction of River, for actions on onboarding jobs.
+func InsertAuditLogTx(ctx context.Context, tx pgx.Tx, entry AuditEntry) error {
+	record, err := entry.record()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO oracle_example.admin_audit_log (id, actor, action, vin, details)
+VALUES ($1, $2, $3, $4, $5)`, record.ID, record.Actor, record.Action, record.Vin, record.Details); err != nil {
+		return fmt.Errorf("failed to insert audit log: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditLogs retrieves the latest admin actions, optionally for a single VIN.
+func (ds *Vehicle) GetAuditLogs(ctx context.Context, vin string, limit int) (dbmodels.AdminAuditLogSlice, error) {
+	mods := []qm.QueryMod{
+		qm.OrderBy(dbmodels.AdminAuditLogColumns.CreatedAt + " DESC"),
+		qm.Limit(limit),
+	}
+	if vin != "" {
+		mods = append(mods, dbmodels.AdminAuditLogWhere.Vin.EQ(null.StringFrom(vin)))
+	}
+
+	logs, err := dbmodels.AdminAuditLogs(mods...).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to get audit logs")
+		return nil, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+
+	return logs, nil
+}
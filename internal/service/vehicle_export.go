@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"strings"
+	"time"
+)
+
+// VehicleExportFilter narrows down the exported VINs, empty fields are not filtered on.
+type VehicleExportFilter struct {
+	// onboarding statuses from MinStatus up to but excluding MaxStatus
+	MinStatus *int
+	MaxStatus *int
+	// last onboarding status change from From up to but excluding To
+	From *time.Time
+	To   *time.Time
+}
+
+// VehicleExport is a VIN with its latest onboarding status change and telemetry freshness
+type VehicleExport struct {
+	Vin                       string
+	VehicleTokenID            null.Int64
+	SyntheticTokenID          null.Int64
+	WalletIndex               null.Int64
+	OwnerAddress              null.String
+	ExternalID                null.String
+	ConnectionStatus          null.String
+	DisconnectionStatus       null.String
+	OnboardingStatus          int
+	DeviceDefinitionID        null.String
+	OperationErrorCode        null.String
+	OperationErrorType        null.String
+	OperationErrorDescription null.String
+	StatusChangedAt           null.Time
+	LastReceivedAt            null.Time
+	LastForwardedAt           null.Time
+	LastError                 null.String
+}
+
+const vehicleExportQuery = `SELECT v.vin, v.vehicle_token_id, v.synthetic_token_id, v.wallet_index, v.owner_address,
+	v.external_id, v.connection_status, v.disconnection_status, v.onboarding_status, v.device_definition_id,
+	v.operation_error_code, v.operation_error_type, v.operation_error_description,
+	h.changed_at, t.last_received_at, t.last_forwarded_at, t.last_error
+FROM oracle_example.vins v
+LEFT JOIN LATERAL (
+	SELECT changed_at FROM oracle_example.vin_status_history
+	WHERE vin = v.vin
+	ORDER BY changed_at DESC
+	LIMIT 1
+) h ON true
+LEFT JOIN oracle_example.telemetry_status t ON t.vin = v.vin`
+
+// ExportVehicles calls each for every VIN matching the filter in VIN order. Rows are read from the database as they
+// are consumed, so the export isn't held in memory. An error returned by each stops the export.
+func (ds *Vehicle) ExportVehicles(ctx context.Context, filter VehicleExportFilter, each func(*VehicleExport) error) error {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.MinStatus != nil {
+		where("v.onboarding_status >= $%d", *filter.MinStatus)
+	}
+	if filter.MaxStatus != nil {
+		where("v.onboarding_status < $%d", *filter.MaxStatus)
+	}
+	if filter.From != nil {
+		where("h.changed_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where("h.changed_at < $%d", *filter.To)
+	}
+
+	query := vehicleExportQuery
+	if len(conditions) > 0 {
+		query += "\nWHERE " + strings.Join(conditions, " AND ")
+	}
+	query += "\nORDER BY v.vin"
+
+	rows, err := queries.Raw(query, args...).QueryContext(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to export VINs")
+		return fmt.Errorf("failed to export VINs: %w", err)
+	}
+	defer rows.Close() // nolint
+
+	for rows.Next() {
+		var v VehicleExport
+		if err := rows.Scan(&v.Vin, &v.VehicleTokenID, &v.SyntheticTokenID, &v.WalletIndex, &v.OwnerAddress,
+			&v.ExternalID, &v.ConnectionStatus, &v.DisconnectionStatus, &v.OnboardingStatus, &v.DeviceDefinitionID,
+			&v.OperationErrorCode, &v.OperationErrorType, &v.OperationErrorDescription,
+			&v.StatusChangedAt, &v.LastReceivedAt, &v.LastForwardedAt, &v.LastError); err != nil {
+			return fmt.Errorf("failed to read exported VIN: %w", err)
+		}
+		if err := each(&v); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to export VINs")
+		return fmt.Errorf("failed to export VINs: %w", err)
+	}
+
+	return nil
+}
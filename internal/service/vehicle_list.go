@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"strconv"
+	"strings"
+)
+
+// Fields vehicles can be sorted by, the VIN breaks ties
+const (
+	VehicleSortVin              = "vin"
+	VehicleSortOnboardingStatus = "onboardingStatus"
+	VehicleSortMake             = "make"
+	VehicleSortModel            = "model"
+	VehicleSortYear             = "year"
+)
+
+var ErrInvalidVehicleCursor = errors.New("invalid vehicle cursor")
+
+// vehicleSortExpressions missing values are sorted as empty, so they can be compared with the cursor
+var vehicleSortExpressions = map[string]string{
+	VehicleSortVin:              dbmodels.VinColumns.Vin,
+	VehicleSortOnboardingStatus: dbmodels.VinColumns.OnboardingStatus,
+	VehicleSortMake:             "coalesce(" + dbmodels.VinColumns.DefinitionMake + ", '')",
+	VehicleSortModel:            "coalesce(" + dbmodels.VinColumns.DefinitionModel + ", '')",
+	VehicleSortYear:             "coalesce(" + dbmodels.VinColumns.DefinitionYear + ", 0)",
+}
+
+// IsVehicleSort tells whether vehicles can be sorted by the field
+func IsVehicleSort(sort string) bool {
+	_, ok := vehicleSortExpressions[sort]
+	return ok
+}
+
+// VehicleListFilter narrows down the VINs of an owner returned by ListVehicles, empty fields are not filtered on.
+type VehicleListFilter struct {
+	// VINs are listed by their stored owner, the one of minted vehicles follows transfers, see SetVinOwner
+	OwnerAddress     string
+	ConnectionStatus string
+	// onboarding statuses from MinStatus up to but excluding MaxStatus
+	MinStatus *int
+	MaxStatus *int
+	Make      string
+	Model     string
+	Year      int
+	HasError  *bool
+	Sort      string
+	Desc      bool
+	// encoded cursor of the last VIN of the previous page, empty for the first page
+	Cursor string
+	Limit  int
+}
+
+// vehicleCursor position of a VIN in the sort order of the listing
+type vehicleCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	Vin   string `json:"vin"`
+}
+
+// ListVehicles retrieves a page of the VINs of an owner matching the filter. The cursor of the next page is empty
+// when there are no more VINs.
+func (ds *Vehicle) ListVehicles(ctx context.Context, filter VehicleListFilter) (dbmodels.VinSlice, string, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = VehicleSortVin
+	}
+	expression, ok := vehicleSortExpressions[sort]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown vehicle sort %s", sort)
+	}
+
+	direction, comparison := "asc", ">"
+	if filter.Desc {
+		direction, comparison = "desc", "<"
+	}
+
+	mods := []qm.QueryMod{
+		// owners backfilled from onboarding jobs are lowercase, the others checksummed
+		qm.Where("lower("+dbmodels.VinColumns.OwnerAddress+") = lower(?)", filter.OwnerAddress),
+		qm.OrderBy(expression + " " + direction + ", " + dbmodels.VinColumns.Vin + " " + direction),
+		// one more to know if there is a next page
+		qm.Limit(filter.Limit + 1),
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := decodeVehicleCursor(filter.Cursor)
+		if err != nil || cursor.Sort != sort || cursor.Desc != filter.Desc {
+			return nil, "", ErrInvalidVehicleCursor
+		}
+		mods = append(mods, qm.Where("("+expression+", "+dbmodels.VinColumns.Vin+") "+comparison+" (?, ?)", cursor.Value, cursor.Vin))
+	}
+	if filter.ConnectionStatus != "" {
+		mods = append(mods, dbmodels.VinWhere.ConnectionStatus.EQ(null.StringFrom(filter.ConnectionStatus)))
+	}
+	if filter.MinStatus != nil {
+		mods = append(mods, dbmodels.VinWhere.OnboardingStatus.GTE(*filter.MinStatus))
+	}
+	if filter.MaxStatus != nil {
+		mods = append(mods, dbmodels.VinWhere.OnboardingStatus.LT(*filter.MaxStatus))
+	}
+	if filter.Make != "" {
+		mods = append(mods, qm.Where("lower("+dbmodels.VinColumns.DefinitionMake+") = lower(?)", filter.Make))
+	}
+	if filter.Model != "" {
+		mods = append(mods, qm.Where("lower("+dbmodels.VinColumns.DefinitionModel+") = lower(?)", filter.Model))
+	}
+	if filter.Year != 0 {
+		mods = append(mods, dbmodels.VinWhere.DefinitionYear.EQ(null.IntFrom(filter.Year)))
+	}
+	if filter.HasError != nil {
+		// failed statuses end with 2, see onboarding.IsFailure
+		hasError := "(" + dbmodels.VinColumns.OperationErrorCode + " is not null or " + dbmodels.VinColumns.OnboardingStatus + " % 10 = 2)"
+		if *filter.HasError {
+			mods = append(mods, qm.Where(hasError))
+		} else {
+			mods = append(mods, qm.Where("not "+hasError))
+		}
+	}
+
+	vins, err := dbmodels.Vins(mods...).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to list VINs")
+		return nil, "", fmt.Errorf("failed to list VINs: %w", err)
+	}
+
+	if len(vins) <= filter.Limit {
+		return vins, "", nil
+	}
+
+	vins = vins[:filter.Limit]
+	last := vins[len(vins)-1]
+
+	return vins, encodeVehicleCursor(vehicleCursor{
+		Sort:  sort,
+		Desc:  filter.Desc,
+		Value: vehicleSortValue(last, sort),
+		Vin:   last.Vin,
+	}), nil
+}
+
+// vehicleSortValue value of the sorted field of the VIN, as compared by vehicleSortExpressions
+func vehicleSortValue(vin *dbmodels.Vin, sort string) string {
+	switch sort {
+	case VehicleSortOnboardingStatus:
+		return strconv.Itoa(vin.OnboardingStatus)
+	case VehicleSortMake:
+		return vin.DefinitionMake.String
+	case VehicleSortModel:
+		return vin.DefinitionModel.String
+	case VehicleSortYear:
+		return strconv.Itoa(vin.DefinitionYear.Int)
+	default:
+		return vin.Vin
+	}
+}
+
+func encodeVehicleCursor(cursor vehicleCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeVehicleCursor(encoded string) (*vehicleCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor vehicleCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"strconv"
+	"strings"
+)
+
+// Kinds of identifiers a vehicle can be found by
+const (
+	IdentifierVin                    = "vin"
+	IdentifierTokenID                = "tokenId"
+	IdentifierSyntheticTokenID       = "syntheticTokenId"
+	IdentifierExternalID             = "externalId"
+	IdentifierSyntheticDeviceAddress = "syntheticDeviceAddress"
+)
+
+// Identifiers kinds in the order they are looked up
+var Identifiers = []string{
+	IdentifierVin,
+	IdentifierTokenID,
+	IdentifierSyntheticTokenID,
+	IdentifierExternalID,
+	IdentifierSyntheticDeviceAddress,
+}
+
+var ErrInvalidIdentifier = errors.New("invalid vehicle identifier")
+
+// FindVehicle resolves an identifier of the kind to the VIN record. The synthetic device address needs the SD wallets
+// the addresses are derived from.
+func (ds *Vehicle) FindVehicle(ctx context.Context, ws SDWalletsAPI, kind, value string) (*dbmodels.Vin, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, ErrInvalidIdentifier
+	}
+
+	switch kind {
+	case IdentifierVin:
+		return ds.GetVehicleByVin(ctx, strings.ToUpper(value))
+	case IdentifierTokenID, IdentifierSyntheticTokenID:
+		tokenID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || tokenID <= 0 {
+			return nil, ErrInvalidIdentifier
+		}
+		if kind == IdentifierTokenID {
+			return ds.GetVehicleByTokenID(ctx, tokenID)
+		}
+		return ds.GetVehicleBySyntheticTokenID(ctx, tokenID)
+	case IdentifierExternalID:
+		return ds.GetVehicleByExternalID(ctx, value)
+	case IdentifierSyntheticDeviceAddress:
+		if !common.IsHexAddress(value) {
+			return nil, ErrInvalidIdentifier
+		}
+		return ds.GetVehicleBySyntheticDeviceAddress(ctx, ws, common.HexToAddress(value))
+	default:
+		return nil, ErrInvalidIdentifier
+	}
+}
+
+// GetVehicleByTokenID retrieves a vehicle by its vehicle token ID.
+func (ds *Vehicle) GetVehicleByTokenID(ctx context.Context, tokenID int64) (*dbmodels.Vin, error) {
+	return ds.getVehicle(ctx, fmt.Sprintf("vehicle token %d", tokenID),
+		dbmodels.VinWhere.VehicleTokenID.EQ(null.Int64From(tokenID)))
+}
+
+// GetVehicleBySyntheticTokenID retrieves a vehicle by the token ID of its synthetic device.
+func (ds *Vehicle) GetVehicleBySyntheticTokenID(ctx context.Context, tokenID int64) (*dbmodels.Vin, error) {
+	return ds.getVehicle(ctx, fmt.Sprintf("synthetic token %d", tokenID),
+		dbmodels.VinWhere.SyntheticTokenID.EQ(null.Int64From(tokenID)))
+}
+
+// GetVehicleBySyntheticDeviceAddress retrieves a vehicle by the address of its synthetic device. VINs minted before the
+// address was stored are filled in first when it isn't found.
+func (ds *Vehicle) GetVehicleBySyntheticDeviceAddress(ctx context.Context, ws SDWalletsAPI, address common.Address) (*dbmodels.Vin, error) {
+	identifier := "synthetic device " + address.Hex()
+	vin, err := ds.getVehicle(ctx, identifier, dbmodels.VinWhere.SDAddress.EQ(null.StringFrom(address.Hex())))
+	if !errors.Is(err, ErrVehicleNotFound) {
+		return vin, err
+	}
+
+	filled, err := ds.BackfillSyntheticDeviceAddresses(ctx, ws)
+	if err != nil || filled == 0 {
+		return nil, ErrVehicleNotFound
+	}
+
+	return ds.getVehicle(ctx, identifier, dbmodels.VinWhere.SDAddress.EQ(null.StringFrom(address.Hex())))
+}
+
+// BackfillSyntheticDeviceAddresses stores the synthetic device address of VINs minted before it was stored, derived
+// from their wallet index. Returns the number of VINs filled.
+func (ds *Vehicle) BackfillSyntheticDeviceAddresses(ctx context.Context, ws SDWalletsAPI) (int, error) {
+	vins, err := dbmodels.Vins(
+		qm.Select(dbmodels.VinColumns.Vin, dbmodels.VinColumns.WalletIndex),
+		dbmodels.VinWhere.WalletIndex.IsNotNull(),
+		dbmodels.VinWhere.SDAddress.IsNull(),
+	).All(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		ds.logger.Error().Err(err).Msg("Failed to get VINs without synthetic device address")
+		return 0, fmt.Errorf("failed to get VINs without synthetic device address: %w", err)
+	}
+
+	filled := 0
+	for _, vin := range vins {
+		sdAddress, err := ws.GetAddress(uint32(vin.WalletIndex.Int64))
+		if err != nil {
+			return filled, fmt.Errorf("failed to get synthetic device address of %s: %w", vin.Vin, err)
+		}
+
+		// the wallet index may have changed meanwhile, eg. when the synthetic device was burned
+		_, err = dbmodels.Vins(
+			dbmodels.VinWhere.Vin.EQ(vin.Vin),
+			dbmodels.VinWhere.WalletIndex.EQ(vin.WalletIndex),
+			dbmodels.VinWhere.SDAddress.IsNull(),
+		).UpdateAll(ctx, ds.pdb.DBS().Writer, dbmodels.M{dbmodels.VinColumns.SDAddress: sdAddress.Hex()})
+		if err != nil {
+			ds.logger.Error().Err(err).Msgf("Failed to store synthetic device address of %s", vin.Vin)
+			return filled, fmt.Errorf("failed to store synthetic device address: %w", err)
+		}
+		filled++
+	}
+
+	return filled, nil
+}
+
+func (ds *Vehicle) getVehicle(ctx context.Context, identifier string, mods ...qm.QueryMod) (*dbmodels.Vin, error) {
+	vin, err := dbmodels.Vins(mods...).One(ctx, ds.pdb.DBS().Reader)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVehicleNotFound
+		}
+		ds.logger.Error().Err(err).Msgf("Failed to get vehicle by %s", identifier)
+		return nil, err
+	}
+
+	return vin, nil
+}
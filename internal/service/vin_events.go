@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+// vinStatusChannel is notified by the vins table trigger whenever the onboarding or connection status changes
+const vinStatusChannel = "vin_status"
+
+const (
+	vinEventsBufferSize     = 16
+	vinEventsReconnectDelay = 5 * time.Second
+)
+
+// VinStatusEvent is a change of the VIN onboarding or connection status
+type VinStatusEvent struct {
+	Vin                       string `json:"vin"`
+	OwnerAddress              string `json:"ownerAddress"`
+	OnboardingStatus          int    `json:"onboardingStatus"`
+	ConnectionStatus          string `json:"connectionStatus"`
+	DisconnectionStatus       string `json:"disconnectionStatus"`
+	OperationErrorType        string `json:"operationErrorType"`
+	OperationErrorCode        string `json:"operationErrorCode"`
+	OperationErrorDescription string `json:"operationErrorDescription"`
+}
+
+// VinEventsHub listens for VIN status changes through Postgres NOTIFY, so changes made by any replica are seen,
+// and fans them out to the subscribers of the VIN owner. Slow subscribers miss events instead of blocking the hub.
+// Channels of the subscribers are closed once the hub stops, so open streams end on shutdown.
+// A nil hub has no events.
+type VinEventsHub struct {
+	pool        *pgxpool.Pool
+	logger      zerolog.Logger
+	subscribers map[common.Address]map[chan VinStatusEvent]struct{}
+	closed      bool
+	m           sync.Mutex
+}
+
+func NewVinEventsHub(pool *pgxpool.Pool, logger zerolog.Logger) *VinEventsHub {
+	return &VinEventsHub{
+		pool:        pool,
+		logger:      logger,
+		subscribers: make(map[common.Address]map[chan VinStatusEvent]struct{}),
+	}
+}
+
+// Subscribe returns a channel with status changes of VINs owned by the wallet, and a function to unsubscribe.
+// The channel is closed when the hub stops.
+func (h *VinEventsHub) Subscribe(owner common.Address) (<-chan VinStatusEvent, func()) {
+	ch := make(chan VinStatusEvent, vinEventsBufferSize)
+	if h == nil {
+		return ch, func() {}
+	}
+
+	h.m.Lock()
+	if h.closed {
+		h.m.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if _, ok := h.subscribers[owner]; !ok {
+		h.subscribers[owner] = make(map[chan VinStatusEvent]struct{})
+	}
+	h.subscribers[owner][ch] = struct{}{}
+	vinEventsSubscribersGauge.Inc()
+	h.m.Unlock()
+
+	return ch, func() {
+		h.m.Lock()
+		defer h.m.Unlock()
+
+		delete(h.subscribers[owner], ch)
+		if len(h.subscribers[owner]) == 0 {
+			delete(h.subscribers, owner)
+		}
+		vinEventsSubscribersGauge.Dec()
+	}
+}
+
+// Run listens for notifications until the context is done, the connection is re-established after errors.
+// The channels of all subscribers are closed when it returns.
+func (h *VinEventsHub) Run(ctx context.Context) error {
+	defer h.close()
+
+	for {
+		err := h.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		h.logger.Error().Err(err).Msg("VIN status listener failed, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(vinEventsReconnectDelay):
+		}
+	}
+}
+
+func (h *VinEventsHub) listen(ctx context.Context) error {
+	conn, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+vinStatusChannel); err != nil {
+		return err
+	}
+	h.logger.Debug().Msgf("Listening on %s", vinStatusChannel)
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event VinStatusEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			h.logger.Error().Err(err).Str("payload", notification.Payload).Msg("Failed to parse VIN status notification")
+			continue
+		}
+
+		h.publish(event)
+	}
+}
+
+func (h *VinEventsHub) close() {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.closed = true
+	for _, channels := range h.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+	}
+	// unsubscribing afterward still updates the gauge
+	h.subscribers = make(map[common.Address]map[chan VinStatusEvent]struct{})
+}
+
+func (h *VinEventsHub) publish(event VinStatusEvent) {
+	// VINs without recorded owner have nobody to notify
+	if event.OwnerAddress == "" {
+		return
+	}
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	for ch := range h.subscribers[common.HexToAddress(event.OwnerAddress)] {
+		select {
+		case ch <- event:
+		default:
+			droppedVinEventsCntr.Inc()
+		}
+	}
+}
+
+// Prometheus metrics
+var vinEventsSubscribersGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "dimo_oracle_vin_events_subscribers",
+	Help: "Number of open VIN status event streams",
+})
+
+var droppedVinEventsCntr = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "dimo_oracle_dropped_vin_events_total",
+	Help: "Total number of VIN status events not delivered to slow subscribers",
+})
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type VinEventsHubTestSuite struct {
+	suite.Suite
+}
+
+func TestVinEventsHubTestSuite(t *testing.T) {
+	suite.Run(t, new(VinEventsHubTestSuite))
+}
+
+func (s *VinEventsHubTestSuite) TestPublishToOwner() {
+	hub := NewVinEventsHub(nil, zerolog.Nop())
+	owner := common.HexToAddress("0x1A2b3C4d5E6f7a8B9c0D1e2F3a4B5c6D7e8F9a0B")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	events, unsubscribe := hub.Subscribe(owner)
+	otherEvents, unsubscribeOther := hub.Subscribe(other)
+	defer unsubscribeOther()
+
+	// owner address from the DB is not necessarily checksummed
+	hub.publish(VinStatusEvent{Vin: vin, OwnerAddress: "0x1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b", OnboardingStatus: 11})
+	hub.publish(VinStatusEvent{Vin: vin, OnboardingStatus: 12})
+
+	s.Require().Len(events, 1)
+	s.Equal(VinStatusEvent{Vin: vin, OwnerAddress: "0x1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b", OnboardingStatus: 11}, <-events)
+	s.Empty(otherEvents)
+
+	unsubscribe()
+	hub.publish(VinStatusEvent{Vin: vin, OwnerAddress: owner.Hex(), OnboardingStatus: 13})
+	s.Empty(events)
+}
+
+func (s *VinEventsHubTestSuite) TestSlowSubscriber() {
+	hub := NewVinEventsHub(nil, zerolog.Nop())
+	owner := common.HexToAddress("0x1A2b3C4d5E6f7a8B9c0D1e2F3a4B5c6D7e8F9a0B")
+
+	events, unsubscribe := hub.Subscribe(owner)
+	defer unsubscribe()
+
+	for i := 0; i < vinEventsBufferSize*2; i++ {
+		hub.publish(VinStatusEvent{Vin: vin, OwnerAddress: owner.Hex(), OnboardingStatus: i})
+	}
+
+	s.Len(events, vinEventsBufferSize)
+}
+
+func (s *VinEventsHubTestSuite) TestCloseSubscribers() {
+	hub := NewVinEventsHub(nil, zerolog.Nop())
+	owner := common.HexToAddress("0x1A2b3C4d5E6f7a8B9c0D1e2F3a4B5c6D7e8F9a0B")
+
+	events, unsubscribe := hub.Subscribe(owner)
+	defer unsubscribe()
+
+	// when Run returns
+	hub.close()
+
+	_, ok := <-events
+	s.False(ok)
+
+	// streams opened while shutting down end right away
+	late, unsubscribeLate := hub.Subscribe(owner)
+	defer unsubscribeLate()
+	_, ok = <-late
+	s.False(ok)
+}
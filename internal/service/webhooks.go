@@ -0,0 +1,485 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"github.com/DIMO-Network/shared/pkg/db"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/friendsofgo/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Onboarding lifecycle events sent to webhook subscriptions
+const (
+	WebhookEventVehicleVerified     = "vehicle.verified"
+	WebhookEventVehicleConnected    = "vehicle.connected"
+	WebhookEventVehicleMinted       = "vehicle.minted"
+	WebhookEventVehicleDisconnected = "vehicle.disconnected"
+	WebhookEventVehicleDeleted      = "vehicle.deleted"
+)
+
+// WebhookEvents all events a subscription can filter on
+var WebhookEvents = []string{
+	WebhookEventVehicleVerified,
+	WebhookEventVehicleConnected,
+	WebhookEventVehicleMinted,
+	WebhookEventVehicleDisconnected,
+	WebhookEventVehicleDeleted,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	webhookSecretBytes    = 32
+	webhookMaxErrorLength = 512
+)
+
+var (
+	ErrWebhookNotFound          = errors.New("webhook not found")
+	ErrWebhookAddressNotAllowed = errors.New("webhook address not allowed")
+)
+
+// WebhookPayload is the JSON body of every delivery
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// Webhooks stores webhook subscriptions of developers, by client ID, and delivers signed lifecycle events of the VINs
+// submitted through the client to them.
+// A nil service has no subscriptions.
+type Webhooks struct {
+	pdb        *db.Store
+	pool       *pgxpool.Pool
+	logger     *zerolog.Logger
+	httpClient *http.Client
+}
+
+// NewWebhooksService creates a new instance of Webhooks. Deliveries are queued through the pool of the river client,
+// so they are stored in the same transaction as their jobs.
+func NewWebhooksService(pdb *db.Store, pool *pgxpool.Pool, logger *zerolog.Logger, timeout time.Duration) *Webhooks {
+	return &Webhooks{
+		pdb:        pdb,
+		pool:       pool,
+		logger:     logger,
+		httpClient: newWebhookHTTPClient(timeout, IsPublicAddress),
+	}
+}
+
+// newWebhookHTTPClient client which only connects to allowed addresses. They are checked when dialing, after DNS
+// resolution, so a host can't be re-pointed to an internal address after it was validated. Redirects aren't followed.
+func newWebhookHTTPClient(timeout time.Duration, allowed func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !allowed(addrPort.Addr()) {
+				return ErrWebhookAddressNotAllowed
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// no proxy, it would be the only address checked
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// metadata and carrier-grade NAT ranges not covered by the net/netip checks
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("fd00:ec2::254/128"),
+}
+
+// IsPublicAddress tells whether webhooks may be sent to the address, loopback, private, link-local and cloud
+// metadata addresses are rejected
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+		return false
+	}
+
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidateWebhookHost resolves the host of a webhook URL and checks all of its addresses are public
+func ValidateWebhookHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublicAddress(addr) {
+			return ErrWebhookAddressNotAllowed
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host: %w", err)
+	}
+
+	for _, addr := range addrs {
+		if !IsPublicAddress(addr) {
+			return ErrWebhookAddressNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// SignWebhookPayload computes the hex HMAC-SHA256 of "timestamp.body" with the subscription secret.
+// Receivers recompute it from the t= and v1= parts of the signature header to verify a delivery.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateWebhookEvents checks the events of a subscription filter are known
+func ValidateWebhookEvents(events []string) error {
+	for _, event := range events {
+		if !slices.Contains(WebhookEvents, event) {
+			return fmt.Errorf("unknown webhook event %s", event)
+		}
+	}
+	return nil
+}
+
+// WebhookSubscriptionEvents returns the event filter of the subscription, empty means all events
+func WebhookSubscriptionEvents(subscription *dbmodels.WebhookSubscription) []string {
+	if subscription.Events == "" {
+		return []string{}
+	}
+	return strings.Split(subscription.Events, ",")
+}
+
+func isSubscribedTo(subscription *dbmodels.WebhookSubscription, event string) bool {
+	events := WebhookSubscriptionEvents(subscription)
+	return len(events) == 0 || slices.Contains(events, event)
+}
+
+// CreateSubscription stores a new subscription of the developer client ID with a generated secret.
+func (ws *Webhooks) CreateSubscription(ctx context.Context, clientID, url string, events []string) (*dbmodels.WebhookSubscription, error) {
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	subscription := dbmodels.WebhookSubscription{
+		ID:       ksuid.New().String(),
+		ClientID: clientID,
+		URL:      url,
+		Secret:   hex.EncodeToString(secret),
+		Events:   strings.Join(events, ","),
+		Active:   true,
+	}
+
+	if err := subscription.Insert(ctx, ws.pdb.DBS().Writer, boil.Infer()); err != nil {
+		ws.logger.Error().Err(err).Msgf("Failed to insert webhook subscription for %s", clientID)
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// GetSubscriptions retrieves all subscriptions of the developer client ID.
+func (ws *Webhooks) GetSubscriptions(ctx context.Context, clientID string) (dbmodels.WebhookSubscriptionSlice, error) {
+	subscriptions, err := dbmodels.WebhookSubscriptions(
+		dbmodels.WebhookSubscriptionWhere.ClientID.EQ(clientID),
+		qm.OrderBy(dbmodels.WebhookSubscriptionColumns.CreatedAt),
+	).All(ctx, ws.pdb.DBS().Reader)
+	if err != nil {
+		ws.logger.Error().Err(err).Msgf("Failed to get webhook subscriptions for %s", clientID)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscription retrieves a single subscription of the developer client ID.
+func (ws *Webhooks) GetSubscription(ctx context.Context, clientID, id string) (*dbmodels.WebhookSubscription, error) {
+	subscription, err := dbmodels.WebhookSubscriptions(
+		dbmodels.WebhookSubscriptionWhere.ID.EQ(id),
+		dbmodels.WebhookSubscriptionWhere.ClientID.EQ(clientID),
+	).One(ctx, ws.pdb.DBS().Reader)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		ws.logger.Error().Err(err).Msgf("Failed to get webhook subscription %s", id)
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// DeleteSubscription removes a subscription of the developer client ID, its delivery log is kept.
+func (ws *Webhooks) DeleteSubscription(ctx context.Context, clientID, id string) error {
+	subscription, err := ws.GetSubscription(ctx, clientID, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := subscription.Delete(ctx, ws.pdb.DBS().Writer); err != nil {
+		ws.logger.Error().Err(err).Msgf("Failed to delete webhook subscription %s", id)
+		return err
+	}
+
+	return nil
+}
+
+// GetDeliveries retrieves the latest deliveries of a subscription.
+func (ws *Webhooks) GetDeliveries(ctx context.Context, subscriptionID string, limit int) (dbmodels.WebhookDeliverySlice, error) {
+	deliveries, err := dbmodels.WebhookDeliveries(
+		dbmodels.WebhookDeliveryWhere.SubscriptionID.EQ(subscriptionID),
+		qm.OrderBy(dbmodels.WebhookDeliveryColumns.CreatedAt+" DESC"),
+		qm.Limit(limit),
+	).All(ctx, ws.pdb.DBS().Reader)
+	if err != nil {
+		ws.logger.Error().Err(err).Msgf("Failed to get webhook deliveries for %s", subscriptionID)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetDelivery retrieves a single delivery of a subscription.
+func (ws *Webhooks) GetDelivery(ctx context.Context, subscriptionID, deliveryID string) (*dbmodels.WebhookDelivery, error) {
+	delivery, err := dbmodels.WebhookDeliveries(
+		dbmodels.WebhookDeliveryWhere.ID.EQ(deliveryID),
+		dbmodels.WebhookDeliveryWhere.SubscriptionID.EQ(subscriptionID),
+	).One(ctx, ws.pdb.DBS().Reader)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		ws.logger.Error().Err(err).Msgf("Failed to get webhook delivery %s", deliveryID)
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// QueueWebhookDeliveries inserts the jobs sending the deliveries in the transaction which stores them
+type QueueWebhookDeliveries func(ctx context.Context, tx pgx.Tx, deliveries dbmodels.WebhookDeliverySlice) error
+
+// ResetDelivery marks a delivery as pending again and queues it, so it can be redelivered.
+func (ws *Webhooks) ResetDelivery(ctx context.Context, delivery *dbmodels.WebhookDelivery, queue QueueWebhookDeliveries) error {
+	delivery.Status = WebhookDeliveryPending
+	delivery.UpdatedAt = time.Now()
+
+	return pgx.BeginFunc(ctx, ws.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE oracle_example.webhook_deliveries SET status = $2, updated_at = $3 WHERE id = $1`,
+			delivery.ID, delivery.Status, delivery.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to update webhook delivery: %w", err)
+		}
+		return queue(ctx, tx, dbmodels.WebhookDeliverySlice{delivery})
+	})
+}
+
+// CreateDeliveries stores a pending delivery of the event for every active subscription of the developer client ID
+// filtering on it. The deliveries are sent by the webhook delivery worker, queue inserts their jobs in the same
+// transaction, so a delivery is never left pending without a job.
+func (ws *Webhooks) CreateDeliveries(ctx context.Context, clientID, event, vin string, data interface{}, queue QueueWebhookDeliveries) (dbmodels.WebhookDeliverySlice, error) {
+	if ws == nil {
+		return nil, nil
+	}
+
+	subscriptions, err := dbmodels.WebhookSubscriptions(
+		dbmodels.WebhookSubscriptionWhere.ClientID.EQ(clientID),
+		dbmodels.WebhookSubscriptionWhere.Active.EQ(true),
+	).All(ctx, ws.pdb.DBS().Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+
+	payload := WebhookPayload{
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+
+	deliveries := dbmodels.WebhookDeliverySlice{}
+	for _, subscription := range subscriptions {
+		if !isSubscribedTo(subscription, event) {
+			continue
+		}
+
+		delivery := dbmodels.WebhookDelivery{
+			ID:             ksuid.New().String(),
+			SubscriptionID: subscription.ID,
+			Event:          event,
+			Vin:            vin,
+			Status:         WebhookDeliveryPending,
+		}
+		if err := delivery.Payload.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+		}
+
+		deliveries = append(deliveries, &delivery)
+	}
+	if len(deliveries) == 0 {
+		return deliveries, nil
+	}
+
+	err = pgx.BeginFunc(ctx, ws.pool, func(tx pgx.Tx) error {
+		for _, delivery := range deliveries {
+			if _, err := tx.Exec(ctx, `INSERT INTO oracle_example.webhook_deliveries (id, subscription_id, event, vin, payload, status)
+VALUES ($1, $2, $3, $4, $5, $6)`, delivery.ID, delivery.SubscriptionID, delivery.Event, delivery.Vin, string(delivery.Payload.JSON), delivery.Status); err != nil {
+				return fmt.Errorf("failed to insert webhook delivery: %w", err)
+			}
+		}
+		return queue(ctx, tx, deliveries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Deliver posts the delivery payload to the subscription URL and records the result.
+// Non 2xx responses are returned as errors, so the caller can retry.
+func (ws *Webhooks) Deliver(ctx context.Context, deliveryID string) error {
+	delivery, err := dbmodels.FindWebhookDelivery(ctx, ws.pdb.DBS().Reader, deliveryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+
+	subscription, err := dbmodels.FindWebhookSubscription(ctx, ws.pdb.DBS().Reader, delivery.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ws.finishDelivery(ctx, delivery, 0, ErrWebhookNotFound)
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+
+	code, err := ws.post(ctx, subscription, delivery)
+	ws.finishDelivery(ctx, delivery, code, err)
+
+	if err != nil {
+		webhookDeliveriesCntr.WithLabelValues(WebhookDeliveryFailed).Inc()
+		return err
+	}
+
+	webhookDeliveriesCntr.WithLabelValues(WebhookDeliverySucceeded).Inc()
+	return nil
+}
+
+func (ws *Webhooks) post(ctx context.Context, subscription *dbmodels.WebhookSubscription, delivery *dbmodels.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+	signature := SignWebhookPayload(subscription.Secret, timestamp, delivery.Payload.JSON)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload.JSON))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookSignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, signature))
+
+	res, err := ws.httpClient.Do(req)
+	if err != nil {
+		// the error is shown to the subscriber, it must not tell what the host resolved to
+		if errors.Is(err, ErrWebhookAddressNotAllowed) {
+			return 0, ErrWebhookAddressNotAllowed
+		}
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer res.Body.Close() // nolint
+
+	// the response body isn't stored, it could be anything the receiver answers with
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+func (ws *Webhooks) finishDelivery(ctx context.Context, delivery *dbmodels.WebhookDelivery, code int, deliveryErr error) {
+	delivery.Attempts++
+	delivery.UpdatedAt = time.Now()
+	delivery.ResponseCode = null.NewInt(code, code != 0)
+
+	if deliveryErr != nil {
+		message := deliveryErr.Error()
+		if len(message) > webhookMaxErrorLength {
+			message = message[:webhookMaxErrorLength]
+		}
+		delivery.Status = WebhookDeliveryFailed
+		delivery.LastError = null.StringFrom(message)
+	} else {
+		delivery.Status = WebhookDeliverySucceeded
+		delivery.LastError = null.String{}
+		delivery.DeliveredAt = null.TimeFrom(delivery.UpdatedAt)
+	}
+
+	if _, err := delivery.Update(ctx, ws.pdb.DBS().Writer, boil.Infer()); err != nil {
+		ws.logger.Error().Err(err).Msgf("Failed to update webhook delivery %s", delivery.ID)
+	}
+}
+
+// Prometheus metrics
+var webhookDeliveriesCntr = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "dimo_oracle_webhook_deliveries_total",
+	Help: "Total number of webhook delivery attempts by result",
+}, []string{"result"})
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	dbmodels "github.com/DIMO-Network/volteras-oracle/internal/db/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"github.com/volatiletech/null/v8"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type WebhooksTestSuite struct {
+	suite.Suite
+}
+
+func TestWebhooksTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhooksTestSuite))
+}
+
+func (s *WebhooksTestSuite) TestSignWebhookPayload() {
+	signature := SignWebhookPayload("secret", 1700000000, []byte(`{"event":"vehicle.minted"}`))
+	s.Equal("c79e614ce494676cd4d8ea039e56843f5cd3ecc1354973523b464de3c1d06dba", signature)
+
+	s.NotEqual(signature, SignWebhookPayload("secret", 1700000001, []byte(`{"event":"vehicle.minted"}`)))
+	s.NotEqual(signature, SignWebhookPayload("other", 1700000000, []byte(`{"event":"vehicle.minted"}`)))
+}
+
+func (s *WebhooksTestSuite) TestValidateWebhookEvents() {
+	s.NoError(ValidateWebhookEvents(nil))
+	s.NoError(ValidateWebhookEvents([]string{WebhookEventVehicleMinted, WebhookEventVehicleDeleted}))
+	s.Error(ValidateWebhookEvents([]string{WebhookEventVehicleMinted, "vehicle.unknown"}))
+}
+
+func (s *WebhooksTestSuite) TestIsSubscribedTo() {
+	all := &dbmodels.WebhookSubscription{Events: ""}
+	s.True(isSubscribedTo(all, WebhookEventVehicleVerified))
+	s.Empty(WebhookSubscriptionEvents(all))
+
+	filtered := &dbmodels.WebhookSubscription{Events: WebhookEventVehicleMinted + "," + WebhookEventVehicleDeleted}
+	s.True(isSubscribedTo(filtered, WebhookEventVehicleMinted))
+	s.True(isSubscribedTo(filtered, WebhookEventVehicleDeleted))
+	s.False(isSubscribedTo(filtered, WebhookEventVehicleVerified))
+}
+
+func (s *WebhooksTestSuite) TestPost() {
+	payload := []byte(`{"event":"vehicle.minted","data":{"vin":"1HGCM82633A004352"}}`)
+	status := http.StatusOK
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("receiver says no"))
+	}))
+	defer server.Close()
+
+	logger := zerolog.Nop()
+	ws := NewWebhooksService(nil, nil, &logger, time.Second)
+	// the test server listens on loopback
+	ws.httpClient = newWebhookHTTPClient(time.Second, func(netip.Addr) bool { return true })
+	subscription := &dbmodels.WebhookSubscription{ID: "sub", URL: server.URL, Secret: "secret"}
+	delivery := &dbmodels.WebhookDelivery{ID: "delivery", Event: WebhookEventVehicleMinted, Payload: null.JSONFrom(payload)}
+
+	code, err := ws.post(context.Background(), subscription, delivery)
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, code)
+	s.Equal(payload, body)
+	s.Equal(WebhookEventVehicleMinted, received.Header.Get(WebhookEventHeader))
+	s.Equal("delivery", received.Header.Get(WebhookDeliveryHeader))
+
+	// the receiver must be able to verify the signature from the header alone
+	parts := strings.Split(received.Header.Get(WebhookSignatureHeader), ",")
+	s.Require().Len(parts, 2)
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "t="), 10, 64)
+	s.Require().NoError(err)
+	s.Equal("v1="+SignWebhookPayload("secret", timestamp, body), parts[1])
+
+	status = http.StatusServiceUnavailable
+	code, err = ws.post(context.Background(), subscription, delivery)
+	s.Require().Error(err)
+	s.Equal(http.StatusServiceUnavailable, code)
+	// response bodies are never shown to the subscriber
+	s.NotContains(err.Error(), "receiver says no")
+
+	// redirects are not followed
+	status = http.StatusFound
+	code, err = ws.post(context.Background(), subscription, delivery)
+	s.Require().Error(err)
+	s.Equal(http.StatusFound, code)
+}
+
+func (s *WebhooksTestSuite) TestPostInternalAddress() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Fail("internal address must not be called")
+	}))
+	defer server.Close()
+
+	logger := zerolog.Nop()
+	ws := NewWebhooksService(nil, nil, &logger, time.Second)
+	subscription := &dbmodels.WebhookSubscription{ID: "sub", URL: server.URL, Secret: "secret"}
+	delivery := &dbmodels.WebhookDelivery{ID: "delivery", Event: WebhookEventVehicleMinted, Payload: null.JSONFrom([]byte(`{}`))}
+
+	_, err := ws.post(context.Background(), subscription, delivery)
+	s.ErrorIs(err, ErrWebhookAddressNotAllowed)
+	// doesn't tell what the host resolved to
+	s.Equal(ErrWebhookAddressNotAllowed.Error(), err.Error())
+}
+
+func (s *WebhooksTestSuite) TestIsPublicAddress() {
+	for address, public := range map[string]bool{
+		"8.8.8.8":            true,
+		"2606:4700::1111":    true,
+		"127.0.0.1":          false,
+		"::1":                false,
+		"10.1.2.3":           false,
+		"172.16.0.1":         false,
+		"192.168.1.1":        false,
+		"169.254.169.254":    false,
+		"100.100.100.200":    false,
+		"0.0.0.0":            false,
+		"::ffff:127.0.0.1":   false,
+		"fd00:ec2::254":      false,
+		"fe80::1":            false,
+		"224.0.0.1":          false,
+		"::ffff:169.254.1.1": false,
+	} {
+		s.Equal(public, IsPublicAddress(netip.MustParseAddr(address)), address)
+	}
+}
+
+func (s *WebhooksTestSuite) TestValidateWebhookHost() {
+	s.NoError(ValidateWebhookHost(context.Background(), "8.8.8.8"))
+	s.ErrorIs(ValidateWebhookHost(context.Background(), "169.254.169.254"), ErrWebhookAddressNotAllowed)
+	s.ErrorIs(ValidateWebhookHost(context.Background(), "localhost"), ErrWebhookAddressNotAllowed)
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// metadata key of the trace context of the job
+const riverMetadataKey = "otel"
+
+// RiverMiddleware stores the trace context of the inserting request in the job metadata and continues the trace
+// when the job is worked, so the steps of an onboarding are spans of the request that started it.
+type RiverMiddleware struct {
+	river.MiddlewareDefaults
+}
+
+func (m *RiverMiddleware) InsertMany(ctx context.Context, manyParams []*rivertype.JobInsertParams, doInner func(context.Context) ([]*rivertype.JobInsertResult, error)) ([]*rivertype.JobInsertResult, error) {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return doInner(ctx)
+	}
+
+	for _, params := range manyParams {
+		metadata := map[string]any{}
+		if len(params.Metadata) > 0 {
+			if err := json.Unmarshal(params.Metadata, &metadata); err != nil {
+				return nil, err
+			}
+		}
+		metadata[riverMetadataKey] = carrier
+
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, err
+		}
+		params.Metadata = encoded
+	}
+
+	return doInner(ctx)
+}
+
+func (m *RiverMiddleware) Work(ctx context.Context, job *rivertype.JobRow, doInner func(context.Context) error) error {
+	var metadata struct {
+		Otel propagation.MapCarrier `json:"otel"`
+	}
+	if len(job.Metadata) > 0 {
+		// jobs without a trace context start a new trace
+		_ = json.Unmarshal(job.Metadata, &metadata)
+	}
+	if metadata.Otel != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadata.Otel)
+	}
+
+	ctx, span := Tracer().Start(ctx, "river.work "+job.Kind,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.Int64("river.job.id", job.ID),
+			attribute.String("river.job.kind", job.Kind),
+			attribute.String("river.job.queue", job.Queue),
+			attribute.Int("river.job.attempt", job.Attempt),
+		),
+	)
+	err := doInner(ctx)
+	End(span, err)
+
+	return err
+}
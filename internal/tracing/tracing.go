@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/DIMO-Network/volteras-oracle/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"os"
+)
+
+// Exporters of the spans
+const (
+	ExporterNone   = ""
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Peer services of the client spans
+const (
+	PeerIdentityAPI          = "identity-api"
+	PeerDeviceDefinitionsAPI = "device-definitions-api"
+	PeerDimoNode             = "dimo-node"
+	PeerChain                = "chain"
+	PeerVendor               = "vendor"
+)
+
+const (
+	serviceName = "volteras-oracle"
+	tracerName  = "github.com/DIMO-Network/volteras-oracle"
+)
+
+// Setup registers the tracer provider with the configured exporter and the W3C trace context propagator. Spans are
+// dropped when no exporter is configured, but the context is still propagated. The returned function flushes the
+// remaining spans on shutdown.
+func Setup(ctx context.Context, settings *config.Settings) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch settings.TracingExporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if settings.TracingOTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(settings.TracingOTLPEndpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", settings.TracingExporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sampleRatio := settings.TracingSampleRatio
+	if sampleRatio <= 0 {
+		sampleRatio = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.DeploymentEnvironment(settings.Environment),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer of the oracle spans, uses the provider registered by Setup
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartClientSpan starts the span of a call to another service, ended with End
+func StartClientSpan(ctx context.Context, peer, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.PeerService(peer))
+	return Tracer().Start(ctx, peer+" "+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// End ends the span, marking it failed when the operation returned an error
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// VIN attribute, to find the spans of a vehicle
+func VIN(vin string) attribute.KeyValue {
+	return attribute.String("vin", vin)
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/friendsofgo/errors"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+type TracingTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	recorder *tracetest.SpanRecorder
+}
+
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
+
+func (s *TracingTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+func (s *TracingTestSuite) TestRiverMiddleware() {
+	middleware := &RiverMiddleware{}
+
+	ctx, parent := Tracer().Start(s.ctx, "request")
+	manyParams := []*rivertype.JobInsertParams{
+		{Kind: "verify"},
+		{Kind: "onboarding", Metadata: []byte(`{"source":"admin"}`)},
+	}
+	_, err := middleware.InsertMany(ctx, manyParams, func(context.Context) ([]*rivertype.JobInsertResult, error) {
+		return nil, nil
+	})
+	s.Require().NoError(err)
+	parent.End()
+
+	var metadata map[string]any
+	s.Require().NoError(json.Unmarshal(manyParams[1].Metadata, &metadata))
+	s.Equal("admin", metadata["source"])
+	s.Contains(metadata, riverMetadataKey)
+
+	var workedCtx context.Context
+	err = middleware.Work(s.ctx, &rivertype.JobRow{ID: 1, Kind: "verify", Metadata: manyParams[0].Metadata}, func(ctx context.Context) error {
+		workedCtx = ctx
+		return errors.New("decoding failed")
+	})
+	s.Error(err)
+
+	// the job span continues the trace of the request
+	workSpan := trace.SpanContextFromContext(workedCtx)
+	s.Equal(parent.SpanContext().TraceID(), workSpan.TraceID())
+
+	spans := s.recorder.Ended()
+	s.Require().Len(spans, 2)
+	s.Equal("river.work verify", spans[1].Name())
+	s.Equal(parent.SpanContext().SpanID(), spans[1].Parent().SpanID())
+	s.Equal(codes.Error, spans[1].Status().Code)
+}
+
+func (s *TracingTestSuite) TestRiverMiddlewareWithoutTrace() {
+	middleware := &RiverMiddleware{}
+
+	manyParams := []*rivertype.JobInsertParams{{Kind: "idempotency_keys_cleanup"}}
+	_, err := middleware.InsertMany(s.ctx, manyParams, func(context.Context) ([]*rivertype.JobInsertResult, error) {
+		return nil, nil
+	})
+	s.Require().NoError(err)
+	s.Nil(manyParams[0].Metadata)
+
+	err = middleware.Work(s.ctx, &rivertype.JobRow{ID: 2, Kind: "idempotency_keys_cleanup", Metadata: []byte(`{}`)}, func(context.Context) error {
+		return nil
+	})
+	s.NoError(err)
+
+	spans := s.recorder.Ended()
+	s.Require().Len(spans, 1)
+	s.False(spans[0].Parent().IsValid())
+}
+
+func (s *TracingTestSuite) TestClientSpan() {
+	_, span := StartClientSpan(s.ctx, PeerIdentityAPI, "vehicle", VIN("1HGCM82633A004352"))
+	End(span, nil)
+
+	spans := s.recorder.Ended()
+	s.Require().Len(spans, 1)
+	s.Equal("identity-api vehicle", spans[0].Name())
+	s.Equal(trace.SpanKindClient, spans[0].SpanKind())
+	s.Equal(codes.Unset, spans[0].Status().Code)
+}